@@ -16,6 +16,12 @@ type SchemaCacheEntry struct {
 	ReferenceSchema string // String version of RenderedInline
 	RenderedJSON    []byte
 	CompiledSchema  *jsonschema.Schema
+
+	// RequestSchema and ResponseSchema are compiled for request and response bodies, in which readOnly and
+	// writeOnly properties (respectively) are never required. They are the CompiledSchema when no property is
+	// affected, and nil until the schema has been used in that direction.
+	RequestSchema  *jsonschema.Schema
+	ResponseSchema *jsonschema.Schema
 }
 
 // SchemaCache defines the interface for schema caching implementations.
@@ -1,8 +1,14 @@
 package config
 
 import (
+	"crypto/x509"
+	"net/http"
+	"time"
+
 	"github.com/santhosh-tekuri/jsonschema/v6"
 
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
 	"github.com/pb33f/libopenapi-validator/cache"
 )
 
@@ -15,6 +21,148 @@ type RegexCache interface {
 	Store(key, value any)              // Set a compiled regex to the cache
 }
 
+// PathRouter can be set to replace the linear scan of the document paths with a compiled routing index.
+// paths.NewRouter builds one, the validator builds and shares one between all of its validators automatically.
+type PathRouter interface {
+	// FindPathItem returns the path item that matches the request. Routers only fill in the fields of the match
+	// they know about, so fields can be added to PathMatch without breaking existing routers.
+	FindPathItem(request *http.Request) PathMatch
+}
+
+// PathMatch is the path item a PathRouter found for a request.
+type PathMatch struct {
+	// PathItem is the path item that matches the request, nil when no path matched.
+	PathItem *v3.PathItem
+
+	// PathValue is the path template of the path item, as it appears in the document.
+	PathValue string
+
+	// HasOperation is true when the path item defines an operation for the request method.
+	HasOperation bool
+
+	// Ambiguous are the other path templates that match the request, and are just as specific as PathValue.
+	Ambiguous []string
+}
+
+// DefaultMultipartMemoryLimit is the default for ValidationOptions.MultipartMemoryLimit, the same as the limit
+// net/http uses when parsing multipart forms.
+const DefaultMultipartMemoryLimit int64 = 32 << 20
+
+// DefaultStrictAllowedHeaders are the headers strict mode allows by default, even though operations do not declare
+// them: tracing and correlation headers, and the headers added by proxies. A trailing asterisk matches any header
+// that starts with the name.
+var DefaultStrictAllowedHeaders = []string{
+	"Traceparent",
+	"Tracestate",
+	"Baggage",
+	"B3",
+	"X-B3-*",
+	"Uber-Trace-Id",
+	"X-Amzn-Trace-Id",
+	"X-Cloud-Trace-Context",
+	"X-Request-Id",
+	"X-Correlation-Id",
+	"X-Forwarded-*",
+	"X-Real-Ip",
+	"X-Envoy-*",
+}
+
+// TokenIntrospector checks the bearer token sent for an OAuth2 or OpenID Connect security scheme, for example
+// against the introspection endpoint (RFC 7662) of the authorization server, and returns the scopes granted to it.
+// An error means the token is not active: it is unknown, has expired or has been revoked.
+type TokenIntrospector interface {
+	Introspect(request *http.Request, schemeName string, scheme *v3.SecurityScheme, token string) ([]string, error)
+}
+
+// TokenIntrospectorFunc adapts a function into a TokenIntrospector.
+type TokenIntrospectorFunc func(request *http.Request, schemeName string, scheme *v3.SecurityScheme,
+	token string) ([]string, error)
+
+// Introspect calls the function.
+func (f TokenIntrospectorFunc) Introspect(request *http.Request, schemeName string, scheme *v3.SecurityScheme,
+	token string,
+) ([]string, error) {
+	return f(request, schemeName, scheme, token)
+}
+
+// JWTKeyProvider returns the keys that may have signed a JWT bearer token, with the key ID (if any) and signing
+// algorithm of its header: HMAC secrets ([]byte), *rsa.PublicKey, *ecdsa.PublicKey or ed25519.PublicKey keys. A
+// JSON Web Key Set (see jwt.LoadJWKS) is a key provider.
+type JWTKeyProvider interface {
+	KeysFor(request *http.Request, schemeName string, scheme *v3.SecurityScheme, kid, alg string) ([]any, error)
+}
+
+// Credentials are the credentials of an HTTP 'Basic' or 'Digest' Authorization header. Basic credentials have a
+// username and a password, Digest credentials have a username and the parameters of the header (realm, nonce, uri,
+// response, etc.), the password is not sent.
+type Credentials struct {
+	Scheme     string // "basic" or "digest"
+	Username   string
+	Password   string
+	Parameters map[string]string
+}
+
+// CredentialVerifier authenticates the credentials sent for an HTTP 'basic' or 'digest' security scheme, once their
+// format has been checked. An error means the credentials are not valid.
+type CredentialVerifier interface {
+	VerifyCredentials(request *http.Request, schemeName string, scheme *v3.SecurityScheme,
+		credentials *Credentials) error
+}
+
+// CredentialVerifierFunc adapts a function into a CredentialVerifier.
+type CredentialVerifierFunc func(request *http.Request, schemeName string, scheme *v3.SecurityScheme,
+	credentials *Credentials) error
+
+// VerifyCredentials calls the function.
+func (f CredentialVerifierFunc) VerifyCredentials(request *http.Request, schemeName string,
+	scheme *v3.SecurityScheme, credentials *Credentials,
+) error {
+	return f(request, schemeName, scheme, credentials)
+}
+
+// ClientCertificateVerifier checks the client certificate of a request for a 'mutualTLS' security scheme, for example
+// its subject, subject alternative names or issuer. The certificate has already been verified by the TLS handshake,
+// chains are the verified chains that lead from it to a trusted root. An error means the certificate is not
+// accepted for the scheme.
+type ClientCertificateVerifier interface {
+	VerifyClientCertificate(request *http.Request, schemeName string, scheme *v3.SecurityScheme,
+		certificate *x509.Certificate, chains [][]*x509.Certificate) error
+}
+
+// ClientCertificateVerifierFunc adapts a function into a ClientCertificateVerifier.
+type ClientCertificateVerifierFunc func(request *http.Request, schemeName string, scheme *v3.SecurityScheme,
+	certificate *x509.Certificate, chains [][]*x509.Certificate) error
+
+// VerifyClientCertificate calls the function.
+func (f ClientCertificateVerifierFunc) VerifyClientCertificate(request *http.Request, schemeName string,
+	scheme *v3.SecurityScheme, certificate *x509.Certificate, chains [][]*x509.Certificate,
+) error {
+	return f(request, schemeName, scheme, certificate, chains)
+}
+
+// StrictPropertiesScope selects the bodies whose object schemas are closed by strict additionalProperties.
+type StrictPropertiesScope int
+
+const (
+	StrictPropertiesOff       StrictPropertiesScope = iota // object schemas are left as they are
+	StrictPropertiesAll                                    // request and response bodies
+	StrictPropertiesRequests                               // request bodies only
+	StrictPropertiesResponses                              // response bodies only
+)
+
+// DeprecationWarning describes an element of the contract that is marked as deprecated, and was used by a request
+// or a response. Deprecated elements do not fail validation, they are reported to the DeprecationHandler.
+type DeprecationWarning struct {
+	In       string // Where the element was found, e.g. "response header"
+	Name     string // The name of the element
+	Message  string
+	SpecLine int
+	SpecCol  int
+}
+
+// DeprecationHandler receives a warning for every deprecated element of the contract used by a request or response.
+type DeprecationHandler func(request *http.Request, warning *DeprecationWarning)
+
 // ValidationOptions A container for validation configuration.
 //
 // Generally fluent With... style functions are used to establish the desired behavior.
@@ -28,6 +176,62 @@ type ValidationOptions struct {
 	AllowScalarCoercion bool // Enable string->boolean/number coercion
 	Formats             map[string]func(v any) error
 	SchemaCache         cache.SchemaCache // Optional cache for compiled schemas
+	PathRouter          PathRouter        // Optional compiled routing index used to locate path items
+
+	// StrictServerMatching requires the scheme and host of a request to match one of the servers that apply to the
+	// operation, as well as the base path.
+	StrictServerMatching bool
+
+	// AmbiguousPathErrors reports a request that matches more than one equally specific path template as an error
+	// (default: true). When it is off, the first of those templates in the document is used.
+	AmbiguousPathErrors bool
+
+	// MultipartMemoryLimit caps the number of bytes of a multipart/form-data request body read into memory for
+	// validation. A larger body is reported as invalid without being read any further.
+	MultipartMemoryLimit int64
+
+	// DeprecationHandler is called with a warning whenever a deprecated element of the contract is used.
+	DeprecationHandler DeprecationHandler
+
+	// StrictMode reports query parameters, headers and cookies that the operation does not declare. Standard HTTP
+	// headers, and the headers in StrictAllowedHeaders, are never reported.
+	StrictMode bool
+
+	// StrictAllowedHeaders lists the headers strict mode allows, names are case-insensitive and a trailing
+	// asterisk matches any header that starts with the name. DefaultStrictAllowedHeaders by default.
+	StrictAllowedHeaders []string
+
+	// StrictAdditionalProperties closes the object schemas of request and/or response bodies that leave
+	// additionalProperties unset, so properties the schemas do not define are reported.
+	StrictAdditionalProperties StrictPropertiesScope
+
+	// OpenAPISchemaPath is the path of a schema file that documents are validated against, instead of the OpenAPI
+	// schema libopenapi provides for their version.
+	OpenAPISchemaPath string
+
+	// RefreshOpenAPISchemas fetches the latest OpenAPI 3.0 and 3.1 schemas (once) to validate documents against,
+	// instead of the schemas libopenapi provides. Fetching waits for OpenAPISchemaRefreshTimeout at most.
+	RefreshOpenAPISchemas       bool
+	OpenAPISchemaRefreshTimeout time.Duration
+
+	// TokenIntrospector checks the bearer tokens of OAuth2 and OpenID Connect security schemes, and the scopes
+	// granted to them. Without one, the presence of a bearer token satisfies those schemes.
+	TokenIntrospector TokenIntrospector
+
+	// JWTKeyProvider verifies the signatures of the bearer tokens of schemes with a 'JWT' bearer format. Without
+	// one, only the structure and the claims of tokens are checked.
+	JWTKeyProvider JWTKeyProvider
+
+	// JWTAudiences are the audiences that JWT bearer tokens must be issued for (one of them), when set.
+	JWTAudiences []string
+
+	// CredentialVerifier authenticates the credentials of HTTP 'basic' and 'digest' security schemes. Without one,
+	// only the format of the credentials is checked.
+	CredentialVerifier CredentialVerifier
+
+	// ClientCertificateVerifier checks the client certificates of 'mutualTLS' security schemes. Without one, any
+	// client certificate verified by the TLS handshake satisfies those schemes.
+	ClientCertificateVerifier ClientCertificateVerifier
 }
 
 // Option Enables an 'Options pattern' approach
@@ -37,11 +241,14 @@ type Option func(*ValidationOptions)
 func NewValidationOptions(opts ...Option) *ValidationOptions {
 	// Create the set of default values
 	o := &ValidationOptions{
-		FormatAssertions:   false,
-		ContentAssertions:  false,
-		SecurityValidation: true,
-		OpenAPIMode:        true,                    // Enable OpenAPI vocabulary by default
-		SchemaCache:        cache.NewDefaultCache(), // Enable caching by default
+		FormatAssertions:     false,
+		ContentAssertions:    false,
+		SecurityValidation:   true,
+		AmbiguousPathErrors:  true,
+		OpenAPIMode:          true,                    // Enable OpenAPI vocabulary by default
+		SchemaCache:          cache.NewDefaultCache(), // Enable caching by default
+		MultipartMemoryLimit: DefaultMultipartMemoryLimit,
+		StrictAllowedHeaders: append([]string(nil), DefaultStrictAllowedHeaders...),
 	}
 
 	// Apply any supplied overrides
@@ -68,6 +275,22 @@ func WithExistingOpts(options *ValidationOptions) Option {
 			o.AllowScalarCoercion = options.AllowScalarCoercion
 			o.Formats = options.Formats
 			o.SchemaCache = options.SchemaCache
+			o.PathRouter = options.PathRouter
+			o.StrictServerMatching = options.StrictServerMatching
+			o.AmbiguousPathErrors = options.AmbiguousPathErrors
+			o.MultipartMemoryLimit = options.MultipartMemoryLimit
+			o.DeprecationHandler = options.DeprecationHandler
+			o.StrictMode = options.StrictMode
+			o.StrictAllowedHeaders = options.StrictAllowedHeaders
+			o.StrictAdditionalProperties = options.StrictAdditionalProperties
+			o.OpenAPISchemaPath = options.OpenAPISchemaPath
+			o.RefreshOpenAPISchemas = options.RefreshOpenAPISchemas
+			o.OpenAPISchemaRefreshTimeout = options.OpenAPISchemaRefreshTimeout
+			o.TokenIntrospector = options.TokenIntrospector
+			o.JWTKeyProvider = options.JWTKeyProvider
+			o.JWTAudiences = options.JWTAudiences
+			o.CredentialVerifier = options.CredentialVerifier
+			o.ClientCertificateVerifier = options.ClientCertificateVerifier
 		}
 	}
 }
@@ -150,3 +373,136 @@ func WithSchemaCache(cache cache.SchemaCache) Option {
 		o.SchemaCache = cache
 	}
 }
+
+// WithPathRouter sets the compiled routing index used to locate path items, instead of scanning every path in the
+// document. The router must have been built from the same document that is being validated.
+func WithPathRouter(router PathRouter) Option {
+	return func(o *ValidationOptions) {
+		o.PathRouter = router
+	}
+}
+
+// WithStrictServerMatching requires requests to match the scheme, host and base path of one of the servers that
+// apply to the operation (its own, then those of the path item, then those of the document). Server variables are
+// expanded to their enum values, or their default. By default, only the base path is stripped when it matches.
+func WithStrictServerMatching() Option {
+	return func(o *ValidationOptions) {
+		o.StrictServerMatching = true
+	}
+}
+
+// WithAmbiguousPathErrors reports requests that match more than one equally specific path template (such as
+// '/a/{x}/c' and '/a/b/{y}' for '/a/b/c') as errors (default: true).
+func WithAmbiguousPathErrors() Option {
+	return func(o *ValidationOptions) {
+		o.AmbiguousPathErrors = true
+	}
+}
+
+// WithoutAmbiguousPathErrors stops reporting ambiguous requests as errors: the first of the equally specific path
+// templates in the document is used, and the others are only listed in paths.RouteMatch.Ambiguous.
+func WithoutAmbiguousPathErrors() Option {
+	return func(o *ValidationOptions) {
+		o.AmbiguousPathErrors = false
+	}
+}
+
+// WithMultipartMemoryLimit caps the number of bytes of a multipart/form-data request body read into memory for
+// validation (32MB by default). A larger body is reported as invalid.
+func WithMultipartMemoryLimit(limit int64) Option {
+	return func(o *ValidationOptions) {
+		o.MultipartMemoryLimit = limit
+	}
+}
+
+// WithDeprecationHandler sets a handler that is warned whenever a request or response uses a deprecated element of
+// the contract (such as a deprecated response header). Deprecated elements never fail validation.
+func WithDeprecationHandler(handler DeprecationHandler) Option {
+	return func(o *ValidationOptions) {
+		o.DeprecationHandler = handler
+	}
+}
+
+// WithStrictMode reports query parameters, headers and cookies that are sent with a request, but are not declared
+// by its operation (or used by its security schemes). Standard HTTP headers, and infrastructure headers such as
+// tracing and proxy headers (see DefaultStrictAllowedHeaders), are allowed.
+func WithStrictMode() Option {
+	return func(o *ValidationOptions) {
+		o.StrictMode = true
+	}
+}
+
+// WithStrictAllowedHeaders adds headers that strict mode allows, to the default tracing and proxy headers. Names
+// are case-insensitive, a trailing asterisk matches any header that starts with the name (e.g. X-Internal-*).
+func WithStrictAllowedHeaders(headers ...string) Option {
+	return func(o *ValidationOptions) {
+		o.StrictAllowedHeaders = append(o.StrictAllowedHeaders, headers...)
+	}
+}
+
+// WithStrictAdditionalProperties treats every object schema of the bodies in scope that leaves additionalProperties
+// unset as 'additionalProperties: false' (or 'unevaluatedProperties: false' when it is composed with allOf, anyOf or
+// oneOf), so properties that are not defined fail validation. Free-form objects (that define no properties), and
+// schemas with the 'x-strict-properties: false' extension, are left open.
+func WithStrictAdditionalProperties(scope StrictPropertiesScope) Option {
+	return func(o *ValidationOptions) {
+		o.StrictAdditionalProperties = scope
+	}
+}
+
+// WithOpenAPISchemaPath validates documents against the schema in a file, instead of the OpenAPI schema libopenapi
+// provides for their version. Use it to validate documents offline against a newer, or customized, OpenAPI schema.
+func WithOpenAPISchemaPath(path string) Option {
+	return func(o *ValidationOptions) {
+		o.OpenAPISchemaPath = path
+	}
+}
+
+// WithOpenAPISchemaRefresh fetches the latest OpenAPI 3.0 and 3.1 schemas from the network to validate documents
+// against, waiting for the timeout at most (a default timeout is used when it is zero). The schemas libopenapi
+// provides are used when they cannot be fetched.
+func WithOpenAPISchemaRefresh(timeout time.Duration) Option {
+	return func(o *ValidationOptions) {
+		o.RefreshOpenAPISchemas = true
+		o.OpenAPISchemaRefreshTimeout = timeout
+	}
+}
+
+// WithTokenIntrospector checks the bearer tokens sent for OAuth2 and OpenID Connect security schemes with an
+// introspector, a token must be active and granted every scope the security requirement lists.
+func WithTokenIntrospector(introspector TokenIntrospector) Option {
+	return func(o *ValidationOptions) {
+		o.TokenIntrospector = introspector
+	}
+}
+
+// WithJWTKeyProvider verifies the signatures of JWT bearer tokens with the keys of a provider, such as a JSON Web
+// Key Set loaded with jwt.LoadJWKS.
+func WithJWTKeyProvider(provider JWTKeyProvider) Option {
+	return func(o *ValidationOptions) {
+		o.JWTKeyProvider = provider
+	}
+}
+
+// WithJWTAudiences requires JWT bearer tokens to be issued for one of the audiences (their 'aud' claim).
+func WithJWTAudiences(audiences ...string) Option {
+	return func(o *ValidationOptions) {
+		o.JWTAudiences = append(o.JWTAudiences, audiences...)
+	}
+}
+
+// WithCredentialVerifier authenticates the credentials sent for HTTP 'basic' and 'digest' security schemes with a
+// verifier.
+func WithCredentialVerifier(verifier CredentialVerifier) Option {
+	return func(o *ValidationOptions) {
+		o.CredentialVerifier = verifier
+	}
+}
+
+// WithClientCertificateVerifier checks the client certificates of requests for 'mutualTLS' security schemes with a
+// verifier, for example against an expected subject, subject alternative name or issuer.
+func WithClientCertificateVerifier(verifier ClientCertificateVerifier) Option {
+	return func(o *ValidationOptions) {
+		o.ClientCertificateVerifier = verifier
+	}
+}
@@ -4,11 +4,19 @@
 package config
 
 import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net/http"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 )
 
 func TestNewValidationOptions_Defaults(t *testing.T) {
@@ -368,3 +376,191 @@ func TestWithRegexpCache(t *testing.T) {
 
 	assert.NotNil(t, opts.RegexCache)
 }
+
+type stubRouter struct{}
+
+func (stubRouter) FindPathItem(_ *http.Request) PathMatch {
+	return PathMatch{}
+}
+
+func TestWithPathRouter(t *testing.T) {
+	assert.Nil(t, NewValidationOptions().PathRouter)
+
+	opts := NewValidationOptions(WithPathRouter(stubRouter{}))
+	assert.Equal(t, stubRouter{}, opts.PathRouter)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Equal(t, stubRouter{}, copied.PathRouter)
+}
+
+func TestWithStrictServerMatching(t *testing.T) {
+	assert.False(t, NewValidationOptions().StrictServerMatching)
+
+	opts := NewValidationOptions(WithStrictServerMatching())
+	assert.True(t, opts.StrictServerMatching)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.True(t, copied.StrictServerMatching)
+}
+
+func TestWithAmbiguousPathErrors(t *testing.T) {
+	assert.True(t, NewValidationOptions().AmbiguousPathErrors)
+
+	opts := NewValidationOptions(WithoutAmbiguousPathErrors())
+	assert.False(t, opts.AmbiguousPathErrors)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.False(t, copied.AmbiguousPathErrors)
+
+	opts = NewValidationOptions(WithoutAmbiguousPathErrors(), WithAmbiguousPathErrors())
+	assert.True(t, opts.AmbiguousPathErrors)
+}
+
+func TestWithMultipartMemoryLimit(t *testing.T) {
+	assert.Equal(t, DefaultMultipartMemoryLimit, NewValidationOptions().MultipartMemoryLimit)
+
+	opts := NewValidationOptions(WithMultipartMemoryLimit(1024))
+	assert.Equal(t, int64(1024), opts.MultipartMemoryLimit)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Equal(t, int64(1024), copied.MultipartMemoryLimit)
+}
+
+func TestWithDeprecationHandler(t *testing.T) {
+	assert.Nil(t, NewValidationOptions().DeprecationHandler)
+
+	var warnings []*DeprecationWarning
+	opts := NewValidationOptions(WithDeprecationHandler(func(_ *http.Request, warning *DeprecationWarning) {
+		warnings = append(warnings, warning)
+	}))
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	copied.DeprecationHandler(nil, &DeprecationWarning{Name: "X-Old"})
+	assert.Len(t, warnings, 1)
+}
+
+func TestWithStrictMode(t *testing.T) {
+	opts := NewValidationOptions()
+	assert.False(t, opts.StrictMode)
+	assert.Equal(t, DefaultStrictAllowedHeaders, opts.StrictAllowedHeaders)
+
+	opts = NewValidationOptions(WithStrictMode(), WithStrictAllowedHeaders("X-Internal-*", "X-Tenant"))
+	assert.True(t, opts.StrictMode)
+	assert.Len(t, opts.StrictAllowedHeaders, len(DefaultStrictAllowedHeaders)+2)
+	assert.Equal(t, "X-Tenant", opts.StrictAllowedHeaders[len(opts.StrictAllowedHeaders)-1])
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.True(t, copied.StrictMode)
+	assert.Equal(t, opts.StrictAllowedHeaders, copied.StrictAllowedHeaders)
+
+	// the defaults are not shared between options
+	assert.Len(t, NewValidationOptions().StrictAllowedHeaders, len(DefaultStrictAllowedHeaders))
+}
+
+func TestWithStrictAdditionalProperties(t *testing.T) {
+	assert.Equal(t, StrictPropertiesOff, NewValidationOptions().StrictAdditionalProperties)
+
+	opts := NewValidationOptions(WithStrictAdditionalProperties(StrictPropertiesResponses))
+	assert.Equal(t, StrictPropertiesResponses, opts.StrictAdditionalProperties)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Equal(t, StrictPropertiesResponses, copied.StrictAdditionalProperties)
+}
+
+func TestWithOpenAPISchemaPath(t *testing.T) {
+	assert.Empty(t, NewValidationOptions().OpenAPISchemaPath)
+
+	opts := NewValidationOptions(WithOpenAPISchemaPath("schemas/openapi-3.1.json"))
+	assert.Equal(t, "schemas/openapi-3.1.json", opts.OpenAPISchemaPath)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.Equal(t, "schemas/openapi-3.1.json", copied.OpenAPISchemaPath)
+}
+
+func TestWithOpenAPISchemaRefresh(t *testing.T) {
+	assert.False(t, NewValidationOptions().RefreshOpenAPISchemas)
+
+	opts := NewValidationOptions(WithOpenAPISchemaRefresh(2 * time.Second))
+	assert.True(t, opts.RefreshOpenAPISchemas)
+	assert.Equal(t, 2*time.Second, opts.OpenAPISchemaRefreshTimeout)
+
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	assert.True(t, copied.RefreshOpenAPISchemas)
+	assert.Equal(t, 2*time.Second, copied.OpenAPISchemaRefreshTimeout)
+}
+
+func TestWithTokenIntrospector(t *testing.T) {
+	assert.Nil(t, NewValidationOptions().TokenIntrospector)
+
+	introspector := TokenIntrospectorFunc(func(_ *http.Request, name string, _ *v3.SecurityScheme,
+		token string,
+	) ([]string, error) {
+		return []string{name, token}, nil
+	})
+	opts := NewValidationOptions(WithTokenIntrospector(introspector))
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	require.NotNil(t, copied.TokenIntrospector)
+	scopes, err := copied.TokenIntrospector.Introspect(nil, "OAuth", nil, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"OAuth", "abc"}, scopes)
+}
+
+type keyProvider []any
+
+func (k keyProvider) KeysFor(_ *http.Request, _ string, _ *v3.SecurityScheme, _, _ string) ([]any, error) {
+	return k, nil
+}
+
+func TestWithJWTKeyProvider(t *testing.T) {
+	opts := NewValidationOptions()
+	assert.Nil(t, opts.JWTKeyProvider)
+	assert.Empty(t, opts.JWTAudiences)
+
+	opts = NewValidationOptions(WithJWTKeyProvider(keyProvider{[]byte("secret")}),
+		WithJWTAudiences("burgers"), WithJWTAudiences("fries"))
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	require.NotNil(t, copied.JWTKeyProvider)
+	keys, err := copied.JWTKeyProvider.KeysFor(nil, "JWT", nil, "", "HS256")
+	assert.NoError(t, err)
+	assert.Equal(t, []any{[]byte("secret")}, keys)
+	assert.Equal(t, []string{"burgers", "fries"}, copied.JWTAudiences)
+}
+
+func TestWithCredentialVerifier(t *testing.T) {
+	assert.Nil(t, NewValidationOptions().CredentialVerifier)
+
+	verifier := CredentialVerifierFunc(func(_ *http.Request, name string, _ *v3.SecurityScheme,
+		credentials *Credentials,
+	) error {
+		if credentials.Username != "pb33f" {
+			return fmt.Errorf("unknown user '%s' for '%s'", credentials.Username, name)
+		}
+		return nil
+	})
+	opts := NewValidationOptions(WithCredentialVerifier(verifier))
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	require.NotNil(t, copied.CredentialVerifier)
+	assert.NoError(t, copied.CredentialVerifier.VerifyCredentials(nil, "BasicAuth", nil,
+		&Credentials{Scheme: "basic", Username: "pb33f"}))
+	assert.EqualError(t, copied.CredentialVerifier.VerifyCredentials(nil, "BasicAuth", nil,
+		&Credentials{Scheme: "basic", Username: "fries"}), "unknown user 'fries' for 'BasicAuth'")
+}
+
+func TestWithClientCertificateVerifier(t *testing.T) {
+	assert.Nil(t, NewValidationOptions().ClientCertificateVerifier)
+
+	verifier := ClientCertificateVerifierFunc(func(_ *http.Request, name string, _ *v3.SecurityScheme,
+		certificate *x509.Certificate, _ [][]*x509.Certificate,
+	) error {
+		if certificate.Subject.CommonName != "pb33f" {
+			return fmt.Errorf("'%s' is not allowed for '%s'", certificate.Subject.CommonName, name)
+		}
+		return nil
+	})
+	opts := NewValidationOptions(WithClientCertificateVerifier(verifier))
+	copied := NewValidationOptions(WithExistingOpts(opts))
+	require.NotNil(t, copied.ClientCertificateVerifier)
+	assert.NoError(t, copied.ClientCertificateVerifier.VerifyClientCertificate(nil, "ClientCert", nil,
+		&x509.Certificate{Subject: pkix.Name{CommonName: "pb33f"}}, nil))
+	assert.EqualError(t, copied.ClientCertificateVerifier.VerifyClientCertificate(nil, "ClientCert", nil,
+		&x509.Certificate{Subject: pkix.Name{CommonName: "fries"}}, nil), "'fries' is not allowed for 'ClientCert'")
+}
@@ -0,0 +1,55 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package errors
+
+import (
+	"context"
+	stdErrors "errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// HowToFixValidationCancelled is the advice given when validation was abandoned because its context was done.
+const HowToFixValidationCancelled = "Validation was abandoned before it could complete, increase the deadline or check why the context was cancelled"
+
+// ValidationCancelled creates a ValidationError that reports validation was abandoned because the context
+// driving it was cancelled, or its deadline expired. The context error is attached as the Context of the
+// ValidationError, so it can be inspected with errors.Is.
+func ValidationCancelled(err error, request *http.Request) *ValidationError {
+	subType := helpers.ContextCancelled
+	reason := "The context was cancelled before validation could complete"
+	if stdErrors.Is(err, context.DeadlineExceeded) {
+		subType = helpers.ContextDeadlineExceeded
+		reason = "The context deadline expired before validation could complete"
+	}
+	ve := &ValidationError{
+		ValidationType:    helpers.ContextValidation,
+		ValidationSubType: subType,
+		Message:           "Validation was cancelled",
+		Reason:            reason,
+		SpecLine:          -1,
+		SpecCol:           -1,
+		HowToFix:          HowToFixValidationCancelled,
+		Context:           err,
+	}
+	if request != nil {
+		ve.Message = fmt.Sprintf("%s validation for '%s' was cancelled", request.Method, request.URL.Path)
+		ve.RequestPath = request.URL.Path
+		ve.RequestMethod = request.Method
+	}
+	return ve
+}
+
+// ContainsCancellation returns true if any of the errors reports that validation was cancelled (see
+// ValidationError.IsCancellationError).
+func ContainsCancellation(validationErrors []*ValidationError) bool {
+	for _, ve := range validationErrors {
+		if ve != nil && ve.IsCancellationError() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,54 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestValidationCancelled(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+
+	err := ValidationCancelled(context.Canceled, request)
+	require.NotNil(t, err)
+	assert.Equal(t, helpers.ContextValidation, err.ValidationType)
+	assert.Equal(t, helpers.ContextCancelled, err.ValidationSubType)
+	assert.Equal(t, "GET validation for '/burgers' was cancelled", err.Message)
+	assert.Equal(t, "/burgers", err.RequestPath)
+	assert.Equal(t, http.MethodGet, err.RequestMethod)
+	assert.Equal(t, HowToFixValidationCancelled, err.HowToFix)
+	assert.True(t, err.IsCancellationError())
+	assert.ErrorIs(t, err.Context.(error), context.Canceled)
+}
+
+func TestValidationCancelled_DeadlineExceeded(t *testing.T) {
+	wrapped := fmt.Errorf("gateway: %w", context.DeadlineExceeded)
+
+	err := ValidationCancelled(wrapped, nil)
+	require.NotNil(t, err)
+	assert.Equal(t, helpers.ContextDeadlineExceeded, err.ValidationSubType)
+	assert.Equal(t, "Validation was cancelled", err.Message)
+	assert.Contains(t, err.Reason, "deadline expired")
+	assert.Empty(t, err.RequestPath)
+	assert.True(t, err.IsCancellationError())
+}
+
+func TestValidationError_IsCancellationError(t *testing.T) {
+	v := &ValidationError{ValidationType: "path", ValidationSubType: "missing"}
+	assert.False(t, v.IsCancellationError())
+}
+
+func TestContainsCancellation(t *testing.T) {
+	assert.False(t, ContainsCancellation(nil))
+	assert.False(t, ContainsCancellation([]*ValidationError{{ValidationType: "path"}, nil}))
+	assert.True(t, ContainsCancellation([]*ValidationError{{ValidationType: "path"}, ValidationCancelled(context.Canceled, nil)}))
+}
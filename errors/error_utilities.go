@@ -2,6 +2,9 @@ package errors
 
 import (
 	"net/http"
+	"strings"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
 )
 
 // PopulateValidationErrors mutates the provided validation errors with additional useful error information, that is
@@ -14,3 +17,19 @@ func PopulateValidationErrors(validationErrors []*ValidationError, request *http
 		validationError.RequestPath = request.URL.Path
 	}
 }
+
+// PrefixSchemaLocations mutates the schema failures of the provided validation errors, so that their locations are
+// relative to the document that contains the validated value: the item of a stream, for example, is located by its
+// index. The instance path, location and field path are prefixed with the segments.
+func PrefixSchemaLocations(validationErrors []*ValidationError, segments ...string) {
+	for _, validationError := range validationErrors {
+		for _, failure := range validationError.SchemaValidationErrors {
+			if failure == nil {
+				continue
+			}
+			failure.InstancePath = append(append([]string{}, segments...), failure.InstancePath...)
+			failure.Location = "/" + strings.Join(segments, "/") + strings.TrimSuffix(failure.Location, "/")
+			failure.FieldPath = helpers.ExtractJSONPathFromInstanceLocation(failure.InstancePath)
+		}
+	}
+}
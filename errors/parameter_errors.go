@@ -2,6 +2,7 @@ package errors
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 
@@ -629,3 +630,71 @@ func PathParameterMissing(param *v3.Parameter) *ValidationError {
 		HowToFix: HowToFixMissingValue,
 	}
 }
+
+// UnknownQueryParameter is returned in strict mode, when a request has a query parameter that its operation does
+// not declare.
+func UnknownQueryParameter(request *http.Request, name string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationQuery,
+		Message:           fmt.Sprintf("Query parameter '%s' is not defined", name),
+		Reason: fmt.Sprintf("The query parameter '%s' is not defined by the %s operation, "+
+			"undeclared parameters are not allowed in strict mode", name, request.Method),
+		SpecLine:      -1,
+		SpecCol:       -1,
+		ParameterName: name,
+		HowToFix:      fmt.Sprintf(HowToFixUnknownParameter, "query parameter", name),
+	}
+}
+
+// UnknownHeader is returned in strict mode, when a request has a header that its operation does not declare, and
+// that is neither a standard HTTP header nor an allowed one.
+func UnknownHeader(request *http.Request, name string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationHeader,
+		Message:           fmt.Sprintf("Header '%s' is not defined", name),
+		Reason: fmt.Sprintf("The header '%s' is not defined by the %s operation, and is not a standard "+
+			"or allowed header, undeclared headers are not allowed in strict mode", name, request.Method),
+		SpecLine:      -1,
+		SpecCol:       -1,
+		ParameterName: name,
+		HowToFix:      fmt.Sprintf(HowToFixUnknownHeader, name),
+	}
+}
+
+// UnknownCookie is returned in strict mode, when a request has a cookie that its operation does not declare.
+func UnknownCookie(request *http.Request, name string) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationCookie,
+		Message:           fmt.Sprintf("Cookie '%s' is not defined", name),
+		Reason: fmt.Sprintf("The cookie '%s' is not defined by the %s operation, "+
+			"undeclared cookies are not allowed in strict mode", name, request.Method),
+		SpecLine:      -1,
+		SpecCol:       -1,
+		ParameterName: name,
+		HowToFix:      fmt.Sprintf(HowToFixUnknownParameter, "cookie", name),
+	}
+}
+
+// QueryStringCannotBeDecoded is returned when the query string of a request cannot be decoded as the media type of
+// its 'querystring' parameter (OpenAPI 3.2) describes.
+func QueryStringCannotBeDecoded(param *v3.Parameter, query, contentType string) *ValidationError {
+	specLine, specCol := -1, -1
+	if low := param.GoLow(); low != nil && low.Content.KeyNode != nil {
+		specLine, specCol = low.Content.KeyNode.Line, low.Content.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ParameterValidation,
+		ValidationSubType: helpers.ParameterValidationQuery,
+		Message:           fmt.Sprintf("Query string '%s' cannot be decoded", param.Name),
+		Reason: fmt.Sprintf("The query string is defined by the parameter '%s' as '%s', "+
+			"however '%s' cannot be decoded as '%s'", param.Name, contentType, query, contentType),
+		SpecLine:      specLine,
+		SpecCol:       specCol,
+		ParameterName: param.Name,
+		Context:       param,
+		HowToFix:      HowToFixInvalidEncoding,
+	}
+}
@@ -5,6 +5,7 @@ package errors
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
@@ -1186,3 +1187,23 @@ items:
 	require.Contains(t, err.Reason, "The query parameter (which is an array) 'testQueryParam' contains the following duplicates: 'fish, cake'")
 	require.Contains(t, err.HowToFix, "Ensure the array values are all unique")
 }
+
+func TestUnknownParameters(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers?limt=10", nil)
+
+	err := UnknownQueryParameter(request, "limt")
+	require.Equal(t, helpers.ParameterValidationQuery, err.ValidationSubType)
+	require.Equal(t, "Query parameter 'limt' is not defined", err.Message)
+	require.Equal(t, "Remove the query parameter 'limt' from the request, or declare it as a parameter of the operation",
+		err.HowToFix)
+
+	err = UnknownHeader(request, "X-Tennant")
+	require.Equal(t, helpers.ParameterValidationHeader, err.ValidationSubType)
+	require.Contains(t, err.Reason, "is not a standard or allowed header")
+	require.Contains(t, err.HowToFix, "allow it in strict mode")
+
+	err = UnknownCookie(request, "flavour")
+	require.Equal(t, helpers.ParameterValidationCookie, err.ValidationSubType)
+	require.Equal(t, "flavour", err.ParameterName)
+	require.Contains(t, err.HowToFix, "Remove the cookie 'flavour'")
+}
@@ -19,15 +19,33 @@ const (
 		"they should be separated by pipes '|'. For example: '%s'"
 	HowToFixParamInvalidDeepObjectMultipleValues string = "There can only be a single value per property name, " +
 		"deepObject parameters should contain the property key in square brackets next to the parameter name. For example: '%s'"
-	HowToFixInvalidJSON         string = "The JSON submitted is invalid, please check the syntax"
-	HowToFixDecodingError              = "The object can't be decoded, so make sure it's being encoded correctly according to the spec."
-	HowToFixInvalidContentType         = "The content type is invalid, Use one of the %d supported types for this operation: %s"
-	HowToFixInvalidResponseCode        = "The service is responding with a code that is not defined in the spec, fix the service or add the code to the specification"
-	HowToFixInvalidEncoding            = "Ensure the correct encoding has been used on the object"
-	HowToFixMissingValue               = "Ensure the value has been set"
-	HowToFixPath                       = "Check the path is correct, and check that the correct HTTP method has been used (e.g. GET, POST, PUT, DELETE)"
-	HowToFixPathMethod                 = "Add the missing operation to the contract for the path"
-	HowToFixInvalidMaxItems            = "Reduce the number of items in the array to %d or less"
-	HowToFixInvalidMinItems            = "Increase the number of items in the array to %d or more"
-	HowToFixMissingHeader              = "Make sure the service responding sets the required headers with this response code"
+	HowToFixInvalidJSON          string = "The JSON submitted is invalid, please check the syntax"
+	HowToFixDecodingError               = "The object can't be decoded, so make sure it's being encoded correctly according to the spec."
+	HowToFixInvalidContentType          = "The content type is invalid, Use one of the %d supported types for this operation: %s"
+	HowToFixNotAcceptable               = "Respond with a content type accepted by the request (%s), or respond with '406 Not Acceptable'"
+	HowToFixInvalidResponseCode         = "The service is responding with a code that is not defined in the spec, fix the service or add the code to the specification"
+	HowToFixInvalidEncoding             = "Ensure the correct encoding has been used on the object"
+	HowToFixMissingValue                = "Ensure the value has been set"
+	HowToFixPath                        = "Check the path is correct, and check that the correct HTTP method has been used (e.g. GET, POST, PUT, DELETE)"
+	HowToFixPathAmbiguous               = "Rename or restructure the path templates in the specification, so that only one of them can match this request"
+	HowToFixPathMethod                  = "Add the missing operation to the contract for the path"
+	HowToFixInvalidMaxItems             = "Reduce the number of items in the array to %d or less"
+	HowToFixInvalidMinItems             = "Increase the number of items in the array to %d or more"
+	HowToFixMissingHeader               = "Make sure the service responding sets the required headers with this response code"
+	HowToFixMultipartContentType        = "Send the part with one of the content types allowed by its encoding: %s"
+	HowToFixMultipartHeader             = "Add the '%s' header to the part, it is required by its encoding"
+	HowToFixMultipartMemoryLimit        = "Send a smaller multipart request body, or raise the multipart memory limit"
+	HowToFixUnknownParameter            = "Remove the %s '%s' from the request, or declare it as a parameter of the operation"
+	HowToFixUnknownHeader               = "Remove the header '%s' from the request, declare it as a parameter of the operation, or allow it in strict mode"
+	HowToFixReadOnlyProperty            = "Remove the '%s' property from the request body, it is readOnly and is set by the server"
+	HowToFixWriteOnlyProperty           = "Remove the '%s' property from the response body, it is writeOnly and must never be returned"
+	HowToFixBearerTokenMissing          = "Add an 'Authorization: Bearer <token>' header to the request, with a token issued for the '%s' security scheme"
+	HowToFixBearerTokenInvalid          = "The token is not active, request a new token from the authorization server of the '%s' security scheme"
+	HowToFixSecurityScopes              = "Request a token that is granted the missing scopes: %s"
+	HowToFixInvalidJWT                  = "Send a signed JWT that is current (see its 'exp' and 'nbf' claims) and issued for this API (see its 'aud' claim)"
+	HowToFixBasicCredentials            = "Add an 'Authorization: Basic <credentials>' header to the request, where the credentials are the base64 encoding of 'username:password'"
+	HowToFixDigestCredentials           = "Add an 'Authorization: Digest' header to the request, with the username, realm, nonce, uri and response parameters of the challenge"
+	HowToFixCredentialsRejected         = "Send credentials of a user who is allowed to access the '%s' security scheme"
+	HowToFixClientCertMissing           = "Send the request over TLS with a client certificate that is issued by a certificate authority the server trusts"
+	HowToFixClientCertRejected          = "Send a client certificate that is issued for the '%s' security scheme"
 )
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/orderedmap"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -54,3 +55,110 @@ func OperationNotFound(pathItem *v3.PathItem, request *http.Request, method stri
 		SpecPath:      specPath,
 	}
 }
+
+// MultipartPartContentTypeInvalid is returned when a part of a multipart/form-data request body has a content type
+// that is not allowed by the encoding of its property.
+func MultipartPartContentTypeInvalid(request *http.Request, partName, contentType string, encoding *v3.Encoding) *ValidationError {
+	line, col := -1, -1
+	if low := encoding.GoLow(); low != nil && low.ContentType.ValueNode != nil {
+		line, col = low.ContentType.ValueNode.Line, low.ContentType.ValueNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.MultipartContentType,
+		Message: fmt.Sprintf("%s request body part '%s' has an invalid content type '%s'",
+			request.Method, partName, contentType),
+		Reason: fmt.Sprintf("The content type '%s' of the part '%s' is not one of the content types "+
+			"allowed by its encoding: '%s'", contentType, partName, encoding.ContentType),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       encoding,
+		HowToFix:      fmt.Sprintf(HowToFixMultipartContentType, encoding.ContentType),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+// MultipartPartHeaderMissing is returned when a part of a multipart/form-data request body is missing a header that
+// the encoding of its property requires.
+func MultipartPartHeaderMissing(request *http.Request, partName, headerName string, header *v3.Header) *ValidationError {
+	line, col := -1, -1
+	if low := header.GoLow(); low != nil && low.Required.KeyNode != nil {
+		line, col = low.Required.KeyNode.Line, low.Required.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.MultipartHeader,
+		Message: fmt.Sprintf("%s request body part '%s' is missing the '%s' header",
+			request.Method, partName, headerName),
+		Reason: fmt.Sprintf("The header '%s' is required for the part '%s' by its encoding, "+
+			"however it was not found", headerName, partName),
+		SpecLine:      line,
+		SpecCol:       col,
+		Context:       header,
+		HowToFix:      fmt.Sprintf(HowToFixMultipartHeader, headerName),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+// MultipartMemoryLimitExceeded is returned when a multipart/form-data request body is larger than the configured
+// memory limit, the body is not validated.
+func MultipartMemoryLimitExceeded(request *http.Request, limit int64) *ValidationError {
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.MultipartMemoryLimit,
+		Message: fmt.Sprintf("%s request body for '%s' is too large to decode",
+			request.Method, request.URL.Path),
+		Reason:        fmt.Sprintf("The multipart request body exceeds the memory limit of %d bytes", limit),
+		SpecLine:      -1,
+		SpecCol:       -1,
+		HowToFix:      HowToFixMultipartMemoryLimit,
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+// ReadOnlyPropertyInRequest is returned when a request body contains a property that is readOnly, readOnly
+// properties are set by the server and can only be sent in responses.
+func ReadOnlyPropertyInRequest(request *http.Request, property string, instancePath []string, schema *base.Schema) *ValidationError {
+	fieldPath := helpers.ExtractJSONPathFromInstanceLocation(instancePath)
+	line, col := accessModeLocation(schema, helpers.ReadOnly)
+	return &ValidationError{
+		ValidationType:    helpers.RequestBodyValidation,
+		ValidationSubType: helpers.ReadOnly,
+		Message: fmt.Sprintf("%s request body for '%s' contains the readOnly property '%s'",
+			request.Method, request.URL.Path, property),
+		Reason: fmt.Sprintf("The property '%s' (at '%s') is readOnly, it can be sent in responses, "+
+			"but not in requests", property, fieldPath),
+		SpecLine: line,
+		SpecCol:  col,
+		SchemaValidationErrors: []*SchemaValidationFailure{{
+			Reason:       fmt.Sprintf("readOnly property '%s' is not allowed in a request", property),
+			Location:     "/" + strings.Join(instancePath, "/"),
+			FieldName:    property,
+			FieldPath:    fieldPath,
+			InstancePath: instancePath,
+		}},
+		Context:       schema,
+		HowToFix:      fmt.Sprintf(HowToFixReadOnlyProperty, property),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+// accessModeLocation returns the location of the readOnly or writeOnly keyword of a schema.
+func accessModeLocation(schema *base.Schema, keyword string) (int, int) {
+	low := schema.GoLow()
+	if low == nil {
+		return 1, 0
+	}
+	node := low.ReadOnly.KeyNode
+	if keyword == helpers.WriteOnly {
+		node = low.WriteOnly.KeyNode
+	}
+	if node == nil {
+		return 1, 0
+	}
+	return node.Line, node.Column
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/require"
@@ -98,3 +99,34 @@ func TestOperationNotFound(t *testing.T) {
 	require.Equal(t, 25, err.SpecCol)
 	require.Equal(t, HowToFixPathMethod, err.HowToFix)
 }
+
+func TestMultipartErrors(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers", nil)
+
+	err := MultipartPartContentTypeInvalid(request, "photo", "image/gif", &v3.Encoding{ContentType: "image/png"})
+	require.Equal(t, helpers.MultipartContentType, err.ValidationSubType)
+	require.Equal(t, "POST request body part 'photo' has an invalid content type 'image/gif'", err.Message)
+	require.Equal(t, -1, err.SpecLine)
+
+	err = MultipartPartHeaderMissing(request, "photo", "X-Rating", &v3.Header{Required: true})
+	require.Equal(t, helpers.MultipartHeader, err.ValidationSubType)
+	require.Equal(t, "POST request body part 'photo' is missing the 'X-Rating' header", err.Message)
+
+	err = MultipartMemoryLimitExceeded(request, 10)
+	require.Equal(t, helpers.MultipartMemoryLimit, err.ValidationSubType)
+	require.Equal(t, "/burgers", err.RequestPath)
+}
+
+func TestReadOnlyPropertyInRequest(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers", nil)
+
+	err := ReadOnlyPropertyInRequest(request, "id", []string{"sides", "0", "id"}, &base.Schema{})
+	require.Equal(t, helpers.RequestBodyValidation, err.ValidationType)
+	require.Equal(t, helpers.ReadOnly, err.ValidationSubType)
+	require.Equal(t, "POST request body for '/burgers' contains the readOnly property 'id'", err.Message)
+	require.Contains(t, err.Reason, "'$.sides[0].id'")
+	require.Equal(t, 1, err.SpecLine)
+	require.Len(t, err.SchemaValidationErrors, 1)
+	require.Equal(t, "$.sides[0].id", err.SchemaValidationErrors[0].FieldPath)
+	require.Equal(t, "/sides/0/id", err.SchemaValidationErrors[0].Location)
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/orderedmap"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -72,3 +73,95 @@ func ResponseCodeNotFound(op *v3.Operation, request *http.Request, code int) *Va
 		HowToFix: HowToFixInvalidResponseCode,
 	}
 }
+
+// ResponseHeaderCannotBeDecoded is returned when the value of a response header cannot be decoded as its definition
+// describes: an object that is not made of (key, value) pairs, or content that is not valid JSON.
+func ResponseHeaderCannotBeDecoded(request *http.Request, name, value string, header *v3.Header) *ValidationError {
+	specLine, specCol := -1, -1
+	if low := header.GoLow(); low != nil && low.KeyNode != nil {
+		specLine, specCol = low.KeyNode.Line, low.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ResponseBodyValidation,
+		ValidationSubType: helpers.ParameterValidationHeader,
+		Message:           fmt.Sprintf("Response header '%s' cannot be decoded", name),
+		Reason: fmt.Sprintf("The response header '%s' cannot be decoded as its definition "+
+			"describes, '%s' is malformed", name, value),
+		SpecLine:      specLine,
+		SpecCol:       specCol,
+		ParameterName: name,
+		Context:       header,
+		HowToFix:      HowToFixInvalidEncoding,
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+// ResponseNotAcceptable is returned when the content type of a response does not satisfy the Accept header of the
+// request, the service should have responded with '406 Not Acceptable' instead. The content types of the response
+// that the request does accept (if any) are suggested as the fix.
+func ResponseNotAcceptable(op *v3.Operation, request *http.Request, response *http.Response, code string,
+	content *orderedmap.Map[string, *v3.MediaType],
+) *ValidationError {
+	accept := request.Header.Get(helpers.AcceptHeader)
+	mediaTypeString, _, _ := helpers.ExtractContentType(response.Header.Get(helpers.ContentTypeHeader))
+
+	var acceptable []string
+	for ct := range content.KeysFromOldest() {
+		if helpers.IsAcceptable(ct, accept) {
+			acceptable = append(acceptable, ct)
+		}
+	}
+	if len(acceptable) == 0 {
+		acceptable = append(acceptable, "none are defined")
+	}
+
+	specLine, specCol := -1, -1
+	if responses := op.GoLow().Responses; responses.KeyNode != nil {
+		specLine, specCol = responses.KeyNode.Line, responses.KeyNode.Column
+	}
+	return &ValidationError{
+		ValidationType:    helpers.ResponseBodyValidation,
+		ValidationSubType: helpers.ResponseNotAcceptable,
+		Message: fmt.Sprintf("%s / %s operation response content type '%s' is not acceptable",
+			request.Method, code, mediaTypeString),
+		Reason: fmt.Sprintf("The content type '%s' of the %s response does not satisfy the Accept header '%s' "+
+			"of the request", mediaTypeString, request.Method, accept),
+		SpecLine:      specLine,
+		SpecCol:       specCol,
+		Context:       op,
+		HowToFix:      fmt.Sprintf(HowToFixNotAcceptable, strings.Join(acceptable, ", ")),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
+
+// WriteOnlyPropertyInResponse is returned when a response body contains a property that is writeOnly, writeOnly
+// properties (passwords, secrets) can only be sent in requests.
+func WriteOnlyPropertyInResponse(request *http.Request, response *http.Response, property string,
+	instancePath []string, schema *base.Schema,
+) *ValidationError {
+	fieldPath := helpers.ExtractJSONPathFromInstanceLocation(instancePath)
+	line, col := accessModeLocation(schema, helpers.WriteOnly)
+	return &ValidationError{
+		ValidationType:    helpers.ResponseBodyValidation,
+		ValidationSubType: helpers.WriteOnly,
+		Message: fmt.Sprintf("%d response body for '%s' contains the writeOnly property '%s'",
+			response.StatusCode, request.URL.Path, property),
+		Reason: fmt.Sprintf("The property '%s' (at '%s') is writeOnly, it can be sent in requests, "+
+			"but not in responses", property, fieldPath),
+		SpecLine: line,
+		SpecCol:  col,
+		SchemaValidationErrors: []*SchemaValidationFailure{{
+			Reason:       fmt.Sprintf("writeOnly property '%s' is not allowed in a response", property),
+			Location:     "/" + strings.Join(instancePath, "/"),
+			FieldName:    property,
+			FieldPath:    fieldPath,
+			InstancePath: instancePath,
+		}},
+		Context:       schema,
+		HowToFix:      fmt.Sprintf(HowToFixWriteOnlyProperty, property),
+		RequestPath:   request.URL.Path,
+		RequestMethod: request.Method,
+	}
+}
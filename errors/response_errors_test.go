@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/datamodel/low"
 	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/require"
@@ -159,3 +160,58 @@ func TestResponseCodeNotFound(t *testing.T) {
 	require.Equal(t, 56, err.SpecCol)
 	require.Equal(t, HowToFixInvalidResponseCode, err.HowToFix)
 }
+
+func TestWriteOnlyPropertyInResponse(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/users/1", nil)
+	response := &http.Response{StatusCode: http.StatusOK}
+
+	err := WriteOnlyPropertyInResponse(request, response, "password", []string{"password"}, &base.Schema{})
+	require.Equal(t, helpers.ResponseBodyValidation, err.ValidationType)
+	require.Equal(t, helpers.WriteOnly, err.ValidationSubType)
+	require.Equal(t, "200 response body for '/users/1' contains the writeOnly property 'password'", err.Message)
+	require.Contains(t, err.HowToFix, "Remove the 'password' property")
+	require.Equal(t, "$.password", err.SchemaValidationErrors[0].FieldPath)
+}
+
+func TestResponseNotAcceptable(t *testing.T) {
+	op := createMockOperation()
+	op.GoLow().Responses.KeyNode.Line = 12
+	op.GoLow().Responses.KeyNode.Column = 7
+
+	content := orderedmap.New[string, *v3.MediaType]()
+	content.Set("application/json", &v3.MediaType{})
+	content.Set("text/html", &v3.MediaType{})
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	request.Header.Set(helpers.AcceptHeader, "text/*")
+	response := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+	response.Header.Set(helpers.ContentTypeHeader, "application/json; charset=utf-8")
+
+	err := ResponseNotAcceptable(op, request, response, "200", content)
+	require.Equal(t, helpers.ResponseBodyValidation, err.ValidationType)
+	require.Equal(t, helpers.ResponseNotAcceptable, err.ValidationSubType)
+	require.True(t, err.IsNotAcceptableError())
+	require.Equal(t, "GET / 200 operation response content type 'application/json' is not acceptable", err.Message)
+	require.Contains(t, err.Reason, "does not satisfy the Accept header 'text/*'")
+	require.Equal(t, "Respond with a content type accepted by the request (text/html), or respond with '406 Not Acceptable'",
+		err.HowToFix)
+	require.Equal(t, 12, err.SpecLine)
+	require.Equal(t, 7, err.SpecCol)
+
+	request.Header.Set(helpers.AcceptHeader, "image/png")
+	err = ResponseNotAcceptable(op, request, response, "200", content)
+	require.Contains(t, err.HowToFix, "(none are defined)")
+}
+
+func TestResponseHeaderCannotBeDecoded(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+
+	err := ResponseHeaderCannotBeDecoded(request, "x-limits", "burst", &v3.Header{})
+	require.Equal(t, helpers.ResponseBodyValidation, err.ValidationType)
+	require.Equal(t, helpers.ParameterValidationHeader, err.ValidationSubType)
+	require.Equal(t, "Response header 'x-limits' cannot be decoded", err.Message)
+	require.Contains(t, err.Reason, "'burst' is malformed")
+	require.Equal(t, "x-limits", err.ParameterName)
+	require.Equal(t, -1, err.SpecLine)
+	require.Equal(t, HowToFixInvalidEncoding, err.HowToFix)
+}
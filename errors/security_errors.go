@@ -0,0 +1,232 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// BearerTokenMissing is returned when the request has no bearer token for an OAuth2, OpenID Connect or HTTP bearer
+// security scheme.
+func BearerTokenMissing(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement) *ValidationError {
+	line, col := requirementLocation(requirement)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: securitySubType(scheme),
+		Message:           fmt.Sprintf("Bearer token for '%s' scheme not found", name),
+		Reason: fmt.Sprintf("The '%s' security scheme (%s) requires a bearer token, however the request has no "+
+			"'Authorization' header with a bearer token", name, scheme.Type),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  scheme,
+		HowToFix: fmt.Sprintf(HowToFixBearerTokenMissing, name),
+	}
+}
+
+// BearerTokenInvalid is returned when the token introspector rejects the bearer token sent for an OAuth2 or OpenID
+// Connect security scheme.
+func BearerTokenInvalid(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement,
+	err error,
+) *ValidationError {
+	line, col := requirementLocation(requirement)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: scheme.Type,
+		Message:           fmt.Sprintf("Bearer token for '%s' scheme is not valid", name),
+		Reason:            fmt.Sprintf("The bearer token sent for the '%s' security scheme was rejected: %s", name, err),
+		SpecLine:          line,
+		SpecCol:           col,
+		Context:           scheme,
+		HowToFix:          fmt.Sprintf(HowToFixBearerTokenInvalid, name),
+	}
+}
+
+// SecurityScopesMissing is returned when the bearer token sent for an OAuth2 or OpenID Connect security scheme is
+// valid, but is not granted every scope that the security requirement lists.
+func SecurityScopesMissing(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement,
+	missing []string,
+) *ValidationError {
+	line, col := requirementLocation(requirement)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: helpers.SecurityScopes,
+		Message:           fmt.Sprintf("Bearer token for '%s' scheme is missing scopes", name),
+		Reason: fmt.Sprintf("The bearer token sent for the '%s' security scheme is not granted the required "+
+			"scopes '%s'", name, strings.Join(missing, "', '")),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  scheme,
+		HowToFix: fmt.Sprintf(HowToFixSecurityScopes, strings.Join(missing, ", ")),
+	}
+}
+
+// JWTInvalid is returned when the bearer token sent for a security scheme with a 'JWT' bearer format is not a valid
+// JWT: it is malformed, it has expired or is not valid yet, it is issued for another audience, or its signature
+// cannot be verified.
+func JWTInvalid(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement, err error) *ValidationError {
+	line, col := requirementLocation(requirement)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: securitySubType(scheme),
+		Message:           fmt.Sprintf("Bearer token for '%s' scheme is not a valid JWT", name),
+		Reason:            fmt.Sprintf("The JWT sent for the '%s' security scheme is not valid: %s", name, err),
+		SpecLine:          line,
+		SpecCol:           col,
+		Context:           scheme,
+		HowToFix:          HowToFixInvalidJWT,
+	}
+}
+
+// CredentialsMissing is returned when the 'Authorization' header of the request does not use the authentication
+// scheme of an HTTP 'basic' or 'digest' security scheme.
+func CredentialsMissing(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement,
+	sent string,
+) *ValidationError {
+	line, col := requirementLocation(requirement)
+	authScheme := credentialsScheme(scheme)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: helpers.CredentialsMissing,
+		Message:           fmt.Sprintf("%s credentials for '%s' scheme not found", authScheme, name),
+		Reason: fmt.Sprintf("The '%s' security scheme requires '%s' credentials, however the 'Authorization' "+
+			"header of the request uses the '%s' scheme", name, authScheme, sent),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  scheme,
+		HowToFix: credentialsHowToFix(scheme),
+	}
+}
+
+// CredentialsMalformed is returned when the credentials sent for an HTTP 'basic' or 'digest' security scheme are
+// not encoded as the scheme defines (RFC 7617 and RFC 7616).
+func CredentialsMalformed(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement,
+	err error,
+) *ValidationError {
+	line, col := requirementLocation(requirement)
+	authScheme := credentialsScheme(scheme)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: helpers.CredentialsMalformed,
+		Message:           fmt.Sprintf("%s credentials for '%s' scheme are malformed", authScheme, name),
+		Reason: fmt.Sprintf("The '%s' credentials sent for the '%s' security scheme are malformed: %s", authScheme,
+			name, err),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  scheme,
+		HowToFix: credentialsHowToFix(scheme),
+	}
+}
+
+// CredentialsRejected is returned when the credential verifier rejects the credentials sent for an HTTP 'basic' or
+// 'digest' security scheme.
+func CredentialsRejected(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement,
+	err error,
+) *ValidationError {
+	line, col := requirementLocation(requirement)
+	authScheme := credentialsScheme(scheme)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: helpers.CredentialsRejected,
+		Message:           fmt.Sprintf("%s credentials for '%s' scheme are not valid", authScheme, name),
+		Reason: fmt.Sprintf("The '%s' credentials sent for the '%s' security scheme were rejected: %s", authScheme,
+			name, err),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  scheme,
+		HowToFix: fmt.Sprintf(HowToFixCredentialsRejected, name),
+	}
+}
+
+// ClientCertificateMissing is returned when the request for a 'mutualTLS' security scheme has no client certificate
+// verified by the TLS handshake.
+func ClientCertificateMissing(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement,
+	reason string,
+) *ValidationError {
+	line, col := requirementLocation(requirement)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: helpers.CertificateMissing,
+		Message:           fmt.Sprintf("Client certificate for '%s' scheme not found", name),
+		Reason: fmt.Sprintf("The '%s' security scheme (mutualTLS) requires a verified client certificate, "+
+			"however %s", name, reason),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  scheme,
+		HowToFix: HowToFixClientCertMissing,
+	}
+}
+
+// ClientCertificateRejected is returned when the client certificate verifier rejects the certificate of the request
+// for a 'mutualTLS' security scheme.
+func ClientCertificateRejected(name string, scheme *v3.SecurityScheme, requirement *base.SecurityRequirement,
+	subject string, err error,
+) *ValidationError {
+	line, col := requirementLocation(requirement)
+	return &ValidationError{
+		ValidationType:    helpers.SecurityValidation,
+		ValidationSubType: helpers.CertificateRejected,
+		Message:           fmt.Sprintf("Client certificate for '%s' scheme is not valid", name),
+		Reason: fmt.Sprintf("The client certificate '%s' sent for the '%s' security scheme was rejected: %s",
+			subject, name, err),
+		SpecLine: line,
+		SpecCol:  col,
+		Context:  scheme,
+		HowToFix: fmt.Sprintf(HowToFixClientCertRejected, name),
+	}
+}
+
+// ExplainSecurityRequirement adds the security requirement that a scheme belongs to, and the scheme that failed, to
+// the reason of the errors of the scheme. The schemes of a requirement must all be satisfied, and the request must
+// satisfy one of the alternative requirements.
+func ExplainSecurityRequirement(validationErrors []*ValidationError, scheme string, members []string,
+	alternative, alternatives int,
+) {
+	for _, validationError := range validationErrors {
+		validationError.Reason = fmt.Sprintf("%s (security requirement %d of %d requires '%s', '%s' is not satisfied)",
+			validationError.Reason, alternative, alternatives, strings.Join(members, "' and '"), scheme)
+	}
+}
+
+// securitySubType returns the scheme of HTTP security schemes (basic, bearer), or the type of the others.
+func securitySubType(scheme *v3.SecurityScheme) string {
+	if strings.EqualFold(scheme.Type, "http") {
+		return scheme.Scheme
+	}
+	return scheme.Type
+}
+
+// credentialsScheme returns the authentication scheme of an HTTP 'basic' or 'digest' security scheme, as it is sent
+// in the 'Authorization' header.
+func credentialsScheme(scheme *v3.SecurityScheme) string {
+	if strings.EqualFold(scheme.Scheme, helpers.DigestScheme) {
+		return helpers.DigestScheme
+	}
+	return helpers.BasicScheme
+}
+
+func credentialsHowToFix(scheme *v3.SecurityScheme) string {
+	if strings.EqualFold(scheme.Scheme, helpers.DigestScheme) {
+		return HowToFixDigestCredentials
+	}
+	return HowToFixBasicCredentials
+}
+
+// requirementLocation returns the line and column of the requirements of a security requirement.
+func requirementLocation(requirement *base.SecurityRequirement) (int, int) {
+	if requirement == nil {
+		return -1, -1
+	}
+	low := requirement.GoLow()
+	if low == nil || low.Requirements.ValueNode == nil {
+		return -1, -1
+	}
+	return low.Requirements.ValueNode.Line, low.Requirements.ValueNode.Column
+}
@@ -0,0 +1,94 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/stretchr/testify/assert"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestSecurityErrors(t *testing.T) {
+	scheme := &v3.SecurityScheme{Type: "oauth2"}
+	requirement := &base.SecurityRequirement{}
+
+	err := BearerTokenMissing("OAuth", scheme, requirement)
+	assert.Equal(t, helpers.SecurityValidation, err.ValidationType)
+	assert.Equal(t, "oauth2", err.ValidationSubType)
+	assert.Equal(t, -1, err.SpecLine)
+	assert.Equal(t, "Add an 'Authorization: Bearer <token>' header to the request, with a token issued for the "+
+		"'OAuth' security scheme", err.HowToFix)
+
+	err = BearerTokenInvalid("OAuth", scheme, nil, fmt.Errorf("expired"))
+	assert.Equal(t, "The bearer token sent for the 'OAuth' security scheme was rejected: expired", err.Reason)
+
+	err = SecurityScopesMissing("OAuth", scheme, requirement, []string{"read", "write"})
+	assert.Equal(t, helpers.SecurityScopes, err.ValidationSubType)
+	assert.Equal(t, "The bearer token sent for the 'OAuth' security scheme is not granted the required scopes "+
+		"'read', 'write'", err.Reason)
+	assert.Equal(t, "Request a token that is granted the missing scopes: read, write", err.HowToFix)
+
+	jwtScheme := &v3.SecurityScheme{Type: "http", Scheme: "bearer", BearerFormat: "JWT"}
+	err = JWTInvalid("JWT", jwtScheme, requirement, fmt.Errorf("token has expired"))
+	assert.Equal(t, "bearer", err.ValidationSubType)
+	assert.Equal(t, "Bearer token for 'JWT' scheme is not a valid JWT", err.Message)
+	assert.Equal(t, "The JWT sent for the 'JWT' security scheme is not valid: token has expired", err.Reason)
+	assert.Equal(t, HowToFixInvalidJWT, err.HowToFix)
+}
+
+func TestCredentialsErrors(t *testing.T) {
+	basic := &v3.SecurityScheme{Type: "http", Scheme: "basic"}
+	digest := &v3.SecurityScheme{Type: "http", Scheme: "Digest"}
+
+	err := CredentialsMissing("BasicAuth", basic, nil, "Bearer")
+	assert.Equal(t, helpers.SecurityValidation, err.ValidationType)
+	assert.Equal(t, helpers.CredentialsMissing, err.ValidationSubType)
+	assert.Equal(t, "Basic credentials for 'BasicAuth' scheme not found", err.Message)
+	assert.Equal(t, "The 'BasicAuth' security scheme requires 'Basic' credentials, however the 'Authorization' "+
+		"header of the request uses the 'Bearer' scheme", err.Reason)
+	assert.Equal(t, HowToFixBasicCredentials, err.HowToFix)
+
+	err = CredentialsMalformed("DigestAuth", digest, nil, fmt.Errorf("the 'nonce' parameter is missing"))
+	assert.Equal(t, helpers.CredentialsMalformed, err.ValidationSubType)
+	assert.Equal(t, "Digest credentials for 'DigestAuth' scheme are malformed", err.Message)
+	assert.Equal(t, "The 'Digest' credentials sent for the 'DigestAuth' security scheme are malformed: the "+
+		"'nonce' parameter is missing", err.Reason)
+	assert.Equal(t, HowToFixDigestCredentials, err.HowToFix)
+
+	err = CredentialsRejected("BasicAuth", basic, nil, fmt.Errorf("unknown user"))
+	assert.Equal(t, helpers.CredentialsRejected, err.ValidationSubType)
+	assert.Equal(t, "Basic credentials for 'BasicAuth' scheme are not valid", err.Message)
+	assert.Equal(t, "Send credentials of a user who is allowed to access the 'BasicAuth' security scheme", err.HowToFix)
+}
+
+func TestClientCertificateErrors(t *testing.T) {
+	scheme := &v3.SecurityScheme{Type: "mutualTLS"}
+
+	err := ClientCertificateMissing("ClientCert", scheme, nil, "the request was not sent over TLS")
+	assert.Equal(t, helpers.SecurityValidation, err.ValidationType)
+	assert.Equal(t, helpers.CertificateMissing, err.ValidationSubType)
+	assert.Equal(t, "Client certificate for 'ClientCert' scheme not found", err.Message)
+	assert.Equal(t, "The 'ClientCert' security scheme (mutualTLS) requires a verified client certificate, however "+
+		"the request was not sent over TLS", err.Reason)
+	assert.Equal(t, HowToFixClientCertMissing, err.HowToFix)
+
+	err = ClientCertificateRejected("ClientCert", scheme, nil, "CN=fries", fmt.Errorf("unknown client"))
+	assert.Equal(t, helpers.CertificateRejected, err.ValidationSubType)
+	assert.Equal(t, "The client certificate 'CN=fries' sent for the 'ClientCert' security scheme was rejected: "+
+		"unknown client", err.Reason)
+	assert.Equal(t, "Send a client certificate that is issued for the 'ClientCert' security scheme", err.HowToFix)
+}
+
+func TestExplainSecurityRequirement(t *testing.T) {
+	validationErrors := []*ValidationError{{Reason: "API Key not found"}}
+	ExplainSecurityRequirement(validationErrors, "ApiKey", []string{"ApiKey", "OAuth"}, 1, 3)
+	assert.Equal(t, "API Key not found (security requirement 1 of 3 requires 'ApiKey' and 'OAuth', "+
+		"'ApiKey' is not satisfied)", validationErrors[0].Reason)
+}
@@ -135,3 +135,20 @@ func (v *ValidationError) IsPathMissingError() bool {
 func (v *ValidationError) IsOperationMissingError() bool {
 	return v.ValidationType == "path" && v.ValidationSubType == "missingOperation"
 }
+
+// IsPathAmbiguousError returns true if the error has a ValidationType of "path" and a ValidationSubType of "ambiguous"
+func (v *ValidationError) IsPathAmbiguousError() bool {
+	return v.ValidationType == "path" && v.ValidationSubType == "ambiguous"
+}
+
+// IsNotAcceptableError returns true if the error has a ValidationType of "response" and a ValidationSubType of
+// "notAcceptable", which means the service should have responded with '406 Not Acceptable'.
+func (v *ValidationError) IsNotAcceptableError() bool {
+	return v.ValidationType == "response" && v.ValidationSubType == "notAcceptable"
+}
+
+// IsCancellationError returns true if the error has a ValidationType of "context", which means validation was
+// abandoned because the context was cancelled or its deadline expired.
+func (v *ValidationError) IsCancellationError() bool {
+	return v.ValidationType == "context"
+}
@@ -116,3 +116,19 @@ func TestValidationError_IsOperationMissingError(t *testing.T) {
 	v.ValidationSubType = "missingOperation"
 	require.False(t, v.IsOperationMissingError())
 }
+
+func TestValidationError_IsPathAmbiguousError(t *testing.T) {
+	v := &ValidationError{
+		ValidationType:    "path",
+		ValidationSubType: "ambiguous",
+	}
+
+	require.True(t, v.IsPathAmbiguousError())
+
+	v.ValidationSubType = "missing"
+	require.False(t, v.IsPathAmbiguousError())
+
+	v.ValidationType = "request"
+	v.ValidationSubType = "ambiguous"
+	require.False(t, v.IsPathAmbiguousError())
+}
@@ -14,8 +14,25 @@ const (
 	Schema                    = "schema"
 	ResponseBodyValidation    = "response"
 	RequestBodyContentType    = "contentType"
+	MultipartContentType      = "multipartContentType"
+	MultipartHeader           = "multipartHeader"
+	MultipartMemoryLimit      = "multipartMemoryLimit"
+	ReadOnly                  = "readOnly"
+	WriteOnly                 = "writeOnly"
 	RequestMissingOperation   = "missingOperation"
 	ResponseBodyResponseCode  = "statusCode"
+	ResponseNotAcceptable     = "notAcceptable"
+	PathAmbiguous             = "ambiguous"
+	ContextValidation         = "context"
+	SecurityValidation        = "security"
+	SecurityScopes            = "scopes"
+	CredentialsMissing        = "credentialsMissing"
+	CredentialsMalformed      = "credentialsMalformed"
+	CredentialsRejected       = "credentialsRejected"
+	CertificateMissing        = "certificateMissing"
+	CertificateRejected       = "certificateRejected"
+	ContextCancelled          = "cancelled"
+	ContextDeadlineExceeded   = "deadlineExceeded"
 	SpaceDelimited            = "spaceDelimited"
 	PipeDelimited             = "pipeDelimited"
 	DefaultDelimited          = "default"
@@ -41,10 +58,19 @@ const (
 	Path                      = "path"
 	Form                      = "form"
 	Query                     = "query"
+	QueryString               = "querystring"
+	MethodQuery               = "QUERY"
 	JSONContentType           = "application/json"
 	JSONType                  = "json"
+	FormURLEncodedContentType = "application/x-www-form-urlencoded"
+	MultipartFormDataType     = "multipart/form-data"
+	EventStreamContentType    = "text/event-stream"
 	ContentTypeHeader         = "Content-Type"
 	AuthorizationHeader       = "Authorization"
+	BearerScheme              = "Bearer"
+	BasicScheme               = "Basic"
+	DigestScheme              = "Digest"
+	AcceptHeader              = "Accept"
 	Charset                   = "charset"
 	Boundary                  = "boundary"
 	Preferred                 = "preferred"
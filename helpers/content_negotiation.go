@@ -0,0 +1,160 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"mime"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// MediaRange is a media range of an Accept header, with its quality value.
+type MediaRange struct {
+	Type    string            // e.g. application, or * for any type
+	Subtype string            // e.g. json, or * for any subtype
+	Params  map[string]string // media type parameters, without the quality value
+	Quality float64           // q, 1 when not set
+}
+
+// specificity ranks a media range: */* < type/* < type/subtype < type/subtype;params.
+func (m MediaRange) specificity() int {
+	switch {
+	case m.Type == Asterisk:
+		return 0
+	case m.Subtype == Asterisk:
+		return 1
+	case len(m.Params) == 0:
+		return 2
+	}
+	return 3
+}
+
+// matches returns true if the media type (and its parameters) falls within the media range.
+func (m MediaRange) matches(typ, subtype string, params map[string]string) bool {
+	if m.Type != Asterisk && m.Type != typ {
+		return false
+	}
+	if m.Subtype != Asterisk && m.Subtype != subtype {
+		return false
+	}
+	for name, value := range m.Params {
+		if !strings.EqualFold(params[name], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// overlaps returns true if the media range shares any media type with another range.
+func (m MediaRange) overlaps(typ, subtype string) bool {
+	if m.Type != Asterisk && typ != Asterisk && m.Type != typ {
+		return false
+	}
+	return m.Subtype == Asterisk || subtype == Asterisk || m.Subtype == subtype
+}
+
+// ParseAccept parses an Accept header into its media ranges, in the order they are listed. Ranges that cannot be
+// parsed are skipped, the quality value defaults to 1 and is clamped between 0 and 1.
+func ParseAccept(accept string) []MediaRange {
+	var ranges []MediaRange
+	for _, entry := range strings.Split(accept, Comma) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		mt, params, err := mime.ParseMediaType(entry)
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mt, Slash)
+		if !ok || typ == "" || subtype == "" || (typ == Asterisk && subtype != Asterisk) {
+			continue
+		}
+		r := MediaRange{Type: typ, Subtype: subtype, Quality: 1}
+		for name, value := range params {
+			if name == "q" {
+				if q, err := strconv.ParseFloat(value, 64); err == nil {
+					r.Quality = min(max(q, 0), 1)
+				}
+				continue
+			}
+			if r.Params == nil {
+				r.Params = make(map[string]string)
+			}
+			r.Params[name] = value
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// AcceptQuality returns the quality an Accept header gives to a content type, the quality of the most specific
+// media range that the content type falls within (RFC 9110, section 12.5.1). It returns 1 when the header is empty
+// (anything is acceptable), and 0 when no range matches. The content type may itself be a media range (as the keys
+// of a content map can be), it then gets the highest quality of the ranges it overlaps with.
+func AcceptQuality(contentType, accept string) float64 {
+	if strings.TrimSpace(accept) == "" {
+		return 1
+	}
+	mt, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0
+	}
+	typ, subtype, _ := strings.Cut(mt, Slash)
+
+	best := -1
+	quality := 0.0
+	for _, r := range ParseAccept(accept) {
+		if typ == Asterisk || subtype == Asterisk {
+			if r.overlaps(typ, subtype) && r.Quality > quality {
+				quality = r.Quality
+			}
+			continue
+		}
+		if r.matches(typ, subtype, params) && r.specificity() > best {
+			best = r.specificity()
+			quality = r.Quality
+		}
+	}
+	return quality
+}
+
+// IsAcceptable returns true if the Accept header of a request accepts the content type of a response.
+func IsAcceptable(contentType, accept string) bool {
+	return AcceptQuality(contentType, accept) > 0
+}
+
+// FindMediaType looks up the media type that describes a content type in a content map. An exact match wins, then
+// the base of a structured syntax suffix (application/problem+json is described by application/json), then a
+// media range of the type (application/*), and finally */*. It returns the key of the media type in the map.
+func FindMediaType(contentType string, content *orderedmap.Map[string, *v3.MediaType]) (string, *v3.MediaType, bool) {
+	if content == nil {
+		return "", nil, false
+	}
+	ct, _, _ := ExtractContentType(contentType)
+	ct = strings.ToLower(ct)
+	typ, subtype, ok := strings.Cut(ct, Slash)
+	if !ok {
+		return "", nil, false
+	}
+
+	candidates := []string{ct}
+	if i := strings.LastIndex(subtype, "+"); i >= 0 && i < len(subtype)-1 {
+		candidates = append(candidates, typ+Slash+subtype[i+1:])
+	}
+	candidates = append(candidates, typ+"/*", "*/*")
+
+	for _, candidate := range candidates {
+		for key, mediaType := range content.FromOldest() {
+			if strings.EqualFold(strings.TrimSpace(key), candidate) {
+				return key, mediaType, true
+			}
+		}
+	}
+	return "", nil, false
+}
@@ -0,0 +1,97 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+func TestParseAccept(t *testing.T) {
+	ranges := ParseAccept("text/html, application/json;q=0.5, */*;q=0, image/*;q=2, bad, text/plain;level=1")
+	require.Len(t, ranges, 5)
+
+	assert.Equal(t, MediaRange{Type: "text", Subtype: "html", Quality: 1}, ranges[0])
+	assert.Equal(t, 0.5, ranges[1].Quality)
+	assert.Equal(t, 0.0, ranges[2].Quality)
+	assert.Equal(t, 1.0, ranges[3].Quality) // clamped
+	assert.Equal(t, map[string]string{"level": "1"}, ranges[4].Params)
+
+	assert.Empty(t, ParseAccept(""))
+	assert.Empty(t, ParseAccept("*/json"))
+}
+
+func TestAcceptQuality(t *testing.T) {
+	tests := []struct {
+		contentType string
+		accept      string
+		quality     float64
+	}{
+		{"application/json", "", 1},
+		{"application/json", "application/json", 1},
+		{"application/json; charset=utf-8", "application/json;q=0.8", 0.8},
+		{"application/json", "application/*;q=0.3", 0.3},
+		{"application/json", "*/*;q=0.1", 0.1},
+		{"application/json", "text/html", 0},
+		{"application/problem+json", "application/json", 0},
+		// the most specific range wins, whatever its order
+		{"text/plain", "text/*;q=0, text/plain", 1},
+		{"text/html", "text/*;q=0, text/plain", 0},
+		{"text/html", "*/*, text/html;q=0", 0},
+		{"text/html; level=1", "text/html;level=1;q=0.7, text/html;q=0.2", 0.7},
+		{"text/html", "text/html;level=1;q=0.7, text/html;q=0.2", 0.2},
+		{"not a type", "*/*", 0},
+		// media ranges take the best quality of the ranges they overlap with
+		{"text/*", "text/html;q=0.4, */*;q=0.1", 0.4},
+		{"*/*", "image/png;q=0.3", 0.3},
+		{"text/*", "application/json", 0},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.quality, AcceptQuality(tt.contentType, tt.accept), "%s / %s", tt.contentType, tt.accept)
+	}
+	assert.True(t, IsAcceptable("application/json", "application/*"))
+	assert.False(t, IsAcceptable("application/json", "application/json;q=0"))
+}
+
+func TestFindMediaType(t *testing.T) {
+	content := orderedmap.New[string, *v3.MediaType]()
+	json, xml, anyImage, anything := &v3.MediaType{}, &v3.MediaType{}, &v3.MediaType{}, &v3.MediaType{}
+	content.Set("*/*", anything)
+	content.Set("image/*", anyImage)
+	content.Set("application/json", json)
+	content.Set("application/xml", xml)
+
+	tests := []struct {
+		contentType string
+		key         string
+		mediaType   *v3.MediaType
+	}{
+		{"application/json", "application/json", json},
+		{"Application/JSON; charset=utf-8", "application/json", json},
+		{"application/problem+json", "application/json", json},
+		{"application/vnd.burger+xml", "application/xml", xml},
+		{"image/png", "image/*", anyImage},
+		{"text/plain", "*/*", anything},
+	}
+	for _, tt := range tests {
+		key, mediaType, ok := FindMediaType(tt.contentType, content)
+		assert.True(t, ok, tt.contentType)
+		assert.Equal(t, tt.key, key, tt.contentType)
+		assert.Same(t, tt.mediaType, mediaType, tt.contentType)
+	}
+
+	exact := orderedmap.New[string, *v3.MediaType]()
+	exact.Set("application/json", json)
+	_, _, ok := FindMediaType("text/plain", exact)
+	assert.False(t, ok)
+	_, _, ok = FindMediaType("", exact)
+	assert.False(t, ok)
+	_, _, ok = FindMediaType("application/json", nil)
+	assert.False(t, ok)
+}
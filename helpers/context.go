@@ -0,0 +1,43 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"context"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// ValidateSchemaWithContext validates v against a compiled schema, returning early with the context error if ctx is
+// done before the evaluation completes.
+//
+// The jsonschema library cannot be interrupted mid-evaluation, so when ctx can be cancelled the evaluation runs on
+// its own goroutine; an abandoned evaluation finishes in the background and its result is discarded. A nil ctx, or
+// one that can never be cancelled, validates inline.
+func ValidateSchemaWithContext(ctx context.Context, schema *jsonschema.Schema, v any) error {
+	if ctx == nil || ctx.Done() == nil {
+		return schema.Validate(v)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	result := make(chan error, 1)
+	go func() {
+		result <- schema.Validate(v)
+	}()
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ContextError returns the error of the supplied context, or nil if the context is nil or still live.
+func ContextError(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,77 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func compileBlockingSchema(t *testing.T, release chan struct{}) *jsonschema.Schema {
+	opts := config.NewValidationOptions(
+		config.WithFormatAssertions(),
+		config.WithCustomFormat("blocking", func(v any) error {
+			<-release
+			return nil
+		}))
+	sch, err := NewCompiledSchema("blocking", []byte(`{"type": "string", "format": "blocking"}`), opts)
+	require.NoError(t, err)
+	return sch
+}
+
+func TestValidateSchemaWithContext_NilContext(t *testing.T) {
+	sch, err := NewCompiledSchema("string", []byte(`{"type": "string"}`), nil)
+	require.NoError(t, err)
+
+	//nolint:staticcheck // a nil context is explicitly supported.
+	assert.NoError(t, ValidateSchemaWithContext(nil, sch, "burger"))
+	assert.Error(t, ValidateSchemaWithContext(context.Background(), sch, 123))
+}
+
+func TestValidateSchemaWithContext_Completes(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	sch := compileBlockingSchema(t, release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	assert.NoError(t, ValidateSchemaWithContext(ctx, sch, "burger"))
+}
+
+func TestValidateSchemaWithContext_AlreadyCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	sch := compileBlockingSchema(t, release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, ValidateSchemaWithContext(ctx, sch, "burger"), context.Canceled)
+}
+
+func TestValidateSchemaWithContext_DeadlineExceeded(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	sch := compileBlockingSchema(t, release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, ValidateSchemaWithContext(ctx, sch, "burger"), context.DeadlineExceeded)
+}
+
+func TestContextError(t *testing.T) {
+	//nolint:staticcheck // a nil context is explicitly supported.
+	assert.NoError(t, ContextError(nil))
+	assert.NoError(t, ContextError(context.Background()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, ContextError(ctx), context.Canceled)
+}
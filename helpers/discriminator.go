@@ -0,0 +1,178 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"encoding/json"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	"github.com/pb33f/libopenapi-validator/openapi_vocabulary"
+)
+
+// AnnotateDiscriminators records the references of the oneOf (or anyOf) schemas of every schema with a
+// discriminator, in its discriminator. Rendering a schema inline replaces references with the schemas they point
+// to, so the schema names a discriminator selects by are lost from the rendered JSON. Compositions with a schema
+// that is not a reference are left as they are. The schema is returned as-is when nothing is recorded.
+func AnnotateDiscriminators(schema *base.Schema, jsonSchema []byte) []byte {
+	if schema == nil {
+		return jsonSchema
+	}
+	var rendered interface{}
+	if err := json.Unmarshal(jsonSchema, &rendered); err != nil {
+		return jsonSchema
+	}
+	if !annotateDiscriminatorsInSchema(schema, rendered) {
+		return jsonSchema
+	}
+	result, err := json.Marshal(rendered)
+	if err != nil {
+		return jsonSchema
+	}
+	return result
+}
+
+// annotateDiscriminatorsInSchema walks a schema and its rendered JSON together, it returns true if any
+// discriminator was annotated.
+func annotateDiscriminatorsInSchema(schema *base.Schema, rendered interface{}) bool {
+	s, ok := rendered.(map[string]interface{})
+	if !ok || schema == nil {
+		return false
+	}
+
+	changed := false
+	if discriminator, ok := s["discriminator"].(map[string]interface{}); ok && schema.Discriminator != nil {
+		key, proxies := "oneOf", schema.OneOf
+		if len(proxies) == 0 {
+			key, proxies = "anyOf", schema.AnyOf
+		}
+		if refs := schemaReferences(proxies); refs != nil && len(refs) == len(asSlice(s[key])) {
+			discriminator[openapi_vocabulary.DiscriminatorRefsKeyword] = refs
+			changed = true
+		}
+	}
+
+	walk := func(proxy *base.SchemaProxy, value interface{}) {
+		if proxy != nil && value != nil && annotateDiscriminatorsInSchema(proxy.Schema(), value) {
+			changed = true
+		}
+	}
+	walkAll := func(proxies []*base.SchemaProxy, value interface{}) {
+		values := asSlice(value)
+		for i, proxy := range proxies {
+			if i < len(values) {
+				walk(proxy, values[i])
+			}
+		}
+	}
+	walkMap := func(proxies *orderedmap.Map[string, *base.SchemaProxy], value interface{}) {
+		values, _ := value.(map[string]interface{})
+		for name, proxy := range proxies.FromOldest() {
+			walk(proxy, values[name])
+		}
+	}
+	walkDynamic := func(dv *base.DynamicValue[*base.SchemaProxy, bool], value interface{}) {
+		if dv != nil && dv.IsA() {
+			walk(dv.A, value)
+		}
+	}
+
+	walkAll(schema.AllOf, s["allOf"])
+	walkAll(schema.OneOf, s["oneOf"])
+	walkAll(schema.AnyOf, s["anyOf"])
+	walkAll(schema.PrefixItems, s["prefixItems"])
+	walkMap(schema.Properties, s["properties"])
+	walkMap(schema.PatternProperties, s["patternProperties"])
+	walkDynamic(schema.Items, s["items"])
+	walkDynamic(schema.AdditionalProperties, s["additionalProperties"])
+	walk(schema.Not, s["not"])
+	walk(schema.If, s["if"])
+	walk(schema.Then, s["then"])
+	walk(schema.Else, s["else"])
+	walk(schema.Contains, s["contains"])
+	return changed
+}
+
+// schemaReferences returns the references of the schemas, or nil if any of them is not a reference.
+func schemaReferences(proxies []*base.SchemaProxy) []interface{} {
+	if len(proxies) == 0 {
+		return nil
+	}
+	refs := make([]interface{}, 0, len(proxies))
+	for _, proxy := range proxies {
+		if proxy == nil || !proxy.IsReference() || proxy.GetReference() == "" {
+			return nil
+		}
+		refs = append(refs, proxy.GetReference())
+	}
+	return refs
+}
+
+// transformSchemaForDiscriminators moves the oneOf (or anyOf) schemas of every annotated discriminator (see
+// AnnotateDiscriminators) into the discriminator, so that the OpenAPI vocabulary validates a payload against the
+// schema its discriminator value selects, instead of every schema of the composition.
+func transformSchemaForDiscriminators(jsonSchema []byte) []byte {
+	var schema interface{}
+	if err := json.Unmarshal(jsonSchema, &schema); err != nil {
+		return jsonSchema
+	}
+	if !routeDiscriminatorsInSchema(schema) {
+		return jsonSchema
+	}
+	result, err := json.Marshal(schema)
+	if err != nil {
+		return jsonSchema
+	}
+	return result
+}
+
+// routeDiscriminatorsInSchema moves the schemas of annotated discriminators, in place. It returns true if any
+// schema was moved.
+func routeDiscriminatorsInSchema(schema interface{}) bool {
+	changed := false
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		for key, value := range s {
+			switch key {
+			case "enum", "const", "default", "example", "examples":
+				continue // values, not schemas
+			}
+			if routeDiscriminatorsInSchema(value) {
+				changed = true
+			}
+		}
+
+		discriminator, ok := s["discriminator"].(map[string]interface{})
+		if !ok {
+			break
+		}
+		refs := asSlice(discriminator[openapi_vocabulary.DiscriminatorRefsKeyword])
+		if len(refs) == 0 {
+			break
+		}
+		key := "oneOf"
+		if _, ok := s[key]; !ok {
+			key = "anyOf"
+		}
+		if members := asSlice(s[key]); len(members) == len(refs) {
+			discriminator[openapi_vocabulary.DiscriminatorSchemasKeyword] = members
+			delete(s, key)
+			changed = true
+		}
+
+	case []interface{}:
+		for _, item := range s {
+			if routeDiscriminatorsInSchema(item) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+func asSlice(value interface{}) []interface{} {
+	values, _ := value.([]interface{})
+	return values
+}
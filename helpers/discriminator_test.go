@@ -0,0 +1,114 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/openapi_vocabulary"
+)
+
+var discriminatorSpec = `openapi: 3.1.0
+components:
+  schemas:
+    Order:
+      type: object
+      properties:
+        pet:
+          $ref: '#/components/schemas/Pet'
+        anything:
+          oneOf:
+            - $ref: '#/components/schemas/Cat'
+            - type: string
+          discriminator:
+            propertyName: petType
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: petType
+    Cat:
+      type: object
+      properties:
+        petType:
+          type: string
+        lives:
+          type: integer
+    Dog:
+      type: object
+      properties:
+        petType:
+          type: string
+        bark:
+          type: string`
+
+func TestAnnotateDiscriminators(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(discriminatorSpec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	schema := m.Model.Components.Schemas.GetOrZero("Order").Schema()
+
+	rendered, err := schema.RenderInline()
+	require.NoError(t, err)
+	jsonSchema, _ := utils.ConvertYAMLtoJSON(rendered)
+
+	var annotated map[string]any
+	require.NoError(t, json.Unmarshal(AnnotateDiscriminators(schema, jsonSchema), &annotated))
+	properties := annotated["properties"].(map[string]any)
+
+	pet := properties["pet"].(map[string]any)["discriminator"].(map[string]any)
+	assert.Equal(t, []any{"#/components/schemas/Cat", "#/components/schemas/Dog"},
+		pet[openapi_vocabulary.DiscriminatorRefsKeyword])
+
+	// a composition with an inline schema cannot be selected by name
+	anything := properties["anything"].(map[string]any)["discriminator"].(map[string]any)
+	assert.NotContains(t, anything, openapi_vocabulary.DiscriminatorRefsKeyword)
+
+	// nothing to annotate
+	cat := m.Model.Components.Schemas.GetOrZero("Cat").Schema()
+	assert.Equal(t, []byte(`{"type":"object"}`), AnnotateDiscriminators(cat, []byte(`{"type":"object"}`)))
+	assert.Equal(t, []byte(`not json`), AnnotateDiscriminators(cat, []byte(`not json`)))
+	assert.Equal(t, []byte(`{}`), AnnotateDiscriminators(nil, []byte(`{}`)))
+}
+
+func TestNewCompiledSchema_DiscriminatorRouting(t *testing.T) {
+	schema := []byte(`{
+  "oneOf": [
+    {"type": "object", "properties": {"petType": {"type": "string"}, "lives": {"type": "integer"}}},
+    {"type": "object", "properties": {"petType": {"type": "string"}, "bark": {"type": "string"}}}
+  ],
+  "discriminator": {
+    "propertyName": "petType",
+    "x-discriminator-refs": ["#/components/schemas/Cat", "#/components/schemas/Dog"]
+  }
+}`)
+
+	routed := transformSchemaForDiscriminators(schema)
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal(routed, &decoded))
+	assert.NotContains(t, decoded, "oneOf")
+	assert.Len(t, decoded["discriminator"].(map[string]any)[openapi_vocabulary.DiscriminatorSchemasKeyword], 2)
+
+	// both schemas accept {"petType": "Cat"}, which fails oneOf, but the discriminator selects the Cat schema
+	jsch, err := NewCompiledSchema("pet", schema, config.NewValidationOptions())
+	require.NoError(t, err)
+	assert.NoError(t, jsch.Validate(map[string]any{"petType": "Cat"}))
+	assert.Error(t, jsch.Validate(map[string]any{"petType": "Cat", "lives": "nine"}))
+	assert.Error(t, jsch.Validate(map[string]any{"petType": "Hamster"}))
+
+	// strict additionalProperties closes the composed schema, the properties of the selected schema are evaluated
+	strict := config.NewValidationOptions(config.WithStrictAdditionalProperties(config.StrictPropertiesAll))
+	jsch, err = NewCompiledSchemaForDirection("pet", schema, strict, 3.1, RequestDirection)
+	require.NoError(t, err)
+	assert.NoError(t, jsch.Validate(map[string]any{"petType": "Cat", "lives": 9}))
+	assert.Error(t, jsch.Validate(map[string]any{"petType": "Cat", "bark": "woof"}))
+}
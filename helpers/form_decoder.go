@@ -0,0 +1,288 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"encoding/json"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// IsFormURLEncoded returns true if the content type is application/x-www-form-urlencoded.
+func IsFormURLEncoded(contentType string) bool {
+	ct, _, _ := ExtractContentType(contentType)
+	return strings.EqualFold(ct, FormURLEncodedContentType)
+}
+
+// IsMultipartFormData returns true if the content type is multipart/form-data.
+func IsMultipartFormData(contentType string) bool {
+	ct, _, _ := ExtractContentType(contentType)
+	return strings.EqualFold(ct, MultipartFormDataType)
+}
+
+// DecodeFormURLEncoded decodes an application/x-www-form-urlencoded body into an object that can be validated
+// against the schema of the media type. Each property is decoded according to its entry in the encoding map of the
+// media type:
+//
+//   - style form (the default), spaceDelimited, pipeDelimited or deepObject, and explode (true by default for form),
+//     decide how arrays and objects are serialized.
+//   - a JSON contentType means the value is a JSON document.
+//
+// Values are coerced to the types of the property schemas (see CastValueForSchema). Fields that are not properties
+// of the schema are kept as strings (or arrays of strings, when repeated), so additionalProperties still applies.
+func DecodeFormURLEncoded(body []byte, schema *base.Schema, encoding *orderedmap.Map[string, *v3.Encoding]) (map[string]any, error) {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	decoded := make(map[string]any, len(values))
+	consumed := make(map[string]bool, len(values))
+
+	for _, name := range propertyNames(schema) {
+		var enc *v3.Encoding
+		if encoding != nil {
+			enc = encoding.GetOrZero(name)
+		}
+		if value, ok := decodeFormProperty(name, PropertySchema(schema, name), enc, values, consumed); ok {
+			decoded[name] = value
+		}
+	}
+
+	for name, vals := range values {
+		if consumed[name] {
+			continue
+		}
+		if len(vals) == 1 {
+			decoded[name] = vals[0]
+			continue
+		}
+		items := make([]any, len(vals))
+		for i := range vals {
+			items[i] = vals[i]
+		}
+		decoded[name] = items
+	}
+	return decoded, nil
+}
+
+func decodeFormProperty(name string, sch *base.Schema, enc *v3.Encoding, values url.Values,
+	consumed map[string]bool,
+) (any, bool) {
+	style := Form
+	explode := true
+	if enc != nil {
+		if enc.Style != "" {
+			style = enc.Style
+		}
+		if enc.Explode != nil {
+			explode = *enc.Explode
+		} else {
+			explode = style == Form
+		}
+		if enc.ContentType != "" && strings.Contains(strings.ToLower(enc.ContentType), JSONType) {
+			vals, ok := values[name]
+			if !ok || len(vals) == 0 {
+				return nil, false
+			}
+			consumed[name] = true
+			var v any
+			if err := json.Unmarshal([]byte(vals[0]), &v); err != nil {
+				return vals[0], true
+			}
+			return v, true
+		}
+	}
+
+	switch SchemaPrimaryType(sch) {
+	case Array:
+		vals, ok := values[name]
+		if !ok {
+			return nil, false
+		}
+		consumed[name] = true
+		if !explode && len(vals) > 0 {
+			separator := Comma
+			switch style {
+			case SpaceDelimited:
+				separator = Space
+			case PipeDelimited:
+				separator = Pipe
+			}
+			vals = strings.Split(vals[0], separator)
+		}
+		var items *base.Schema
+		if sch.Items != nil && sch.Items.IsA() {
+			items = sch.Items.A.Schema()
+		}
+		decoded := make([]any, 0, len(vals))
+		for _, v := range vals {
+			decoded = append(decoded, CastValueForSchema(v, items))
+		}
+		return decoded, true
+
+	case Object:
+		if style == DeepObject {
+			props := make(map[string]any)
+			prefix := name + "["
+			for key, vals := range values {
+				if strings.HasPrefix(key, prefix) && strings.HasSuffix(key, "]") && len(vals) > 0 {
+					consumed[key] = true
+					prop := key[len(prefix) : len(key)-1]
+					props[prop] = CastValueForSchema(vals[0], PropertySchema(sch, prop))
+				}
+			}
+			return props, len(props) > 0
+		}
+		if !explode {
+			vals, ok := values[name]
+			if !ok || len(vals) == 0 {
+				return nil, false
+			}
+			consumed[name] = true
+			props := make(map[string]any)
+			pairs := strings.Split(vals[0], Comma)
+			for i := 0; i+1 < len(pairs); i += 2 {
+				props[pairs[i]] = CastValueForSchema(pairs[i+1], PropertySchema(sch, pairs[i]))
+			}
+			return props, true
+		}
+		// exploded form objects spread their properties across the form.
+		props := make(map[string]any)
+		for _, prop := range propertyNames(sch) {
+			if vals, ok := values[prop]; ok && len(vals) > 0 {
+				consumed[prop] = true
+				props[prop] = CastValueForSchema(vals[0], PropertySchema(sch, prop))
+			}
+		}
+		return props, len(props) > 0
+	}
+
+	vals, ok := values[name]
+	if !ok || len(vals) == 0 {
+		return nil, false
+	}
+	consumed[name] = true
+	return CastValueForSchema(vals[0], sch), true
+}
+
+// CastValueForSchema converts a string value to the first primitive type of the schema it is valid for: integers
+// to int64, finite numbers to float64 and booleans (only "true" and "false", as for parameters) to bool. A value
+// that fits none of them is returned as-is, so schema validation can report it.
+func CastValueForSchema(value string, sch *base.Schema) any {
+	if sch == nil {
+		return value
+	}
+	for _, typ := range sch.Type {
+		switch typ {
+		case Integer:
+			if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+				return i
+			}
+		case Number:
+			if f, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+				return f
+			}
+		case Boolean:
+			if value == "true" || value == "false" {
+				return value == "true"
+			}
+		}
+	}
+	return value
+}
+
+// maxCompositionDepth bounds how deep SchemaPrimaryType and PropertySchema look through allOf, oneOf and anyOf, so
+// circular compositions end.
+const maxCompositionDepth = 16
+
+// SchemaPrimaryType returns array or object when the schema is one of them, or an empty string for primitives.
+// A schema without a type of its own takes the type of the first schema it is composed of (allOf, oneOf, then
+// anyOf) that is an array or an object.
+func SchemaPrimaryType(sch *base.Schema) string {
+	return schemaPrimaryType(sch, 0)
+}
+
+func schemaPrimaryType(sch *base.Schema, depth int) string {
+	if sch == nil || depth > maxCompositionDepth {
+		return ""
+	}
+	for _, typ := range sch.Type {
+		if typ == Array || typ == Object {
+			return typ
+		}
+	}
+	if len(sch.Type) > 0 {
+		return ""
+	}
+	for _, proxy := range compositionOf(sch) {
+		if typ := schemaPrimaryType(proxy.Schema(), depth+1); typ != "" {
+			return typ
+		}
+	}
+	return ""
+}
+
+// PropertySchema returns the schema of a property, looked up in the properties of the schema, then in those of the
+// schemas it is composed of (allOf, oneOf, then anyOf). It returns nil when no schema defines the property.
+func PropertySchema(sch *base.Schema, name string) *base.Schema {
+	return propertySchema(sch, name, 0)
+}
+
+func propertySchema(sch *base.Schema, name string, depth int) *base.Schema {
+	if sch == nil || depth > maxCompositionDepth {
+		return nil
+	}
+	if sch.Properties != nil {
+		if proxy := sch.Properties.GetOrZero(name); proxy != nil {
+			return proxy.Schema()
+		}
+	}
+	for _, proxy := range compositionOf(sch) {
+		if property := propertySchema(proxy.Schema(), name, depth+1); property != nil {
+			return property
+		}
+	}
+	return nil
+}
+
+// propertyNames returns the names of the properties of a schema, followed by those of the schemas it is composed of
+// (allOf, oneOf, then anyOf) that it does not define itself.
+func propertyNames(sch *base.Schema) []string {
+	var names []string
+	seen := make(map[string]bool)
+	collectPropertyNames(sch, seen, &names, 0)
+	return names
+}
+
+func collectPropertyNames(sch *base.Schema, seen map[string]bool, names *[]string, depth int) {
+	if sch == nil || depth > maxCompositionDepth {
+		return
+	}
+	if sch.Properties != nil {
+		for name := range sch.Properties.KeysFromOldest() {
+			if !seen[name] {
+				seen[name] = true
+				*names = append(*names, name)
+			}
+		}
+	}
+	for _, proxy := range compositionOf(sch) {
+		collectPropertyNames(proxy.Schema(), seen, names, depth+1)
+	}
+}
+
+// compositionOf returns the allOf, oneOf and anyOf schemas of a schema, in that order.
+func compositionOf(sch *base.Schema) []*base.SchemaProxy {
+	var proxies []*base.SchemaProxy
+	proxies = append(proxies, sch.AllOf...)
+	proxies = append(proxies, sch.OneOf...)
+	return append(proxies, sch.AnyOf...)
+}
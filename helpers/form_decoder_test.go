@@ -0,0 +1,193 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+func buildFormMediaType(t *testing.T) *v3.MediaType {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    post:
+      requestBody:
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                patties:
+                  type: integer
+                price:
+                  type: number
+                vegan:
+                  type: boolean
+                sizes:
+                  type: array
+                  items:
+                    type: integer
+                tags:
+                  type: array
+                  items:
+                    type: string
+                codes:
+                  type: array
+                  items:
+                    type: string
+                filter:
+                  type: object
+                  properties:
+                    min:
+                      type: integer
+                    max:
+                      type: integer
+                pair:
+                  type: object
+                address:
+                  type: object
+                  properties:
+                    street:
+                      type: string
+                    zip:
+                      type: integer
+                meta:
+                  type: object
+            encoding:
+              tags:
+                style: spaceDelimited
+                explode: false
+              codes:
+                style: pipeDelimited
+              filter:
+                style: deepObject
+              pair:
+                explode: false
+              meta:
+                contentType: application/json; charset=utf-8`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	return m.Model.Paths.PathItems.GetOrZero("/burgers").Post.RequestBody.Content.GetOrZero(FormURLEncodedContentType)
+}
+
+func TestDecodeFormURLEncoded(t *testing.T) {
+	mt := buildFormMediaType(t)
+
+	decoded, err := DecodeFormURLEncoded([]byte("name=big+mac&patties=2&price=4.5&vegan=true&sizes=1&sizes=2"+
+		"&tags=a+b&codes=x|y&filter[min]=1&filter[max]=9&pair=k,v&street=main&zip=1234"+
+		"&meta=%7B%22a%22%3A1%7D&extra=1&extra=2"), mt.Schema.Schema(), mt.Encoding)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]any{
+		"name":    "big mac",
+		"patties": int64(2),
+		"price":   4.5,
+		"vegan":   true,
+		"sizes":   []any{int64(1), int64(2)},
+		"tags":    []any{"a", "b"},
+		"codes":   []any{"x", "y"}, // explode only defaults to true for the form style
+		"filter":  map[string]any{"min": int64(1), "max": int64(9)},
+		"pair":    map[string]any{"k": "v"},
+		"address": map[string]any{"street": "main", "zip": int64(1234)},
+		"meta":    map[string]any{"a": float64(1)},
+		"extra":   []any{"1", "2"},
+	}, decoded)
+}
+
+func TestDecodeFormURLEncoded_Coercion(t *testing.T) {
+	mt := buildFormMediaType(t)
+
+	// values that cannot be coerced are left as strings, and absent properties are left out.
+	decoded, err := DecodeFormURLEncoded([]byte("patties=two&vegan=yes&meta=%7Bnope"), mt.Schema.Schema(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"patties": "two", "vegan": "yes", "meta": "{nope"}, decoded)
+
+	_, err = DecodeFormURLEncoded([]byte("name=%zz"), mt.Schema.Schema(), nil)
+	assert.Error(t, err)
+
+	decoded, err = DecodeFormURLEncoded([]byte("a=1"), nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": "1"}, decoded)
+}
+
+func TestIsFormURLEncoded(t *testing.T) {
+	assert.True(t, IsFormURLEncoded("application/x-www-form-urlencoded"))
+	assert.True(t, IsFormURLEncoded("Application/X-WWW-Form-Urlencoded; charset=utf-8"))
+	assert.False(t, IsFormURLEncoded("application/json"))
+}
+
+func TestCastValueForSchema(t *testing.T) {
+	assert.Equal(t, "1", CastValueForSchema("1", nil))
+
+	boolean := &base.Schema{Type: []string{Boolean}}
+	assert.Equal(t, true, CastValueForSchema("true", boolean))
+	assert.Equal(t, false, CastValueForSchema("false", boolean))
+	for _, value := range []string{"1", "0", "t", "F", "TRUE"} {
+		assert.Equal(t, value, CastValueForSchema(value, boolean), value)
+	}
+
+	number := &base.Schema{Type: []string{Number}}
+	assert.Equal(t, 4.5, CastValueForSchema("4.5", number))
+	for _, value := range []string{"NaN", "Inf", "-Inf", "infinity", "1e999"} {
+		assert.Equal(t, value, CastValueForSchema(value, number), value)
+	}
+}
+
+func TestSchemaPrimaryType_PropertySchema(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Burger:
+      allOf:
+        - type: object
+          properties:
+            name:
+              type: string
+        - oneOf:
+            - properties:
+                patties:
+                  type: integer
+    Toppings:
+      anyOf:
+        - type: array
+    Fries:
+      type: string
+      allOf:
+        - type: object`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	schemas := m.Model.Components.Schemas
+
+	// types and properties are looked up through composition
+	burger := schemas.GetOrZero("Burger").Schema()
+	assert.Equal(t, Object, SchemaPrimaryType(burger))
+	assert.Equal(t, []string{String}, PropertySchema(burger, "name").Type)
+	assert.Equal(t, []string{Integer}, PropertySchema(burger, "patties").Type)
+	assert.Nil(t, PropertySchema(burger, "cheese"))
+	assert.Equal(t, []string{"name", "patties"}, propertyNames(burger))
+	assert.Equal(t, Array, SchemaPrimaryType(schemas.GetOrZero("Toppings").Schema()))
+
+	// form fields of composed properties are decoded with their own schemas
+	decoded, err := DecodeFormURLEncoded([]byte("name=big+mac&patties=2"), burger, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "big mac", "patties": int64(2)}, decoded)
+
+	// a type of its own wins
+	assert.Empty(t, SchemaPrimaryType(schemas.GetOrZero("Fries").Schema()))
+	assert.Empty(t, SchemaPrimaryType(nil))
+	assert.Nil(t, PropertySchema(nil, "name"))
+}
@@ -8,10 +8,12 @@ import (
 	"net/http"
 
 	"github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
 )
 
-// ExtractOperation extracts the operation from the path item based on the request method. If there is no
-// matching operation found, then nil is returned.
+// ExtractOperation extracts the operation from the path item based on the request method. The QUERY method and
+// the additionalOperations of OpenAPI 3.2 are included, additional operations are keyed by the method exactly as it
+// is sent. If there is no matching operation found, then nil is returned.
 func ExtractOperation(request *http.Request, item *v3.PathItem) *v3.Operation {
 	switch request.Method {
 	case http.MethodGet:
@@ -30,10 +32,60 @@ func ExtractOperation(request *http.Request, item *v3.PathItem) *v3.Operation {
 		return item.Patch
 	case http.MethodTrace:
 		return item.Trace
+	case MethodQuery:
+		return item.Query
+	}
+	if ops := AdditionalOperations(item); ops != nil {
+		return ops.GetOrZero(request.Method)
 	}
 	return nil
 }
 
+// ExtractOperations returns every operation of a path item keyed by method, including the QUERY operation and
+// the additionalOperations of OpenAPI 3.2 (see AdditionalOperations).
+func ExtractOperations(item *v3.PathItem) *orderedmap.Map[string, *v3.Operation] {
+	ops := item.GetOperations()
+	for method, op := range AdditionalOperations(item).FromOldest() {
+		ops.Set(method, op)
+	}
+	return ops
+}
+
+// AdditionalOperations returns the additionalOperations of an OpenAPI 3.2 path item, keyed by method. When the
+// high-level model does not hold them (the low-level model may parse them without building them), they are built
+// from the low-level model on every call, use BuildAdditionalOperations to build them once for a document.
+func AdditionalOperations(item *v3.PathItem) *orderedmap.Map[string, *v3.Operation] {
+	if item == nil {
+		return nil
+	}
+	if item.AdditionalOperations != nil {
+		return item.AdditionalOperations
+	}
+	low := item.GoLow()
+	if low == nil || low.AdditionalOperations.Value == nil || low.AdditionalOperations.Value.Len() == 0 {
+		return nil
+	}
+	ops := orderedmap.New[string, *v3.Operation]()
+	for k, op := range low.AdditionalOperations.Value.FromOldest() {
+		ops.Set(k.Value, v3.NewOperation(op.Value))
+	}
+	return ops
+}
+
+// BuildAdditionalOperations builds the additionalOperations of every path item of a document that the high-level
+// model does not hold, and sets them on the path item, so the same operations are returned from then on. It must be
+// called before the document is used concurrently.
+func BuildAdditionalOperations(document *v3.Document) {
+	if document == nil || document.Paths == nil {
+		return
+	}
+	for item := range document.Paths.PathItems.ValuesFromOldest() {
+		if item != nil && item.AdditionalOperations == nil {
+			item.AdditionalOperations = AdditionalOperations(item)
+		}
+	}
+}
+
 // ExtractContentType extracts the content type from the request header. First return argument is the content type
 // of the request.The second (optional) argument is the charset of the request. The third (optional)
 // argument is the boundary of the type (only used with forms really).
@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/pb33f/libopenapi"
 	"github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/stretchr/testify/require"
 )
 
@@ -53,6 +55,63 @@ func TestExtractOperation(t *testing.T) {
 	require.Nil(t, operation)
 }
 
+// Test ExtractOperation for the QUERY method and additional operations of OpenAPI 3.2
+func TestExtractOperation_OpenAPI32(t *testing.T) {
+	additional := orderedmap.New[string, *v3.Operation]()
+	additional.Set("LINK", &v3.Operation{Summary: "LINK operation"})
+	pathItem := &v3.PathItem{
+		Query:                &v3.Operation{Summary: "QUERY operation"},
+		AdditionalOperations: additional,
+	}
+
+	req, _ := http.NewRequest("QUERY", "/", nil)
+	require.Equal(t, "QUERY operation", ExtractOperation(req, pathItem).Summary)
+
+	req, _ = http.NewRequest("LINK", "/", nil)
+	require.Equal(t, "LINK operation", ExtractOperation(req, pathItem).Summary)
+
+	// additional operations are keyed by the method exactly as it is sent
+	req, _ = http.NewRequest("Link", "/", nil)
+	require.Nil(t, ExtractOperation(req, pathItem))
+	req, _ = http.NewRequest(http.MethodGet, "/", nil)
+	require.Nil(t, ExtractOperation(req, pathItem))
+}
+
+func TestExtractOperations_AdditionalOperations(t *testing.T) {
+	spec := `openapi: 3.2.0
+paths:
+  /burgers:
+    get:
+      summary: GET operation
+    additionalOperations:
+      LINK:
+        summary: LINK operation`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	pathItem := m.Model.Paths.PathItems.GetOrZero("/burgers")
+
+	// built from the low-level model on every call
+	link := AdditionalOperations(pathItem).GetOrZero("LINK")
+	require.NotNil(t, link)
+	require.Equal(t, "LINK operation", link.Summary)
+	require.NotSame(t, link, AdditionalOperations(pathItem).GetOrZero("LINK"))
+	require.Equal(t, 2, ExtractOperations(pathItem).Len())
+
+	// built once for the document, the same operation is returned every time
+	BuildAdditionalOperations(&m.Model)
+	BuildAdditionalOperations(nil)
+	link = AdditionalOperations(pathItem).GetOrZero("LINK")
+	require.Same(t, link, pathItem.AdditionalOperations.GetOrZero("LINK"))
+	req, _ := http.NewRequest("LINK", "/burgers", nil)
+	require.Same(t, link, ExtractOperation(req, pathItem))
+
+	ops := ExtractOperations(pathItem)
+	require.Equal(t, 2, ops.Len())
+	require.Same(t, link, ops.GetOrZero("LINK"))
+	require.Nil(t, AdditionalOperations(&v3.PathItem{}))
+}
+
 // Test ExtractContentType for various input cases
 func TestExtractContentType(t *testing.T) {
 	// Simple content type with no charset or boundary
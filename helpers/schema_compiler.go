@@ -55,6 +55,19 @@ func NewCompiledSchema(name string, jsonSchema []byte, o *config.ValidationOptio
 // - version 3.0: Allows OpenAPI 3.0 keywords like 'nullable'
 // - version 3.1+: Rejects OpenAPI 3.0 keywords like 'nullable' (strict JSON Schema compliance)
 func NewCompiledSchemaWithVersion(name string, jsonSchema []byte, o *config.ValidationOptions, version float32) (*jsonschema.Schema, error) {
+	return NewCompiledSchemaForDirection(name, jsonSchema, o, version, AnyDirection)
+}
+
+// NewCompiledSchemaForDirection compiles the schema of a body sent in a direction, as NewCompiledSchemaWithVersion
+// does. When strict additionalProperties applies to the direction (see StrictPropertiesApply), object schemas that
+// leave additionalProperties unset are closed.
+func NewCompiledSchemaForDirection(name string, jsonSchema []byte, o *config.ValidationOptions, version float32,
+	direction Direction,
+) (*jsonschema.Schema, error) {
+	if StrictPropertiesApply(o, direction) {
+		jsonSchema = transformSchemaForStrictProperties(jsonSchema)
+	}
+
 	compiler := NewCompilerWithOptions(o)
 	compiler.UseLoader(NewCompilerLoader())
 
@@ -78,6 +91,9 @@ func NewCompiledSchemaWithVersion(name string, jsonSchema []byte, o *config.Vali
 		if o.AllowScalarCoercion {
 			jsonSchema = transformSchemaForCoercion(jsonSchema)
 		}
+
+		// discriminators with annotated schemas select the one schema a payload is validated against.
+		jsonSchema = transformSchemaForDiscriminators(jsonSchema)
 	}
 
 	decodedSchema, err := jsonschema.UnmarshalJSON(bytes.NewReader(jsonSchema))
@@ -97,6 +113,118 @@ func NewCompiledSchemaWithVersion(name string, jsonSchema []byte, o *config.Vali
 	return jsch, nil
 }
 
+// Direction is the direction of the messages a schema validates. readOnly properties are only sent in responses,
+// writeOnly properties only in requests.
+type Direction int
+
+const (
+	AnyDirection Direction = iota
+	RequestDirection
+	ResponseDirection
+)
+
+// TransformSchemaForDirection removes the properties that cannot be sent in a direction (readOnly properties for
+// requests, writeOnly properties for responses) from the required properties of every object in the schema, as
+// OpenAPI specifies. The properties of allOf schemas count as properties of the object they are composed into.
+// The schema is returned as-is, and false, when no required property is affected.
+func TransformSchemaForDirection(jsonSchema []byte, direction Direction) ([]byte, bool) {
+	keyword := ""
+	switch direction {
+	case RequestDirection:
+		keyword = "readOnly"
+	case ResponseDirection:
+		keyword = "writeOnly"
+	default:
+		return jsonSchema, false
+	}
+
+	var schema interface{}
+	if err := json.Unmarshal(jsonSchema, &schema); err != nil {
+		return jsonSchema, false
+	}
+	if !relaxRequiredInSchema(schema, keyword, nil) {
+		return jsonSchema, false
+	}
+	result, err := json.Marshal(schema)
+	if err != nil {
+		return jsonSchema, false
+	}
+	return result, true
+}
+
+// relaxRequiredInSchema removes the properties flagged with the keyword from the required properties of every
+// schema, in place. Flagged properties of the allOf siblings of a schema are inherited. It returns true if any
+// required property was removed.
+func relaxRequiredInSchema(schema interface{}, keyword string, inherited map[string]bool) bool {
+	changed := false
+	switch s := schema.(type) {
+	case map[string]interface{}:
+		flagged := flaggedProperties(s, keyword)
+		for name := range inherited {
+			flagged[name] = true
+		}
+
+		if required, ok := s["required"].([]interface{}); ok && len(flagged) > 0 {
+			kept := make([]interface{}, 0, len(required))
+			for _, name := range required {
+				if n, ok := name.(string); ok && flagged[n] {
+					continue
+				}
+				kept = append(kept, name)
+			}
+			if len(kept) != len(required) {
+				s["required"] = kept
+				changed = true
+			}
+		}
+
+		for key, value := range s {
+			switch key {
+			case "enum", "const", "default", "example", "examples":
+				continue // values, not schemas
+			}
+			var inherit map[string]bool
+			if key == "allOf" {
+				inherit = flagged
+			}
+			if relaxRequiredInSchema(value, keyword, inherit) {
+				changed = true
+			}
+		}
+
+	case []interface{}:
+		for _, item := range s {
+			if relaxRequiredInSchema(item, keyword, inherited) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// flaggedProperties returns the names of the properties of a schema, and of its allOf schemas, that are flagged
+// with the keyword.
+func flaggedProperties(schema map[string]interface{}, keyword string) map[string]bool {
+	flagged := make(map[string]bool)
+	if properties, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, prop := range properties {
+			if p, ok := prop.(map[string]interface{}); ok && p[keyword] == true {
+				flagged[name] = true
+			}
+		}
+	}
+	if allOf, ok := schema["allOf"].([]interface{}); ok {
+		for _, sub := range allOf {
+			if s, ok := sub.(map[string]interface{}); ok {
+				for name := range flaggedProperties(s, keyword) {
+					flagged[name] = true
+				}
+			}
+		}
+	}
+	return flagged
+}
+
 // transformOpenAPI30Schema transforms OpenAPI 3.0 schemas to JSON Schema compatible format
 // This specifically handles the nullable keyword by converting it to proper type arrays
 func transformOpenAPI30Schema(jsonSchema []byte) []byte {
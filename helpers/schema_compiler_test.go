@@ -546,3 +546,50 @@ func TestTransformTypeForCoercion_EdgeCases(t *testing.T) {
 	result = transformTypeForCoercion([]interface{}{"string"})
 	assert.Equal(t, []interface{}{"string"}, result)
 }
+
+func TestTransformSchemaForDirection(t *testing.T) {
+	schema := `{
+  "type": "object",
+  "required": ["id", "name", "password"],
+  "properties": {
+    "id": {"type": "integer", "readOnly": true},
+    "name": {"type": "string"},
+    "password": {"type": "string", "writeOnly": true},
+    "owner": {
+      "allOf": [
+        {"properties": {"id": {"type": "integer", "readOnly": true}}},
+        {"required": ["id", "email"], "properties": {"email": {"type": "string"}}}
+      ]
+    }
+  },
+  "enum": [{"required": ["id"]}]
+}`
+
+	transformed, ok := TransformSchemaForDirection([]byte(schema), RequestDirection)
+	require.True(t, ok)
+	var request map[string]any
+	require.NoError(t, json.Unmarshal(transformed, &request))
+	assert.Equal(t, []any{"name", "password"}, request["required"])
+	owner := request["properties"].(map[string]any)["owner"].(map[string]any)
+	assert.Equal(t, []any{"email"}, owner["allOf"].([]any)[1].(map[string]any)["required"])
+	assert.Equal(t, []any{map[string]any{"required": []any{"id"}}}, request["enum"], "values are left alone")
+
+	transformed, ok = TransformSchemaForDirection([]byte(schema), ResponseDirection)
+	require.True(t, ok)
+	var response map[string]any
+	require.NoError(t, json.Unmarshal(transformed, &response))
+	assert.Equal(t, []any{"id", "name"}, response["required"])
+
+	// nothing to relax, nothing changes
+	unchanged := []byte(`{"type":"object","required":["name"],"properties":{"name":{"type":"string"}}}`)
+	transformed, ok = TransformSchemaForDirection(unchanged, RequestDirection)
+	assert.False(t, ok)
+	assert.Equal(t, unchanged, transformed)
+
+	transformed, ok = TransformSchemaForDirection([]byte(schema), AnyDirection)
+	assert.False(t, ok)
+	assert.Equal(t, schema, string(transformed))
+
+	_, ok = TransformSchemaForDirection([]byte(`{nope`), RequestDirection)
+	assert.False(t, ok)
+}
@@ -0,0 +1,99 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// sequentialMediaTypes are the media types of streams of items, that the itemSchema of an OpenAPI 3.2 media type
+// describes one item of.
+var sequentialMediaTypes = map[string]bool{
+	"application/jsonl":        true,
+	"application/x-jsonlines":  true,
+	"application/x-ndjson":     true,
+	"application/json-seq":     true,
+	EventStreamContentType:     true,
+	"application/geo+json-seq": true,
+}
+
+// IsSequentialMediaType returns true if the content type is a stream of items: JSON Lines, NDJSON, JSON text
+// sequences or server-sent events.
+func IsSequentialMediaType(contentType string) bool {
+	ct, _, _ := ExtractContentType(contentType)
+	return sequentialMediaTypes[strings.ToLower(ct)]
+}
+
+// SplitSequentialItems splits a stream into its items, each item is returned as a JSON document. JSON Lines and
+// NDJSON items are lines, JSON text sequence items are separated by record separators, and server-sent events are
+// converted into objects with the fields of the event (data, event, id, and retry as an integer). Items are not
+// checked, an item that is not valid JSON is returned as-is. Empty lines and records are skipped.
+func SplitSequentialItems(contentType string, body []byte) [][]byte {
+	ct, _, _ := ExtractContentType(contentType)
+	switch strings.ToLower(ct) {
+	case EventStreamContentType:
+		return splitEvents(body)
+	case "application/json-seq", "application/geo+json-seq":
+		return splitRecords(body, "\x1e")
+	}
+	return splitRecords(body, "\n")
+}
+
+func splitRecords(body []byte, separator string) [][]byte {
+	var items [][]byte
+	for _, record := range bytes.Split(body, []byte(separator)) {
+		record = bytes.TrimSpace(record)
+		if len(record) > 0 {
+			items = append(items, record)
+		}
+	}
+	return items
+}
+
+// splitEvents parses server-sent events (see the HTML living standard, section 9.2.6). Comments and unknown fields
+// are ignored, the data lines of an event are joined with line feeds.
+func splitEvents(body []byte) [][]byte {
+	var items [][]byte
+	event := make(map[string]any)
+	var data []string
+	flush := func() {
+		if data != nil {
+			event["data"] = strings.Join(data, "\n")
+		}
+		if len(event) > 0 {
+			item, _ := json.Marshal(event)
+			items = append(items, item)
+		}
+		event = make(map[string]any)
+		data = nil
+	}
+
+	normalized := strings.ReplaceAll(strings.ReplaceAll(string(body), "\r\n", "\n"), "\r", "\n")
+	for _, line := range strings.Split(normalized, "\n") {
+		if line == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+		case "event", "id":
+			event[field] = value
+		case "retry":
+			if retry, err := strconv.ParseInt(value, 10, 64); err == nil {
+				event[field] = retry
+			}
+		}
+	}
+	flush()
+	return items
+}
@@ -0,0 +1,40 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSequentialMediaType(t *testing.T) {
+	assert.True(t, IsSequentialMediaType("application/jsonl"))
+	assert.True(t, IsSequentialMediaType("application/x-ndjson; charset=utf-8"))
+	assert.True(t, IsSequentialMediaType("application/json-seq"))
+	assert.True(t, IsSequentialMediaType("Text/Event-Stream"))
+	assert.False(t, IsSequentialMediaType("application/json"))
+	assert.False(t, IsSequentialMediaType(""))
+}
+
+func TestSplitSequentialItems(t *testing.T) {
+	items := SplitSequentialItems("application/jsonl", []byte("{\"a\": 1}\n\n{\"a\": 2}\r\nnot json\n"))
+	assert.Equal(t, [][]byte{[]byte(`{"a": 1}`), []byte(`{"a": 2}`), []byte(`not json`)}, items)
+
+	items = SplitSequentialItems("application/json-seq", []byte("\x1e{\"a\": 1}\n\x1e[1, 2]\n"))
+	assert.Equal(t, [][]byte{[]byte(`{"a": 1}`), []byte(`[1, 2]`)}, items)
+
+	events := ": keep-alive\n\n" +
+		"event: order\nid: 1\ndata: {\"burger\":\ndata: \"big mac\"}\n\n" +
+		"retry: 1000\r\ndata:plain\r\n\r\n" +
+		"retry: soon\ndata: last"
+	items = SplitSequentialItems(EventStreamContentType, []byte(events))
+	assert.Equal(t, [][]byte{
+		[]byte(`{"data":"{\"burger\":\n\"big mac\"}","event":"order","id":"1"}`),
+		[]byte(`{"data":"plain","retry":1000}`),
+		[]byte(`{"data":"last"}`),
+	}, items)
+
+	assert.Empty(t, SplitSequentialItems("application/x-ndjson", nil))
+}
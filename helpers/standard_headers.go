@@ -0,0 +1,61 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import "net/http"
+
+// standardRequestHeaders are the request headers defined by the HTTP specifications (and the fetch standard), which
+// are part of the protocol rather than the API, and never need to be declared as parameters.
+var standardRequestHeaders = map[string]bool{
+	"Accept":                         true,
+	"Accept-Charset":                 true,
+	"Accept-Encoding":                true,
+	"Accept-Language":                true,
+	"Access-Control-Request-Headers": true,
+	"Access-Control-Request-Method":  true,
+	"Authorization":                  true,
+	"Cache-Control":                  true,
+	"Connection":                     true,
+	"Content-Encoding":               true,
+	"Content-Language":               true,
+	"Content-Length":                 true,
+	"Content-Type":                   true,
+	"Cookie":                         true,
+	"Date":                           true,
+	"Dnt":                            true,
+	"Expect":                         true,
+	"Forwarded":                      true,
+	"From":                           true,
+	"Host":                           true,
+	"If-Match":                       true,
+	"If-Modified-Since":              true,
+	"If-None-Match":                  true,
+	"If-Range":                       true,
+	"If-Unmodified-Since":            true,
+	"Keep-Alive":                     true,
+	"Max-Forwards":                   true,
+	"Origin":                         true,
+	"Pragma":                         true,
+	"Priority":                       true,
+	"Proxy-Authorization":            true,
+	"Range":                          true,
+	"Referer":                        true,
+	"Sec-Fetch-Dest":                 true,
+	"Sec-Fetch-Mode":                 true,
+	"Sec-Fetch-Site":                 true,
+	"Sec-Fetch-User":                 true,
+	"Te":                             true,
+	"Trailer":                        true,
+	"Transfer-Encoding":              true,
+	"Upgrade":                        true,
+	"Upgrade-Insecure-Requests":      true,
+	"User-Agent":                     true,
+	"Via":                            true,
+	"Warning":                        true,
+}
+
+// IsStandardHeader returns true if the header is a standard HTTP request header, the name is case-insensitive.
+func IsStandardHeader(name string) bool {
+	return standardRequestHeaders[http.CanonicalHeaderKey(name)]
+}
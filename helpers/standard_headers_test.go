@@ -0,0 +1,18 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStandardHeader(t *testing.T) {
+	assert.True(t, IsStandardHeader("Content-Type"))
+	assert.True(t, IsStandardHeader("accept-encoding"))
+	assert.True(t, IsStandardHeader("TE"))
+	assert.False(t, IsStandardHeader("X-Request-Id"))
+	assert.False(t, IsStandardHeader("X-API-Key"))
+}
@@ -0,0 +1,124 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"encoding/json"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+// StrictPropertiesExtension opts a schema out of strict additionalProperties, when set to false.
+const StrictPropertiesExtension = "x-strict-properties"
+
+// StrictPropertiesApply returns true if strict additionalProperties applies to the bodies sent in a direction.
+// Schemas that are not compiled for a direction are never closed.
+func StrictPropertiesApply(o *config.ValidationOptions, direction Direction) bool {
+	if o == nil || direction == AnyDirection {
+		return false
+	}
+	switch o.StrictAdditionalProperties {
+	case config.StrictPropertiesAll:
+		return true
+	case config.StrictPropertiesRequests:
+		return direction == RequestDirection
+	case config.StrictPropertiesResponses:
+		return direction == ResponseDirection
+	}
+	return false
+}
+
+// transformSchemaForStrictProperties closes object schemas that do not set additionalProperties, so undeclared
+// properties are rejected. Schemas using allOf, anyOf or oneOf are closed with unevaluatedProperties instead.
+func transformSchemaForStrictProperties(jsonSchema []byte) []byte {
+	var schema interface{}
+	if err := json.Unmarshal(jsonSchema, &schema); err != nil {
+		return jsonSchema
+	}
+	closeObjectSchemas(schema, false)
+	result, err := json.Marshal(schema)
+	if err != nil {
+		return jsonSchema
+	}
+	return result
+}
+
+// closeObjectSchemas closes the schema, unless it is composed into another one, and every schema it contains.
+func closeObjectSchemas(schema interface{}, composed bool) {
+	s, ok := schema.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	_, hasAdditional := s["additionalProperties"]
+	_, hasUnevaluated := s["unevaluatedProperties"]
+	if !composed && !hasAdditional && !hasUnevaluated && s[StrictPropertiesExtension] != false && definesProperties(s) {
+		if hasComposition(s) {
+			s["unevaluatedProperties"] = false
+		} else {
+			s["additionalProperties"] = false
+		}
+	}
+
+	for key, value := range s {
+		switch key {
+		case "allOf", "anyOf", "oneOf":
+			if members, ok := value.([]interface{}); ok {
+				for _, member := range members {
+					closeObjectSchemas(member, true)
+				}
+			}
+		case "properties", "patternProperties", "$defs", "definitions", "dependentSchemas":
+			if schemas, ok := value.(map[string]interface{}); ok {
+				for _, sub := range schemas {
+					closeObjectSchemas(sub, false)
+				}
+			}
+		case "prefixItems":
+			if items, ok := value.([]interface{}); ok {
+				for _, item := range items {
+					closeObjectSchemas(item, false)
+				}
+			}
+		case "items", "additionalProperties", "not", "if", "then", "else", "contains":
+			if sub, ok := value.([]interface{}); ok { // draft 4 style tuples
+				for _, item := range sub {
+					closeObjectSchemas(item, false)
+				}
+				continue
+			}
+			closeObjectSchemas(value, false)
+		}
+	}
+}
+
+// definesProperties returns true if a schema defines the properties of objects, itself or through the schemas it is
+// composed of. Free-form objects, that define no properties, are not closed.
+func definesProperties(s map[string]interface{}) bool {
+	if _, ok := s["properties"]; ok {
+		return true
+	}
+	if _, ok := s["patternProperties"]; ok {
+		return true
+	}
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if members, ok := s[key].([]interface{}); ok {
+			for _, member := range members {
+				if m, ok := member.(map[string]interface{}); ok && definesProperties(m) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func hasComposition(s map[string]interface{}) bool {
+	for _, key := range []string{"allOf", "anyOf", "oneOf"} {
+		if members, ok := s[key].([]interface{}); ok && len(members) > 0 {
+			return true
+		}
+	}
+	return false
+}
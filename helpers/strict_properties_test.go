@@ -0,0 +1,85 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package helpers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func TestStrictPropertiesApply(t *testing.T) {
+	assert.False(t, StrictPropertiesApply(nil, RequestDirection))
+	assert.False(t, StrictPropertiesApply(config.NewValidationOptions(), RequestDirection))
+
+	all := config.NewValidationOptions(config.WithStrictAdditionalProperties(config.StrictPropertiesAll))
+	assert.True(t, StrictPropertiesApply(all, RequestDirection))
+	assert.True(t, StrictPropertiesApply(all, ResponseDirection))
+	assert.False(t, StrictPropertiesApply(all, AnyDirection))
+
+	requests := config.NewValidationOptions(config.WithStrictAdditionalProperties(config.StrictPropertiesRequests))
+	assert.True(t, StrictPropertiesApply(requests, RequestDirection))
+	assert.False(t, StrictPropertiesApply(requests, ResponseDirection))
+
+	responses := config.NewValidationOptions(config.WithStrictAdditionalProperties(config.StrictPropertiesResponses))
+	assert.False(t, StrictPropertiesApply(responses, RequestDirection))
+	assert.True(t, StrictPropertiesApply(responses, ResponseDirection))
+}
+
+func TestNewCompiledSchemaForDirection_StrictProperties(t *testing.T) {
+	schema := []byte(`{
+  "type": "object",
+  "properties": {
+    "name": {"type": "string"},
+    "address": {"type": "object", "properties": {"street": {"type": "string"}}},
+    "tags": {"type": "array", "items": {"type": "object", "properties": {"label": {"type": "string"}}}},
+    "metadata": {"type": "object"},
+    "labels": {"type": "object", "properties": {"a": {"type": "string"}}, "additionalProperties": {"type": "string"}},
+    "legacy": {"type": "object", "properties": {"id": {"type": "string"}}, "x-strict-properties": false},
+    "pet": {
+      "allOf": [
+        {"type": "object", "properties": {"id": {"type": "integer"}}},
+        {"type": "object", "properties": {"kind": {"type": "string"}}}
+      ]
+    }
+  }
+}`)
+	opts := config.NewValidationOptions(config.WithStrictAdditionalProperties(config.StrictPropertiesRequests))
+
+	// not compiled for a direction the option applies to, so nothing is closed.
+	open, err := NewCompiledSchemaForDirection("open", schema, opts, 3.1, ResponseDirection)
+	require.NoError(t, err)
+	assert.NoError(t, open.Validate(map[string]any{"name": "dave", "extra": true}))
+
+	strict, err := NewCompiledSchemaForDirection("strict", schema, opts, 3.1, RequestDirection)
+	require.NoError(t, err)
+
+	valid := map[string]any{
+		"name":     "dave",
+		"address":  map[string]any{"street": "main"},
+		"tags":     []any{map[string]any{"label": "x"}},
+		"metadata": map[string]any{"anything": "goes"},              // free-form objects stay open
+		"labels":   map[string]any{"a": "1", "b": "2"},              // additionalProperties is explicit
+		"legacy":   map[string]any{"id": "1", "extra": true},        // opted out
+		"pet":      map[string]any{"id": float64(1), "kind": "cat"}, // composed properties are evaluated
+	}
+	assert.NoError(t, strict.Validate(valid))
+
+	for _, invalid := range []map[string]any{
+		{"name": "dave", "extra": true},
+		{"address": map[string]any{"street": "main", "city": "x"}},
+		{"tags": []any{map[string]any{"label": "x", "colour": "red"}}},
+		{"pet": map[string]any{"id": float64(1), "kind": "cat", "owner": "dave"}},
+	} {
+		assert.Error(t, strict.Validate(invalid), "%v", invalid)
+	}
+
+	// schemas compiled without a direction are never closed.
+	neutral, err := NewCompiledSchemaWithVersion("neutral", schema, opts, 3.1)
+	require.NoError(t, err)
+	assert.NoError(t, neutral.Validate(map[string]any{"name": "dave", "extra": true}))
+}
@@ -4,11 +4,13 @@ import (
 	"strings"
 )
 
-// VersionToFloat converts a version string to a float32 for easier comparison.
+// VersionToFloat converts a version string to a float32 for easier comparison. Unknown versions are treated as 3.1.
 func VersionToFloat(version string) float32 {
 	switch {
 	case strings.HasPrefix(version, "3.0"):
 		return 3.0
+	case strings.HasPrefix(version, "3.2"):
+		return 3.2
 	default:
 		return 3.1
 	}
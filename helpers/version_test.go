@@ -42,6 +42,11 @@ func TestVersionToFloat(t *testing.T) {
 			version:  "3.1.1",
 			expected: 3.1,
 		},
+		{
+			name:     "OpenAPI 3.2.0",
+			version:  "3.2.0",
+			expected: 3.2,
+		},
 		{
 			name:     "default to 3.1 for unknown version",
 			version:  "4.0",
@@ -0,0 +1,177 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package jwt
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+// JWK is a key of a JSON Web Key Set (RFC 7517). Key is the decoded key: an *rsa.PublicKey, an *ecdsa.PublicKey,
+// an ed25519.PublicKey or an HMAC secret ([]byte).
+type JWK struct {
+	Kid string
+	Kty string
+	Alg string
+	Use string
+	Key any
+}
+
+// JWKS is a JSON Web Key Set, it provides the keys that verify the signatures of tokens (see
+// config.WithJWTKeyProvider).
+type JWKS struct {
+	Keys []*JWK
+}
+
+type rawJWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+	K   string `json:"k"`
+}
+
+// LoadJWKS reads a JSON Web Key Set from a file.
+func LoadJWKS(path string) (*JWKS, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the key set: %w", err)
+	}
+	return ParseJWKS(data)
+}
+
+// ParseJWKS decodes a JSON Web Key Set. RSA, EC (P-256, P-384 and P-521), OKP (Ed25519) and oct (HMAC) keys are
+// supported, keys of other types, and keys that are only used for encryption, are skipped.
+func ParseJWKS(data []byte) (*JWKS, error) {
+	var set struct {
+		Keys []rawJWK `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("the key set is not valid JSON: %w", err)
+	}
+	jwks := &JWKS{}
+	for i, raw := range set.Keys {
+		if raw.Use == "enc" {
+			continue
+		}
+		key, err := raw.decode()
+		if err != nil {
+			return nil, fmt.Errorf("key %d ('%s') of the key set is not valid: %w", i, raw.Kid, err)
+		}
+		if key == nil {
+			continue
+		}
+		jwks.Keys = append(jwks.Keys, &JWK{Kid: raw.Kid, Kty: raw.Kty, Alg: raw.Alg, Use: raw.Use, Key: key})
+	}
+	return jwks, nil
+}
+
+// Match returns the keys that may have signed a token with a key ID and algorithm: keys with the same ID (or every
+// key, when the token has no key ID), that are not bound to another algorithm.
+func (s *JWKS) Match(kid, alg string) []any {
+	var keys []any
+	for _, k := range s.Keys {
+		if (kid != "" && k.Kid != kid) || (k.Alg != "" && k.Alg != alg) {
+			continue
+		}
+		keys = append(keys, k.Key)
+	}
+	return keys
+}
+
+// KeysFor implements config.JWTKeyProvider, it returns the keys that match the token (see Match).
+func (s *JWKS) KeysFor(_ *http.Request, _ string, _ *v3.SecurityScheme, kid, alg string) ([]any, error) {
+	return s.Match(kid, alg), nil
+}
+
+func (k *rawJWK) decode() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N, "n")
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E, "e")
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 2 || e.Int64() > 1<<31-1 {
+			return nil, fmt.Errorf("the exponent 'e' is out of range")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, fmt.Errorf("the curve '%s' is not supported", k.Crv)
+		}
+		x, err := decodeBigInt(k.X, "x")
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y, "y")
+		if err != nil {
+			return nil, err
+		}
+		size := (curve.Params().BitSize + 7) / 8
+		point := make([]byte, 1+2*size)
+		point[0] = 4 // uncompressed
+		if len(x.Bytes()) > size || len(y.Bytes()) > size {
+			return nil, fmt.Errorf("the point is not on the curve '%s'", k.Crv)
+		}
+		x.FillBytes(point[1 : 1+size])
+		y.FillBytes(point[1+size:])
+		if _, err = ecdhCurve.NewPublicKey(point); err != nil {
+			return nil, fmt.Errorf("the point is not on the curve '%s'", k.Crv)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("the curve '%s' is not supported", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("'x' is not an Ed25519 public key")
+		}
+		return ed25519.PublicKey(x), nil
+	case "oct":
+		secret, err := base64.RawURLEncoding.DecodeString(k.K)
+		if err != nil || len(secret) == 0 {
+			return nil, fmt.Errorf("'k' is not a base64url encoded secret")
+		}
+		return secret, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(value, name string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("'%s' is not a base64url encoded integer", name)
+	}
+	return new(big.Int).SetBytes(data), nil
+}
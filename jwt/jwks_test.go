@@ -0,0 +1,82 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeInt(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
+
+func TestParseJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	set, _ := json.Marshal(map[string]any{"keys": []map[string]any{
+		{"kty": "RSA", "kid": "rsa", "alg": "RS256", "n": encodeInt(rsaKey.N), "e": encodeInt(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "kid": "ec", "crv": "P-384", "x": encodeInt(ecKey.X), "y": encodeInt(ecKey.Y)},
+		{"kty": "OKP", "kid": "ed", "crv": "Ed25519", "x": base64.RawURLEncoding.EncodeToString(edPublic)},
+		{"kty": "oct", "kid": "hmac", "k": base64.RawURLEncoding.EncodeToString([]byte("secret"))},
+		{"kty": "RSA", "kid": "encryption", "use": "enc", "n": "AQAB", "e": "AQAB"},
+		{"kty": "unknown", "kid": "skipped"},
+	}})
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, set, 0o600))
+
+	jwks, err := LoadJWKS(path)
+	require.NoError(t, err)
+	require.Len(t, jwks.Keys, 4)
+
+	// keys are matched by ID, and by algorithm when they are bound to one
+	assert.Len(t, jwks.Match("rsa", "RS256"), 1)
+	assert.Empty(t, jwks.Match("rsa", "PS256"))
+	assert.Len(t, jwks.Match("", "ES384"), 3)
+
+	for kid, key := range map[string]any{"rsa": rsaKey, "ec": ecKey, "ed": edKey, "hmac": []byte("secret")} {
+		alg := map[string]string{"rsa": "RS256", "ec": "ES384", "ed": "EdDSA", "hmac": "HS256"}[kid]
+		token, err := Parse(sign(t, map[string]any{"alg": alg, "kid": kid}, map[string]any{}, key))
+		require.NoError(t, err)
+		keys, err := jwks.KeysFor(nil, "Bearer", nil, token.Kid(), token.Alg())
+		require.NoError(t, err)
+		assert.NoError(t, token.VerifyAny(keys), kid)
+	}
+
+	_, err = LoadJWKS(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestParseJWKS_Invalid(t *testing.T) {
+	for name, set := range map[string]string{
+		"json":     `{"keys": [`,
+		"modulus":  `{"keys": [{"kty": "RSA", "n": "!!!", "e": "AQAB"}]}`,
+		"exponent": `{"keys": [{"kty": "RSA", "n": "AQAB", "e": "AQ"}]}`,
+		"curve":    `{"keys": [{"kty": "EC", "crv": "P-192", "x": "AQ", "y": "AQ"}]}`,
+		"point":    `{"keys": [{"kty": "EC", "crv": "P-256", "x": "AQ", "y": "AQ"}]}`,
+		"okp":      `{"keys": [{"kty": "OKP", "crv": "Ed25519", "x": "AQ"}]}`,
+		"x448":     `{"keys": [{"kty": "OKP", "crv": "X448", "x": "AQ"}]}`,
+		"secret":   `{"keys": [{"kty": "oct", "k": ""}]}`,
+	} {
+		_, err := ParseJWKS([]byte(set))
+		assert.Error(t, err, name)
+	}
+}
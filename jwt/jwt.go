@@ -0,0 +1,257 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+// Package jwt checks JSON Web Tokens (RFC 7519) sent as the bearer tokens of security schemes with a 'JWT' bearer
+// format: the structure of the token, its registered time and audience claims, and (optionally) its signature.
+// Only signed tokens (JWS compact serialization) are supported, unsecured tokens ('alg: none') are rejected.
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rsa"
+	_ "crypto/sha256" // register the hashes used by the signing algorithms.
+	_ "crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMalformed is returned when a token is not made of a valid header, claims and signature.
+	ErrMalformed = errors.New("malformed token")
+
+	// ErrExpired is returned when the 'exp' claim of a token is in the past.
+	ErrExpired = errors.New("token has expired")
+
+	// ErrNotYetValid is returned when the 'nbf' claim of a token is in the future.
+	ErrNotYetValid = errors.New("token is not valid yet")
+
+	// ErrAudience is returned when a token is not issued for any of the expected audiences.
+	ErrAudience = errors.New("token is not issued for this audience")
+
+	// ErrUnsupportedAlgorithm is returned when the signing algorithm of a token is not supported.
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+
+	// ErrSignature is returned when the signature of a token cannot be verified with any of the keys.
+	ErrSignature = errors.New("signature is not valid")
+)
+
+// Token is a parsed token, its header and claims are decoded JSON objects.
+type Token struct {
+	Raw       string
+	Header    map[string]any
+	Claims    map[string]any
+	Signature []byte
+	signed    string // the encoded header and claims, that the signature covers.
+}
+
+// Parse splits a token into its header, claims and signature, and decodes them. The header must name the signing
+// algorithm.
+func Parse(raw string) (*Token, error) {
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("%w: a token has 3 parts separated by periods, found %d", ErrMalformed, len(parts))
+	}
+	t := &Token{Raw: raw, signed: parts[0] + "." + parts[1]}
+	if err := decodeSegment(parts[0], "header", &t.Header); err != nil {
+		return nil, err
+	}
+	if err := decodeSegment(parts[1], "claims", &t.Claims); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: the signature is not base64url encoded", ErrMalformed)
+	}
+	t.Signature = signature
+
+	alg, ok := t.Header["alg"].(string)
+	if !ok || alg == "" {
+		return nil, fmt.Errorf("%w: the header has no 'alg'", ErrMalformed)
+	}
+	if strings.EqualFold(alg, "none") || len(signature) == 0 {
+		return nil, fmt.Errorf("%w: the token is not signed", ErrMalformed)
+	}
+	if kid, found := t.Header["kid"]; found {
+		if _, ok := kid.(string); !ok {
+			return nil, fmt.Errorf("%w: 'kid' is not a string", ErrMalformed)
+		}
+	}
+	return t, nil
+}
+
+// Alg returns the signing algorithm of the token.
+func (t *Token) Alg() string {
+	alg, _ := t.Header["alg"].(string)
+	return alg
+}
+
+// Kid returns the ID of the key that signed the token, if the header has one.
+func (t *Token) Kid() string {
+	kid, _ := t.Header["kid"].(string)
+	return kid
+}
+
+// ValidateClaims checks the registered claims of the token at a time: 'exp', 'nbf' and 'iat' must be numbers, the
+// token must not have expired and must be valid already, and 'aud' must be a string or an array of strings. When
+// audiences are given, the token must be issued for one of them.
+func (t *Token) ValidateClaims(now time.Time, audiences []string) error {
+	for _, claim := range []string{"exp", "nbf", "iat"} {
+		if value, found := t.Claims[claim]; found {
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("%w: '%s' is not a number", ErrMalformed, claim)
+			}
+		}
+	}
+	if exp, ok := t.Claims["exp"].(float64); ok && !now.Before(unixTime(exp)) {
+		return fmt.Errorf("%w at %s", ErrExpired, unixTime(exp).UTC().Format(time.RFC3339))
+	}
+	if nbf, ok := t.Claims["nbf"].(float64); ok && now.Before(unixTime(nbf)) {
+		return fmt.Errorf("%w, it is valid from %s", ErrNotYetValid, unixTime(nbf).UTC().Format(time.RFC3339))
+	}
+
+	var aud []string
+	switch value := t.Claims["aud"].(type) {
+	case nil:
+	case string:
+		aud = []string{value}
+	case []any:
+		for _, v := range value {
+			s, ok := v.(string)
+			if !ok {
+				return fmt.Errorf("%w: 'aud' is not an array of strings", ErrMalformed)
+			}
+			aud = append(aud, s)
+		}
+	default:
+		return fmt.Errorf("%w: 'aud' is not a string or an array of strings", ErrMalformed)
+	}
+	if len(audiences) > 0 && !slices.ContainsFunc(aud, func(a string) bool { return slices.Contains(audiences, a) }) {
+		return fmt.Errorf("%w, expected '%s'", ErrAudience, strings.Join(audiences, "' or '"))
+	}
+	return nil
+}
+
+// Verify checks the signature of the token with a key: an HMAC secret ([]byte) for the HS algorithms, an
+// *rsa.PublicKey for the RS and PS algorithms, an *ecdsa.PublicKey for the ES algorithms and an ed25519.PublicKey
+// for EdDSA.
+func (t *Token) Verify(key any) error {
+	alg := t.Alg()
+	hash, err := algorithmHash(alg)
+	if err != nil {
+		return err
+	}
+	var digest []byte
+	if hash != 0 {
+		h := hash.New()
+		h.Write([]byte(t.signed))
+		digest = h.Sum(nil)
+	}
+
+	switch alg[:2] {
+	case "HS":
+		secret, ok := key.([]byte)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		mac := hmac.New(hash.New, secret)
+		mac.Write([]byte(t.signed))
+		if !hmac.Equal(mac.Sum(nil), t.Signature) {
+			return ErrSignature
+		}
+	case "RS", "PS":
+		pub, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		if alg[:2] == "RS" {
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, t.Signature)
+		} else {
+			err = rsa.VerifyPSS(pub, hash, digest, t.Signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		}
+		if err != nil {
+			return ErrSignature
+		}
+	case "ES":
+		pub, ok := key.(*ecdsa.PublicKey)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		if len(t.Signature) != 2*size {
+			return ErrSignature
+		}
+		r := new(big.Int).SetBytes(t.Signature[:size])
+		s := new(big.Int).SetBytes(t.Signature[size:])
+		if !ecdsa.Verify(pub, digest, r, s) {
+			return ErrSignature
+		}
+	default: // EdDSA
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return keyTypeError(alg, key)
+		}
+		if !ed25519.Verify(pub, []byte(t.signed), t.Signature) {
+			return ErrSignature
+		}
+	}
+	return nil
+}
+
+// VerifyAny checks the signature of the token with each key, until one of them verifies it.
+func (t *Token) VerifyAny(keys []any) error {
+	if len(keys) == 0 {
+		return fmt.Errorf("%w, there is no key for '%s' (kid '%s')", ErrSignature, t.Alg(), t.Kid())
+	}
+	err := ErrSignature
+	for _, key := range keys {
+		if err = t.Verify(key); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// algorithmHash returns the hash of a signing algorithm, EdDSA signs the message itself.
+func algorithmHash(alg string) (crypto.Hash, error) {
+	switch alg {
+	case "HS256", "RS256", "PS256", "ES256":
+		return crypto.SHA256, nil
+	case "HS384", "RS384", "PS384", "ES384":
+		return crypto.SHA384, nil
+	case "HS512", "RS512", "PS512", "ES512":
+		return crypto.SHA512, nil
+	case "EdDSA":
+		return 0, nil
+	}
+	return 0, fmt.Errorf("%w '%s'", ErrUnsupportedAlgorithm, alg)
+}
+
+func keyTypeError(alg string, key any) error {
+	return fmt.Errorf("%w, a %T key cannot verify '%s'", ErrSignature, key, alg)
+}
+
+func decodeSegment(segment, name string, v *map[string]any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: the %s is not base64url encoded", ErrMalformed, name)
+	}
+	if err = json.Unmarshal(data, v); err != nil || *v == nil {
+		return fmt.Errorf("%w: the %s is not a JSON object", ErrMalformed, name)
+	}
+	return nil
+}
+
+func unixTime(seconds float64) time.Time {
+	whole := math.Floor(seconds)
+	return time.Unix(int64(whole), int64((seconds-whole)*float64(time.Second)))
+}
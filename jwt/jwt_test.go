@@ -0,0 +1,155 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sign creates a token with the header and claims, signed with the key for the algorithm of the header.
+func sign(t *testing.T, header, claims map[string]any, key any) string {
+	h, _ := json.Marshal(header)
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	alg := header["alg"].(string)
+	hash, err := algorithmHash(alg)
+	require.NoError(t, err)
+	var digest []byte
+	if hash != 0 {
+		d := hash.New()
+		d.Write([]byte(signed))
+		digest = d.Sum(nil)
+	}
+
+	var signature []byte
+	switch k := key.(type) {
+	case []byte:
+		mac := hmac.New(hash.New, k)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		if alg[:2] == "PS" {
+			signature, err = rsa.SignPSS(rand.Reader, k, hash, digest, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+		} else {
+			signature, err = rsa.SignPKCS1v15(rand.Reader, k, hash, digest)
+		}
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest)
+		require.NoError(t, err)
+		size := (k.Curve.Params().BitSize + 7) / 8
+		signature = make([]byte, 2*size)
+		r.FillBytes(signature[:size])
+		s.FillBytes(signature[size:])
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(k, []byte(signed))
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestParse(t *testing.T) {
+	raw := sign(t, map[string]any{"alg": "HS256", "kid": "burger"}, map[string]any{"sub": "pb33f"}, []byte("secret"))
+	token, err := Parse(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "HS256", token.Alg())
+	assert.Equal(t, "burger", token.Kid())
+	assert.Equal(t, "pb33f", token.Claims["sub"])
+
+	for name, raw := range map[string]string{
+		"parts":     "abc.def",
+		"header":    "!!!.e30.c2ln",
+		"json":      base64.RawURLEncoding.EncodeToString([]byte("[]")) + ".e30.c2ln",
+		"claims":    "eyJhbGciOiJIUzI1NiJ9.bm90IGpzb24.c2ln",
+		"signature": "eyJhbGciOiJIUzI1NiJ9.e30.!!!",
+		"alg":       "e30.e30.c2ln",
+		"none":      "eyJhbGciOiJub25lIn0.e30.",
+		"kid":       "eyJhbGciOiJIUzI1NiIsImtpZCI6MX0.e30.c2ln",
+	} {
+		_, err = Parse(raw)
+		assert.ErrorIs(t, err, ErrMalformed, name)
+	}
+}
+
+func TestToken_ValidateClaims(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	token := &Token{Claims: map[string]any{"exp": float64(1700000060), "nbf": float64(1699999940), "aud": "burgers"}}
+	assert.NoError(t, token.ValidateClaims(now, nil))
+	assert.NoError(t, token.ValidateClaims(now, []string{"fries", "burgers"}))
+	assert.ErrorIs(t, token.ValidateClaims(now, []string{"fries"}), ErrAudience)
+	assert.ErrorIs(t, token.ValidateClaims(now.Add(time.Minute), nil), ErrExpired)
+	assert.ErrorIs(t, token.ValidateClaims(now.Add(-2*time.Minute), nil), ErrNotYetValid)
+
+	token.Claims["aud"] = []any{"fries", "burgers"}
+	assert.NoError(t, token.ValidateClaims(now, []string{"burgers"}))
+
+	for _, claims := range []map[string]any{
+		{"exp": "tomorrow"},
+		{"iat": true},
+		{"aud": float64(1)},
+		{"aud": []any{"burgers", float64(1)}},
+	} {
+		assert.ErrorIs(t, (&Token{Claims: claims}).ValidateClaims(now, nil), ErrMalformed)
+	}
+
+	// no audience to check
+	assert.ErrorIs(t, (&Token{Claims: map[string]any{}}).ValidateClaims(now, []string{"burgers"}), ErrAudience)
+}
+
+func TestToken_Verify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	edPublic, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	secret := []byte("a very secret secret")
+
+	for alg, keys := range map[string][2]any{
+		"HS256": {secret, secret},
+		"HS512": {secret, secret},
+		"RS256": {rsaKey, &rsaKey.PublicKey},
+		"PS384": {rsaKey, &rsaKey.PublicKey},
+		"ES256": {ecKey, &ecKey.PublicKey},
+		"EdDSA": {edKey, edPublic},
+	} {
+		token, err := Parse(sign(t, map[string]any{"alg": alg}, map[string]any{"sub": "pb33f"}, keys[0]))
+		require.NoError(t, err, alg)
+		assert.NoError(t, token.Verify(keys[1]), alg)
+		assert.NoError(t, token.VerifyAny([]any{[]byte("wrong"), keys[1]}), alg)
+
+		// tampered claims
+		tampered := *token
+		tampered.signed += "x"
+		assert.ErrorIs(t, tampered.Verify(keys[1]), ErrSignature, alg)
+	}
+
+	token, err := Parse(sign(t, map[string]any{"alg": "RS256"}, map[string]any{}, rsaKey))
+	require.NoError(t, err)
+	assert.ErrorIs(t, token.Verify(secret), ErrSignature)
+	assert.ErrorIs(t, token.VerifyAny(nil), ErrSignature)
+
+	token.Header["alg"] = "XS256"
+	assert.ErrorIs(t, token.Verify(secret), ErrUnsupportedAlgorithm)
+	_, err = algorithmHash("RS1")
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+	assert.Equal(t, crypto.SHA384, must(algorithmHash("ES384")))
+}
+
+func must(hash crypto.Hash, _ error) crypto.Hash {
+	return hash
+}
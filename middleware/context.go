@@ -0,0 +1,25 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"context"
+
+	"github.com/pb33f/libopenapi-validator/paths"
+)
+
+type routeMatchKey struct{}
+
+// RouteMatchFromContext returns the paths.RouteMatch the middleware found for the request, so handlers and routers
+// further down the chain can use the operation and the decoded path parameters without parsing the URL again.
+// There is no match when no path in the document matches the request.
+func RouteMatchFromContext(ctx context.Context) (*paths.RouteMatch, bool) {
+	match, ok := ctx.Value(routeMatchKey{}).(*paths.RouteMatch)
+	return match, ok && match != nil
+}
+
+// ContextWithRouteMatch returns a copy of ctx that carries the supplied paths.RouteMatch.
+func ContextWithRouteMatch(ctx context.Context, match *paths.RouteMatch) context.Context {
+	return context.WithValue(ctx, routeMatchKey{}, match)
+}
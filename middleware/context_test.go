@@ -0,0 +1,47 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/paths"
+)
+
+func TestMiddleware_RouteMatchInContext(t *testing.T) {
+	for _, mode := range []Mode{ModeEnforce, ModeOff} {
+		var match *paths.RouteMatch
+		var found bool
+		m := NewMiddleware(newBurgerValidator(t), WithMode(mode), WithLogger(discardLogger()))
+		h := m.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			match, found = RouteMatchFromContext(r.Context())
+			jsonHandler(http.StatusOK, `{"name":"big mac"}`).ServeHTTP(w, r)
+		}))
+
+		recorder := serve(h, http.MethodGet, "/burgers/42", "")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		require.True(t, found, mode.String())
+		assert.Equal(t, "getBurger", match.Operation.OperationId)
+		assert.Equal(t, "/burgers/{burgerId}", match.Template)
+		assert.Equal(t, int64(42), match.PathParams["burgerId"])
+	}
+}
+
+func TestRouteMatchFromContext(t *testing.T) {
+	_, found := RouteMatchFromContext(context.Background())
+	assert.False(t, found)
+
+	_, found = RouteMatchFromContext(ContextWithRouteMatch(context.Background(), nil))
+	assert.False(t, found)
+
+	match := &paths.RouteMatch{Template: "/burgers"}
+	stored, found := RouteMatchFromContext(ContextWithRouteMatch(context.Background(), match))
+	assert.True(t, found)
+	assert.Same(t, match, stored)
+}
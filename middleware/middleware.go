@@ -0,0 +1,120 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	validator "github.com/pb33f/libopenapi-validator"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+)
+
+// Middleware validates the traffic passing through an http.Handler against an OpenAPI 3+ document.
+type Middleware struct {
+	validator validator.Validator
+	options   *Options
+}
+
+// NewMiddleware creates a new Middleware that validates traffic with the supplied validator.Validator.
+func NewMiddleware(v validator.Validator, opts ...Option) *Middleware {
+	return &Middleware{validator: v, options: NewOptions(opts...)}
+}
+
+// Handler wraps next with validation. Requests are validated before next is called, responses are buffered and
+// validated after next returns (unless response validation is disabled).
+func (m *Middleware) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		m.serveHTTP(w, r, next)
+	})
+}
+
+func (m *Middleware) serveHTTP(w http.ResponseWriter, r *http.Request, next http.Handler) {
+	match, validationErrors := m.validator.MatchRoute(r)
+	var pathItem *v3.PathItem
+	var pathValue string
+	if match != nil {
+		pathItem, pathValue = match.PathItem, match.Template
+		r = r.WithContext(ContextWithRouteMatch(r.Context(), match))
+	}
+	ctx := r.Context()
+
+	mode := m.options.Mode
+	if len(validationErrors) == 0 && match.Operation != nil && match.Operation.OperationId != "" {
+		if override, ok := m.options.OperationModes[match.Operation.OperationId]; ok {
+			mode = override
+		}
+	}
+
+	if mode == ModeOff || (mode == ModeSampled && m.options.Sampler() >= m.options.SampleRate) {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	found := len(validationErrors) == 0
+	if found {
+		_, validationErrors = m.validateRequest(ctx, r, pathItem, pathValue)
+	}
+	if errors.ContainsCancellation(validationErrors) {
+		// the client has gone away, there is nobody to respond to.
+		return
+	}
+	if len(validationErrors) > 0 {
+		m.report(ctx, r, "request", mode, validationErrors)
+		if mode == ModeEnforce {
+			m.options.ErrorRenderer(w, r, StatusCodeForErrors(validationErrors), validationErrors)
+			return
+		}
+	}
+
+	if !m.options.ResponseValidation || !found {
+		next.ServeHTTP(w, r)
+		return
+	}
+
+	buffered := NewBufferedResponseWriter(w)
+	next.ServeHTTP(buffered, r)
+
+	_, validationErrors = m.validateResponse(ctx, r, buffered.Response(r))
+	if len(validationErrors) > 0 && !errors.ContainsCancellation(validationErrors) {
+		m.report(ctx, r, "response", mode, validationErrors)
+		if mode == ModeEnforce {
+			m.options.ErrorRenderer(w, r, http.StatusInternalServerError, validationErrors)
+			return
+		}
+	}
+	_ = buffered.Commit()
+}
+
+// validateRequest validates a request with its context, when the validator accepts one.
+func (m *Middleware) validateRequest(ctx context.Context, r *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	if contextValidator, ok := m.validator.(validator.ContextValidator); ok {
+		return contextValidator.ValidateHttpRequestWithPathItemCtx(ctx, r, pathItem, pathValue)
+	}
+	return m.validator.ValidateHttpRequestWithPathItem(r, pathItem, pathValue)
+}
+
+// validateResponse validates a response with the context of its request, when the validator accepts one.
+func (m *Middleware) validateResponse(ctx context.Context, r *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	if contextValidator, ok := m.validator.(validator.ContextValidator); ok {
+		return contextValidator.ValidateHttpResponseCtx(ctx, r, response)
+	}
+	return m.validator.ValidateHttpResponse(r, response)
+}
+
+func (m *Middleware) report(ctx context.Context, r *http.Request, direction string, mode Mode, validationErrors []*errors.ValidationError) {
+	messages := make([]string, 0, len(validationErrors))
+	for _, ve := range validationErrors {
+		messages = append(messages, ve.Error())
+	}
+	m.options.Logger.WarnContext(ctx, direction+" failed OpenAPI validation",
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.String("mode", mode.String()),
+		slog.Any("errors", messages))
+}
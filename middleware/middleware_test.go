@@ -0,0 +1,288 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	validator "github.com/pb33f/libopenapi-validator"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+)
+
+const burgerSpec = `openapi: 3.1.0
+paths:
+  /burgers:
+    post:
+      operationId: createBurger
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                patties:
+                  type: integer
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        '200':
+          description: ok
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string`
+
+func newBurgerValidator(t *testing.T) validator.Validator {
+	doc, err := libopenapi.NewDocument([]byte(burgerSpec))
+	require.NoError(t, err)
+	v, errs := validator.NewValidator(doc)
+	require.Empty(t, errs)
+	return v
+}
+
+func jsonHandler(status int, body string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})
+}
+
+func serve(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(method, target, strings.NewReader(body))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestMiddleware_Enforce_ValidTraffic(t *testing.T) {
+	m := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger()))
+	h := m.Handler(jsonHandler(http.StatusCreated, `{"id":1}`))
+
+	recorder := serve(h, http.MethodPost, "/burgers", `{"name":"big mac"}`)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, `{"id":1}`, recorder.Body.String())
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+}
+
+func TestMiddleware_Enforce_InvalidBody(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	h := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger())).Handler(next)
+
+	recorder := serve(h, http.MethodPost, "/burgers", `{"patties":2}`)
+
+	assert.False(t, called)
+	assert.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+
+	var rendered ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rendered))
+	assert.Equal(t, http.StatusUnprocessableEntity, rendered.Status)
+	require.Len(t, rendered.Errors, 1)
+	assert.Equal(t, "requestBody", rendered.Errors[0].ValidationType)
+}
+
+func TestMiddleware_Enforce_InvalidParameter(t *testing.T) {
+	h := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger())).
+		Handler(jsonHandler(http.StatusOK, `{}`))
+
+	recorder := serve(h, http.MethodGet, "/burgers/cheese", "")
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestMiddleware_Enforce_UnknownPathAndMethod(t *testing.T) {
+	h := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger())).
+		Handler(jsonHandler(http.StatusOK, `{}`))
+
+	assert.Equal(t, http.StatusNotFound, serve(h, http.MethodGet, "/pizza", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(h, http.MethodDelete, "/burgers", "").Code)
+}
+
+func TestMiddleware_Enforce_InvalidResponse(t *testing.T) {
+	h := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger())).
+		Handler(jsonHandler(http.StatusCreated, `{"id":"one"}`))
+
+	recorder := serve(h, http.MethodPost, "/burgers", `{"name":"big mac"}`)
+
+	assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), `"id":"one"`)
+}
+
+func TestMiddleware_ValidatorWithoutContext(t *testing.T) {
+	// only the methods of validator.Validator are available, so validation does not use the request context.
+	v := struct{ validator.Validator }{newBurgerValidator(t)}
+	h := NewMiddleware(v, WithLogger(discardLogger())).Handler(jsonHandler(http.StatusCreated, `{"id":"one"}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(h, http.MethodPost, "/burgers", `{"patties":2}`).Code)
+	assert.Equal(t, http.StatusInternalServerError, serve(h, http.MethodPost, "/burgers", `{"name":"big mac"}`).Code)
+}
+
+func TestMiddleware_ReportOnly(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	h := NewMiddleware(newBurgerValidator(t), WithMode(ModeReportOnly), WithLogger(logger)).
+		Handler(jsonHandler(http.StatusCreated, `{"id":"one"}`))
+
+	recorder := serve(h, http.MethodPost, "/burgers", `{"patties":2}`)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, `{"id":"one"}`, recorder.Body.String())
+	assert.Contains(t, logs.String(), "request failed OpenAPI validation")
+	assert.Contains(t, logs.String(), "response failed OpenAPI validation")
+	assert.Contains(t, logs.String(), "mode=reportOnly")
+}
+
+func TestMiddleware_ReportOnly_BodyStillReadable(t *testing.T) {
+	var received string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+		w.WriteHeader(http.StatusCreated)
+	})
+	h := NewMiddleware(newBurgerValidator(t), WithMode(ModeReportOnly), WithLogger(discardLogger())).Handler(next)
+
+	serve(h, http.MethodPost, "/burgers", `{"name":"whopper"}`)
+
+	assert.Equal(t, `{"name":"whopper"}`, received)
+}
+
+func TestMiddleware_Sampled(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	sample := 0.9
+	h := NewMiddleware(newBurgerValidator(t),
+		WithSampleRate(0.5),
+		WithSampler(func() float64 { return sample }),
+		WithLogger(logger)).
+		Handler(jsonHandler(http.StatusCreated, `{"id":1}`))
+
+	// not sampled
+	recorder := serve(h, http.MethodPost, "/burgers", `{"patties":2}`)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Empty(t, logs.String())
+
+	// sampled, reported but not rejected
+	sample = 0.1
+	recorder = serve(h, http.MethodPost, "/burgers", `{"patties":2}`)
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Contains(t, logs.String(), "mode=sampled")
+}
+
+func TestMiddleware_OperationOverride(t *testing.T) {
+	h := NewMiddleware(newBurgerValidator(t),
+		WithLogger(discardLogger()),
+		WithOperationMode("getBurger", ModeOff)).
+		Handler(jsonHandler(http.StatusOK, `{}`))
+
+	// getBurger is not validated
+	assert.Equal(t, http.StatusOK, serve(h, http.MethodGet, "/burgers/cheese", "").Code)
+
+	// createBurger is still enforced
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(h, http.MethodPost, "/burgers", `{}`).Code)
+}
+
+func TestMiddleware_OperationOverride_ReportOnly(t *testing.T) {
+	h := NewMiddleware(newBurgerValidator(t),
+		WithLogger(discardLogger()),
+		WithMode(ModeReportOnly),
+		WithOperationMode("createBurger", ModeEnforce)).
+		Handler(jsonHandler(http.StatusOK, `{}`))
+
+	assert.Equal(t, http.StatusOK, serve(h, http.MethodGet, "/burgers/cheese", "").Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, serve(h, http.MethodPost, "/burgers", `{}`).Code)
+}
+
+func TestMiddleware_CustomRenderer(t *testing.T) {
+	renderer := func(w http.ResponseWriter, r *http.Request, status int, validationErrors []*errors.ValidationError) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte(validationErrors[0].Message))
+	}
+	h := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger()), WithErrorRenderer(renderer)).
+		Handler(jsonHandler(http.StatusOK, `{}`))
+
+	recorder := serve(h, http.MethodGet, "/pizza", "")
+
+	assert.Equal(t, http.StatusTeapot, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "/pizza")
+}
+
+func TestMiddleware_WithoutResponseValidation(t *testing.T) {
+	h := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger()), WithoutResponseValidation()).
+		Handler(jsonHandler(http.StatusCreated, `{"id":"one"}`))
+
+	recorder := serve(h, http.MethodPost, "/burgers", `{"name":"big mac"}`)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, `{"id":"one"}`, recorder.Body.String())
+}
+
+func TestMiddleware_CancelledRequest(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	h := NewMiddleware(newBurgerValidator(t), WithLogger(discardLogger())).Handler(next)
+
+	request := httptest.NewRequest(http.MethodPost, "/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	ctx, cancel := context.WithCancel(request.Context())
+	cancel()
+	recorder := httptest.NewRecorder()
+	h.ServeHTTP(recorder, request.WithContext(ctx))
+
+	assert.False(t, called)
+	assert.Empty(t, recorder.Body.String())
+}
@@ -0,0 +1,134 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"log/slog"
+	"math/rand/v2"
+)
+
+// Mode determines what the middleware does when validation fails.
+type Mode int
+
+const (
+	// ModeEnforce rejects invalid requests before they reach the wrapped handler, and replaces invalid responses
+	// with an error, using the configured ErrorRenderer.
+	ModeEnforce Mode = iota
+
+	// ModeReportOnly logs validation failures and passes all traffic through untouched.
+	ModeReportOnly
+
+	// ModeSampled behaves like ModeReportOnly, but only validates a fraction of traffic (see WithSampleRate).
+	ModeSampled
+
+	// ModeOff disables validation entirely. It is mostly useful as a per-operation override, for example to
+	// exclude health checks or streaming endpoints.
+	ModeOff
+)
+
+// String returns the name of the mode.
+func (m Mode) String() string {
+	switch m {
+	case ModeEnforce:
+		return "enforce"
+	case ModeReportOnly:
+		return "reportOnly"
+	case ModeSampled:
+		return "sampled"
+	case ModeOff:
+		return "off"
+	}
+	return "unknown"
+}
+
+// Options A container for middleware configuration.
+type Options struct {
+	Mode               Mode
+	SampleRate         float64         // Fraction of traffic (0.0 - 1.0) validated in ModeSampled
+	OperationModes     map[string]Mode // Per-operation mode overrides, keyed by operationId
+	ResponseValidation bool            // Buffer and validate responses produced by the wrapped handler
+	ErrorRenderer      ErrorRenderer
+	Logger             *slog.Logger
+	Sampler            func() float64 // Returns a number in [0.0, 1.0), compared against SampleRate
+}
+
+// Option Enables an 'Options pattern' approach
+type Option func(*Options)
+
+// NewOptions creates a new Options instance with default values.
+func NewOptions(opts ...Option) *Options {
+	o := &Options{
+		Mode:               ModeEnforce,
+		SampleRate:         1.0,
+		OperationModes:     map[string]Mode{},
+		ResponseValidation: true,
+		ErrorRenderer:      DefaultErrorRenderer,
+		Logger:             slog.Default(),
+		Sampler:            rand.Float64,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithMode sets the default Mode of the middleware, the default is ModeEnforce.
+func WithMode(mode Mode) Option {
+	return func(o *Options) {
+		o.Mode = mode
+	}
+}
+
+// WithSampleRate sets the fraction of traffic validated in ModeSampled, clamped between 0.0 (nothing) and 1.0
+// (everything). Sampling also switches the default mode to ModeSampled.
+func WithSampleRate(rate float64) Option {
+	return func(o *Options) {
+		o.Mode = ModeSampled
+		o.SampleRate = min(max(rate, 0), 1)
+	}
+}
+
+// WithOperationMode overrides the mode for the operation with the supplied operationId.
+func WithOperationMode(operationId string, mode Mode) Option {
+	return func(o *Options) {
+		o.OperationModes[operationId] = mode
+	}
+}
+
+// WithoutResponseValidation disables buffering and validation of responses, responses are streamed straight to
+// the client.
+func WithoutResponseValidation() Option {
+	return func(o *Options) {
+		o.ResponseValidation = false
+	}
+}
+
+// WithErrorRenderer sets the ErrorRenderer used to write rejections in ModeEnforce.
+func WithErrorRenderer(renderer ErrorRenderer) Option {
+	return func(o *Options) {
+		if renderer != nil {
+			o.ErrorRenderer = renderer
+		}
+	}
+}
+
+// WithLogger sets the logger used to report validation failures, the default is slog.Default().
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		if logger != nil {
+			o.Logger = logger
+		}
+	}
+}
+
+// WithSampler replaces the random source used to decide whether a request is sampled.
+func WithSampler(sampler func() float64) Option {
+	return func(o *Options) {
+		if sampler != nil {
+			o.Sampler = sampler
+		}
+	}
+}
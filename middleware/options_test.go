@@ -0,0 +1,52 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptions_Defaults(t *testing.T) {
+	o := NewOptions()
+
+	assert.Equal(t, ModeEnforce, o.Mode)
+	assert.Equal(t, 1.0, o.SampleRate)
+	assert.True(t, o.ResponseValidation)
+	assert.NotNil(t, o.ErrorRenderer)
+	assert.Equal(t, slog.Default(), o.Logger)
+	assert.NotNil(t, o.Sampler)
+	assert.Empty(t, o.OperationModes)
+}
+
+func TestNewOptions_NilOptionsIgnored(t *testing.T) {
+	o := NewOptions(nil, WithErrorRenderer(nil), WithLogger(nil), WithSampler(nil))
+
+	assert.NotNil(t, o.ErrorRenderer)
+	assert.NotNil(t, o.Logger)
+	assert.NotNil(t, o.Sampler)
+}
+
+func TestWithSampleRate(t *testing.T) {
+	assert.Equal(t, ModeSampled, NewOptions(WithSampleRate(0.25)).Mode)
+	assert.Equal(t, 0.25, NewOptions(WithSampleRate(0.25)).SampleRate)
+	assert.Equal(t, 0.0, NewOptions(WithSampleRate(-1)).SampleRate)
+	assert.Equal(t, 1.0, NewOptions(WithSampleRate(2)).SampleRate)
+}
+
+func TestWithOperationMode(t *testing.T) {
+	o := NewOptions(WithOperationMode("a", ModeOff), WithOperationMode("b", ModeReportOnly))
+
+	assert.Equal(t, map[string]Mode{"a": ModeOff, "b": ModeReportOnly}, o.OperationModes)
+}
+
+func TestMode_String(t *testing.T) {
+	assert.Equal(t, "enforce", ModeEnforce.String())
+	assert.Equal(t, "reportOnly", ModeReportOnly.String())
+	assert.Equal(t, "sampled", ModeSampled.String())
+	assert.Equal(t, "off", ModeOff.String())
+	assert.Equal(t, "unknown", Mode(42).String())
+}
@@ -0,0 +1,9 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+// Package middleware contains net/http middleware that validates inbound requests, and the responses produced
+// for them, against an OpenAPI 3+ document using a validator.Validator.
+//
+// The middleware can enforce the contract (rejecting invalid requests), report violations without interfering with
+// traffic, or report on a sampled fraction of traffic. The mode can be overridden per operation.
+package middleware
@@ -0,0 +1,59 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// ErrorRenderer writes a rejection to the client. The status code is a suggestion derived from the errors (see
+// StatusCodeForErrors), renderers are free to write a different one.
+type ErrorRenderer func(w http.ResponseWriter, r *http.Request, status int, validationErrors []*errors.ValidationError)
+
+// ErrorResponse is the body written by DefaultErrorRenderer.
+type ErrorResponse struct {
+	Status int                       `json:"status"`
+	Title  string                    `json:"title"`
+	Errors []*errors.ValidationError `json:"errors"`
+}
+
+// DefaultErrorRenderer writes the validation errors as a JSON ErrorResponse.
+func DefaultErrorRenderer(w http.ResponseWriter, _ *http.Request, status int, validationErrors []*errors.ValidationError) {
+	w.Header().Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(&ErrorResponse{
+		Status: status,
+		Title:  http.StatusText(status),
+		Errors: validationErrors,
+	})
+}
+
+// StatusCodeForErrors returns the status code used to reject a request that failed validation.
+//
+//   - 404 when no path in the document matches the request
+//   - 405 when the path exists, but the method is not defined
+//   - 422 when the request is well-formed, but the request body does not match the schema
+//   - 400 for everything else (parameters, content types, security)
+func StatusCodeForErrors(validationErrors []*errors.ValidationError) int {
+	status := http.StatusUnprocessableEntity
+	for _, ve := range validationErrors {
+		switch {
+		case ve.IsPathMissingError():
+			return http.StatusNotFound
+		case ve.IsOperationMissingError(),
+			ve.ValidationType == helpers.RequestValidation && ve.ValidationSubType == helpers.RequestMissingOperation:
+			return http.StatusMethodNotAllowed
+		case ve.ValidationType == helpers.RequestBodyValidation && ve.ValidationSubType == helpers.Schema:
+			continue
+		default:
+			status = http.StatusBadRequest
+		}
+	}
+	return status
+}
@@ -0,0 +1,51 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestDefaultErrorRenderer(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	request := httptest.NewRequest(http.MethodGet, "/burgers", nil)
+
+	DefaultErrorRenderer(recorder, request, http.StatusBadRequest, []*errors.ValidationError{
+		{ValidationType: helpers.ParameterValidation, Message: "bad burger"},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	assert.Equal(t, helpers.JSONContentType, recorder.Header().Get(helpers.ContentTypeHeader))
+
+	var rendered ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rendered))
+	assert.Equal(t, http.StatusBadRequest, rendered.Status)
+	assert.Equal(t, "Bad Request", rendered.Title)
+	require.Len(t, rendered.Errors, 1)
+	assert.Equal(t, "bad burger", rendered.Errors[0].Message)
+}
+
+func TestStatusCodeForErrors(t *testing.T) {
+	body := &errors.ValidationError{ValidationType: helpers.RequestBodyValidation, ValidationSubType: helpers.Schema}
+	param := &errors.ValidationError{ValidationType: helpers.ParameterValidation, ValidationSubType: helpers.ParameterValidationQuery}
+	missingPath := &errors.ValidationError{ValidationType: helpers.ParameterValidationPath, ValidationSubType: "missing"}
+	missingOp := &errors.ValidationError{ValidationType: helpers.ParameterValidationPath, ValidationSubType: helpers.RequestMissingOperation}
+	missingOpRequest := &errors.ValidationError{ValidationType: helpers.RequestValidation, ValidationSubType: helpers.RequestMissingOperation}
+
+	assert.Equal(t, http.StatusUnprocessableEntity, StatusCodeForErrors([]*errors.ValidationError{body}))
+	assert.Equal(t, http.StatusBadRequest, StatusCodeForErrors([]*errors.ValidationError{param}))
+	assert.Equal(t, http.StatusBadRequest, StatusCodeForErrors([]*errors.ValidationError{body, param}))
+	assert.Equal(t, http.StatusNotFound, StatusCodeForErrors([]*errors.ValidationError{missingPath}))
+	assert.Equal(t, http.StatusMethodNotAllowed, StatusCodeForErrors([]*errors.ValidationError{missingOp}))
+	assert.Equal(t, http.StatusMethodNotAllowed, StatusCodeForErrors([]*errors.ValidationError{missingOpRequest}))
+}
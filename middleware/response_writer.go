@@ -0,0 +1,98 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// BufferedResponseWriter is an http.ResponseWriter that holds back the status, headers and body written by a
+// handler, so the response can be validated before anything is sent to the client. Nothing reaches the wrapped
+// writer until Commit is called.
+type BufferedResponseWriter struct {
+	writer      http.ResponseWriter
+	header      http.Header
+	body        bytes.Buffer
+	status      int
+	wroteHeader bool
+}
+
+// NewBufferedResponseWriter creates a BufferedResponseWriter that wraps w. Headers already set on w are visible to
+// the handler.
+func NewBufferedResponseWriter(w http.ResponseWriter) *BufferedResponseWriter {
+	return &BufferedResponseWriter{
+		writer: w,
+		header: w.Header().Clone(),
+		status: http.StatusOK,
+	}
+}
+
+// Header returns the buffered header map.
+func (b *BufferedResponseWriter) Header() http.Header {
+	return b.header
+}
+
+// WriteHeader records the status code, only the first call has any effect.
+func (b *BufferedResponseWriter) WriteHeader(status int) {
+	if b.wroteHeader {
+		return
+	}
+	b.status = status
+	b.wroteHeader = true
+}
+
+// Write appends to the buffered body.
+func (b *BufferedResponseWriter) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+// Unwrap returns the wrapped http.ResponseWriter, for use with http.ResponseController.
+func (b *BufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return b.writer
+}
+
+// StatusCode returns the buffered status code, http.StatusOK if the handler never set one.
+func (b *BufferedResponseWriter) StatusCode() int {
+	return b.status
+}
+
+// Body returns the buffered body.
+func (b *BufferedResponseWriter) Body() []byte {
+	return b.body.Bytes()
+}
+
+// Response builds an *http.Response from the buffered status, headers and body, suitable for validation. The
+// body of the response can be read without draining the buffer.
+func (b *BufferedResponseWriter) Response(request *http.Request) *http.Response {
+	header := b.header.Clone()
+	if header.Get("Content-Length") == "" {
+		header.Set("Content-Length", strconv.Itoa(b.body.Len()))
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(b.status) + " " + http.StatusText(b.status),
+		StatusCode:    b.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(b.body.Bytes())),
+		ContentLength: int64(b.body.Len()),
+		Request:       request,
+	}
+}
+
+// Commit writes the buffered status, headers and body to the wrapped writer.
+func (b *BufferedResponseWriter) Commit() error {
+	dst := b.writer.Header()
+	for k, v := range b.header {
+		dst[k] = v
+	}
+	b.writer.WriteHeader(b.status)
+	_, err := b.writer.Write(b.body.Bytes())
+	return err
+}
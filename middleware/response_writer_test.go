@@ -0,0 +1,72 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBufferedResponseWriter_HoldsBackResponse(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	recorder.Header().Set("X-Outer", "yes")
+
+	b := NewBufferedResponseWriter(recorder)
+	assert.Equal(t, "yes", b.Header().Get("X-Outer"))
+
+	b.Header().Set("Content-Type", "text/plain")
+	b.WriteHeader(http.StatusAccepted)
+	b.WriteHeader(http.StatusTeapot) // ignored
+	_, _ = b.Write([]byte("hello "))
+	_, _ = b.Write([]byte("world"))
+
+	assert.False(t, recorder.Flushed)
+	assert.Empty(t, recorder.Body.String())
+	assert.Empty(t, recorder.Header().Get("Content-Type"))
+	assert.Equal(t, http.StatusAccepted, b.StatusCode())
+	assert.Equal(t, "hello world", string(b.Body()))
+	assert.Equal(t, recorder, b.Unwrap())
+
+	require.NoError(t, b.Commit())
+	assert.Equal(t, http.StatusAccepted, recorder.Code)
+	assert.Equal(t, "hello world", recorder.Body.String())
+	assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "yes", recorder.Header().Get("X-Outer"))
+}
+
+func TestBufferedResponseWriter_ImplicitStatus(t *testing.T) {
+	b := NewBufferedResponseWriter(httptest.NewRecorder())
+	_, _ = b.Write([]byte("ok"))
+	b.WriteHeader(http.StatusNotFound) // too late
+
+	assert.Equal(t, http.StatusOK, b.StatusCode())
+}
+
+func TestBufferedResponseWriter_Response(t *testing.T) {
+	b := NewBufferedResponseWriter(httptest.NewRecorder())
+	b.Header().Set("Content-Type", "application/json")
+	b.WriteHeader(http.StatusCreated)
+	_, _ = b.Write([]byte(`{"id":1}`))
+
+	request := httptest.NewRequest(http.MethodPost, "/burgers", nil)
+	response := b.Response(request)
+
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Equal(t, "201 Created", response.Status)
+	assert.Equal(t, "8", response.Header.Get("Content-Length"))
+	assert.Equal(t, int64(8), response.ContentLength)
+	assert.Equal(t, request, response.Request)
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, `{"id":1}`, string(body))
+
+	// reading the response does not drain the buffer
+	assert.Equal(t, `{"id":1}`, string(b.Body()))
+	assert.Empty(t, b.Header().Get("Content-Length"))
+}
@@ -4,31 +4,127 @@
 package openapi_vocabulary
 
 import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
 	"github.com/santhosh-tekuri/jsonschema/v6"
 )
 
+// DiscriminatorRefsKeyword lists, in a discriminator, the references of the oneOf (or anyOf) schemas it selects
+// from, in order. Schemas are rendered inline before they are compiled, which loses the schema names a discriminator
+// selects by, so they are recorded alongside it.
+const DiscriminatorRefsKeyword = "x-discriminator-refs"
+
+// DiscriminatorSchemasKeyword holds, in a discriminator, the oneOf (or anyOf) schemas it selects from. They are moved
+// out of the composition, so that a payload is only validated against the schema its discriminator value selects.
+const DiscriminatorSchemasKeyword = "x-discriminator-schemas"
+
 // discriminatorExtension handles the OpenAPI discriminator keyword
 type discriminatorExtension struct {
-	propertyName string
-	mapping      map[string]string // value -> schema reference
+	propertyName   string
+	mapping        map[string]string // value -> schema reference
+	defaultMapping string            // schema reference used when the value is missing or not mapped (3.2+)
+	refs           []string          // references of the schemas the discriminator selects from
+	schemas        []*jsonschema.Schema
 }
 
-// Validate validates the discriminator property exists in the instance
+// Validate validates the discriminator property exists in the instance, that its value selects one of the schemas
+// and, when the schemas were moved into the discriminator, validates the instance against the selected schema only.
 func (d *discriminatorExtension) Validate(ctx *jsonschema.ValidatorContext, v any) {
 	obj, _ := v.(map[string]any)
 
-	// check if discriminator property exists in the object
-	if d.propertyName != "" {
-		if _, exists := obj[d.propertyName]; !exists {
-			ctx.AddError(&DiscriminatorPropertyMissingError{
-				PropertyName: d.propertyName,
-			})
+	if d.propertyName == "" {
+		return
+	}
+	value, exists := obj[d.propertyName]
+	if !exists && d.defaultMapping == "" {
+		ctx.AddError(&DiscriminatorPropertyMissingError{
+			PropertyName: d.propertyName,
+		})
+		return
+	}
+	if len(d.refs) == 0 || obj == nil {
+		return
+	}
+
+	selected := -1
+	if exists {
+		name, _ := value.(string)
+		selected = d.selectSchema(name)
+	}
+	if selected < 0 && d.defaultMapping != "" {
+		selected = d.schemaIndex(d.defaultMapping)
+	}
+	if selected < 0 {
+		ctx.AddError(&DiscriminatorUnknownValueError{
+			PropertyName: d.propertyName,
+			Value:        value,
+			Allowed:      d.allowedValues(),
+		})
+		return
+	}
+
+	if selected < len(d.schemas) {
+		if err := ctx.Validate(d.schemas[selected], v, nil); err != nil {
+			ctx.AddErr(err)
 		}
 	}
 }
 
+// selectSchema returns the index of the schema a discriminator value selects, by its mapping when the mapping has
+// an entry for it, or by the implicit name of the schema (#/components/schemas/<value>) otherwise. It returns -1
+// when the value selects no schema.
+func (d *discriminatorExtension) selectSchema(value string) int {
+	if value == "" {
+		return -1
+	}
+	if target, ok := d.mapping[value]; ok {
+		return d.schemaIndex(target)
+	}
+	return d.schemaIndex("#/components/schemas/" + value)
+}
+
+// schemaIndex returns the index of the schema a mapping target references, a target is either a reference or the
+// name of a component schema.
+func (d *discriminatorExtension) schemaIndex(target string) int {
+	target = strings.TrimPrefix(target, "./")
+	if !strings.Contains(target, "/") {
+		target = "#/components/schemas/" + target
+	}
+	for i, ref := range d.refs {
+		if strings.TrimPrefix(ref, "./") == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// allowedValues returns the discriminator values that select a schema, mapped and implicit, sorted.
+func (d *discriminatorExtension) allowedValues() []string {
+	var allowed []string
+	for value, target := range d.mapping {
+		if d.schemaIndex(target) >= 0 {
+			allowed = append(allowed, value)
+		}
+	}
+	for _, ref := range d.refs {
+		if name := schemaName(ref); d.selectSchema(name) >= 0 && !slices.Contains(allowed, name) {
+			allowed = append(allowed, name)
+		}
+	}
+	sort.Strings(allowed)
+	return allowed
+}
+
+// schemaName returns the implicit name of a schema reference, the last segment of it.
+func schemaName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
 // CompileDiscriminator compiles the OpenAPI discriminator keyword
-func CompileDiscriminator(_ *jsonschema.CompilerContext, obj map[string]any, _ VersionType) (jsonschema.SchemaExt, error) {
+func CompileDiscriminator(ctx *jsonschema.CompilerContext, obj map[string]any, _ VersionType) (jsonschema.SchemaExt, error) {
 	v, exists := obj["discriminator"]
 	if !exists {
 		return nil, nil
@@ -81,8 +177,34 @@ func CompileDiscriminator(_ *jsonschema.CompilerContext, obj map[string]any, _ V
 		}
 	}
 
+	defaultMapping, _ := discriminator["defaultMapping"].(string)
+
+	// the references and schemas are recorded by the validator, a composition with a schema that is not a
+	// reference has none.
+	var refs []string
+	if refValues, ok := discriminator[DiscriminatorRefsKeyword].([]any); ok {
+		for _, ref := range refValues {
+			if strRef, ok := ref.(string); ok && strRef != "" {
+				refs = append(refs, strRef)
+			}
+		}
+		if len(refs) != len(refValues) {
+			refs = nil
+		}
+	}
+
+	var schemas []*jsonschema.Schema
+	if members, ok := discriminator[DiscriminatorSchemasKeyword].([]any); ok && ctx != nil && len(members) == len(refs) {
+		for i := range members {
+			schemas = append(schemas, ctx.Enqueue([]string{"discriminator", DiscriminatorSchemasKeyword, strconv.Itoa(i)}))
+		}
+	}
+
 	return &discriminatorExtension{
-		propertyName: propertyName,
-		mapping:      mapping,
+		propertyName:   propertyName,
+		mapping:        mapping,
+		defaultMapping: defaultMapping,
+		refs:           refs,
+		schemas:        schemas,
 	}, nil
 }
@@ -5,6 +5,7 @@ package openapi_vocabulary
 
 import (
 	"fmt"
+	"strings"
 
 	"golang.org/x/text/message"
 )
@@ -36,6 +37,30 @@ func (e *DiscriminatorPropertyMissingError) Error() string {
 	return fmt.Sprintf("discriminator property '%s' is missing", e.PropertyName)
 }
 
+// DiscriminatorUnknownValueError represents an error when the value of a discriminator property selects none of
+// the schemas of the discriminator
+type DiscriminatorUnknownValueError struct {
+	PropertyName string
+	Value        any
+	Allowed      []string
+}
+
+func (e *DiscriminatorUnknownValueError) KeywordPath() []string {
+	return []string{"discriminator"}
+}
+
+func (e *DiscriminatorUnknownValueError) LocalizedString(printer *message.Printer) string {
+	return e.Error()
+}
+
+func (e *DiscriminatorUnknownValueError) Error() string {
+	if len(e.Allowed) == 0 {
+		return fmt.Sprintf("discriminator property '%s' has unknown value '%v'", e.PropertyName, e.Value)
+	}
+	return fmt.Sprintf("discriminator property '%s' has unknown value '%v', expected one of '%s'",
+		e.PropertyName, e.Value, strings.Join(e.Allowed, "', '"))
+}
+
 // CoercionError represents an error during scalar type coercion
 type CoercionError struct {
 	SourceType string
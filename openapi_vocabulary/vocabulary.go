@@ -31,6 +31,9 @@ func NewOpenAPIVocabularyWithCoercion(version VersionType, allowCoercion bool) *
 	return &jsonschema.Vocabulary{
 		URL:    OpenAPIVocabularyURL,
 		Schema: nil, // We don't validate the vocabulary schema itself
+		Subschemas: []jsonschema.SchemaPath{
+			{jsonschema.Prop("discriminator"), jsonschema.Prop(DiscriminatorSchemasKeyword), jsonschema.AllItem{}},
+		},
 		Compile: func(ctx *jsonschema.CompilerContext, obj map[string]any) (jsonschema.SchemaExt, error) {
 			return compileOpenAPIKeywords(ctx, obj, version, allowCoercion)
 		},
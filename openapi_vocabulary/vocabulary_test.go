@@ -459,6 +459,109 @@ func TestDiscriminatorPropertyMissingError_Error(t *testing.T) {
 	assert.Equal(t, expected, err.Error())
 }
 
+func TestDiscriminatorKeyword_RoutesToSelectedSchema(t *testing.T) {
+	schemaJSON := `{
+		"discriminator": {
+			"propertyName": "type",
+			"mapping": {
+				"dog": "#/components/schemas/Dog",
+				"kitty": "Cat"
+			},
+			"x-discriminator-refs": ["#/components/schemas/Dog", "#/components/schemas/Cat"],
+			"x-discriminator-schemas": [
+				{"type": "object", "required": ["bark"], "properties": {"bark": {"type": "string"}}},
+				{"type": "object", "required": ["lives"], "properties": {"lives": {"type": "integer"}}}
+			]
+		}
+	}`
+
+	schema, err := jsonschema.UnmarshalJSON(strings.NewReader(schemaJSON))
+	assert.NoError(t, err)
+
+	compiler := jsonschema.NewCompiler()
+	compiler.RegisterVocabulary(NewOpenAPIVocabulary(Version31))
+	compiler.AssertVocabs()
+	assert.NoError(t, compiler.AddResource("test.json", schema))
+
+	compiledSchema, err := compiler.Compile("test.json")
+	assert.NoError(t, err)
+
+	assert.NoError(t, compiledSchema.Validate(map[string]any{"type": "dog", "bark": "woof"}))
+	assert.NoError(t, compiledSchema.Validate(map[string]any{"type": "kitty", "lives": 9}))
+
+	// only the selected schema is validated
+	err = compiledSchema.Validate(map[string]any{"type": "kitty", "bark": "woof"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "lives")
+	assert.NotContains(t, err.Error(), "bark")
+
+	// a value without a mapping selects the schema of the same name
+	err = compiledSchema.Validate(map[string]any{"type": "Cat", "lives": 9})
+	assert.NoError(t, err)
+	err = compiledSchema.Validate(map[string]any{"type": "Cat", "bark": "woof"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "lives")
+
+	err = compiledSchema.Validate(map[string]any{"type": "hamster", "lives": 9})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(),
+		"discriminator property 'type' has unknown value 'hamster', expected one of 'Cat', 'Dog', 'dog', 'kitty'")
+
+	err = compiledSchema.Validate(map[string]any{"lives": 9})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "discriminator property 'type' is missing")
+}
+
+func TestDiscriminatorKeyword_ImplicitNamesAndDefaultMapping(t *testing.T) {
+	obj := map[string]any{
+		"discriminator": map[string]any{
+			"propertyName":           "type",
+			DiscriminatorRefsKeyword: []any{"#/components/schemas/Dog", "#/components/schemas/Cat"},
+		},
+	}
+	ext, err := CompileDiscriminator(nil, obj, Version31)
+	assert.NoError(t, err)
+	d := ext.(*discriminatorExtension)
+	assert.Equal(t, 0, d.selectSchema("Dog"))
+	assert.Equal(t, 1, d.selectSchema("Cat"))
+	assert.Equal(t, -1, d.selectSchema("dog"))
+	assert.Equal(t, []string{"Cat", "Dog"}, d.allowedValues())
+
+	obj["discriminator"].(map[string]any)["defaultMapping"] = "#/components/schemas/Cat"
+	ext, err = CompileDiscriminator(nil, obj, Version31)
+	assert.NoError(t, err)
+	assert.Equal(t, "#/components/schemas/Cat", ext.(*discriminatorExtension).defaultMapping)
+}
+
+func TestDiscriminatorKeyword_SchemaIndexMatchesWholeReferences(t *testing.T) {
+	d := &discriminatorExtension{
+		propertyName: "type",
+		refs:         []string{"#/components/schemas/Cat", "./pets.yaml#/components/schemas/Dog"},
+	}
+	assert.Equal(t, 0, d.schemaIndex("Cat"))
+	assert.Equal(t, 0, d.schemaIndex("#/components/schemas/Cat"))
+	assert.Equal(t, 1, d.schemaIndex("pets.yaml#/components/schemas/Dog"))
+	assert.Equal(t, -1, d.schemaIndex("x/Cat"))
+	assert.Equal(t, -1, d.schemaIndex("#/components/schemas/other/Cat"))
+	assert.Equal(t, -1, d.schemaIndex("Dog"))
+	assert.Equal(t, []string{"Cat"}, d.allowedValues())
+}
+
+func TestDiscriminatorUnknownValueError(t *testing.T) {
+	err := &DiscriminatorUnknownValueError{
+		PropertyName: "type",
+		Value:        "hamster",
+		Allowed:      []string{"cat", "dog"},
+	}
+	assert.Equal(t, []string{"discriminator"}, err.KeywordPath())
+	expected := "discriminator property 'type' has unknown value 'hamster', expected one of 'cat', 'dog'"
+	assert.Equal(t, expected, err.Error())
+	assert.Equal(t, expected, err.LocalizedString(message.NewPrinter(message.MatchLanguage("en"))))
+
+	err.Allowed = nil
+	assert.Equal(t, "discriminator property 'type' has unknown value 'hamster'", err.Error())
+}
+
 func TestCoercionError_KeywordPath(t *testing.T) {
 	err := &CoercionError{
 		SourceType: "string",
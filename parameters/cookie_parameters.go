@@ -19,7 +19,7 @@ import (
 )
 
 func (v *paramValidator) ValidateCookieParams(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options.RegexCache)
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.document, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -153,6 +153,10 @@ func (v *paramValidator) ValidateCookieParamsWithPathItem(request *http.Request,
 		}
 	}
 
+	if v.options.StrictMode {
+		validationErrors = append(validationErrors, v.unknownCookies(request, pathItem, params)...)
+	}
+
 	errors.PopulateValidationErrors(validationErrors, request, pathValue)
 
 	if len(validationErrors) > 0 {
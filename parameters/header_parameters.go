@@ -20,7 +20,7 @@ import (
 )
 
 func (v *paramValidator) ValidateHeaderParams(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options.RegexCache)
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.document, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -182,6 +182,10 @@ func (v *paramValidator) ValidateHeaderParamsWithPathItem(request *http.Request,
 		}
 	}
 
+	if v.options.StrictMode {
+		validationErrors = append(validationErrors, v.unknownHeaders(request, pathItem, params)...)
+	}
+
 	errors.PopulateValidationErrors(validationErrors, request, pathValue)
 
 	if len(validationErrors) > 0 {
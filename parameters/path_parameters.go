@@ -21,7 +21,7 @@ import (
 )
 
 func (v *paramValidator) ValidatePathParams(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options.RegexCache)
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.document, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -43,7 +43,7 @@ func (v *paramValidator) ValidatePathParamsWithPathItem(request *http.Request, p
 		}}
 	}
 	// split the path into segments
-	submittedSegments := strings.Split(paths.StripRequestPath(request, v.document), helpers.Slash)
+	submittedSegments := strings.Split(paths.StripRequestPathWithOptions(request, v.document, pathItem, v.options), helpers.Slash)
 	pathSegments := strings.Split(pathValue, helpers.Slash)
 
 	// extract params for the operation
@@ -26,7 +26,7 @@ const rx = `[:\/\?#\[\]\@!\$&'\(\)\*\+,;=]`
 var rxRxp = regexp.MustCompile(rx)
 
 func (v *paramValidator) ValidateQueryParams(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options.RegexCache)
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.document, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -231,6 +231,12 @@ doneLooking:
 		}
 	}
 
+	validationErrors = append(validationErrors, v.validateQueryString(request, params)...)
+
+	if v.options.StrictMode {
+		validationErrors = append(validationErrors, v.unknownQueryParams(request, pathItem, params)...)
+	}
+
 	errors.PopulateValidationErrors(validationErrors, request, pathValue)
 
 	if len(validationErrors) > 0 {
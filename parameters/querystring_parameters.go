@@ -0,0 +1,90 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package parameters
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// validateQueryString validates the whole query string of a request against the 'querystring' parameters of the
+// operation (OpenAPI 3.2). The query string is decoded by the media type of the parameter content: form encoded
+// query strings are objects, JSON query strings are JSON documents, and anything else is a string.
+func (v *paramValidator) validateQueryString(request *http.Request, params []*v3.Parameter) []*errors.ValidationError {
+	var validationErrors []*errors.ValidationError
+	for _, p := range params {
+		if p.In != helpers.QueryString {
+			continue
+		}
+		raw := request.URL.RawQuery
+		if raw == "" {
+			if p.Required != nil && *p.Required {
+				validationErrors = append(validationErrors, errors.QueryParameterMissing(p))
+			}
+			continue
+		}
+
+		var contentType string
+		var mediaType *v3.MediaType
+		if p.Content != nil {
+			for ct, mt := range p.Content.FromOldest() {
+				contentType, mediaType = ct, mt
+				break
+			}
+		}
+		if mediaType == nil || mediaType.Schema == nil {
+			continue
+		}
+		sch := mediaType.Schema.Schema()
+		if sch == nil {
+			continue
+		}
+
+		var decoded any
+		switch {
+		case helpers.IsFormURLEncoded(contentType):
+			object, err := helpers.DecodeFormURLEncoded([]byte(raw), sch, mediaType.Encoding)
+			if err != nil {
+				validationErrors = append(validationErrors, errors.QueryStringCannotBeDecoded(p, raw, contentType))
+				continue
+			}
+			decoded = object
+		case strings.Contains(strings.ToLower(contentType), helpers.JSONType):
+			unescaped, err := url.QueryUnescape(raw)
+			if err == nil {
+				err = json.Unmarshal([]byte(unescaped), &decoded)
+			}
+			if err != nil {
+				validationErrors = append(validationErrors, errors.QueryStringCannotBeDecoded(p, raw, contentType))
+				continue
+			}
+		default:
+			unescaped, err := url.QueryUnescape(raw)
+			if err != nil {
+				validationErrors = append(validationErrors, errors.QueryStringCannotBeDecoded(p, raw, contentType))
+				continue
+			}
+			decoded = unescaped
+		}
+
+		validationErrors = append(validationErrors, ValidateSingleParameterSchema(
+			sch,
+			decoded,
+			"Query string",
+			"The query string",
+			p.Name,
+			helpers.ParameterValidation,
+			helpers.ParameterValidationQuery,
+			v.options,
+		)...)
+	}
+	return validationErrors
+}
@@ -0,0 +1,180 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package parameters
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// unknownQueryParams returns an error for every query parameter of the request that is not declared by the
+// operation. The properties of exploded form objects are spread across the query, so they are declared too, and
+// deepObject keys (name[property]) are declared by their parameter. A querystring parameter declares every key.
+func (v *paramValidator) unknownQueryParams(request *http.Request, pathItem *v3.PathItem,
+	params []*v3.Parameter,
+) []*errors.ValidationError {
+	declared := v.securityKeyNames(request, pathItem, helpers.Query)
+	for _, p := range params {
+		if p.In == helpers.QueryString {
+			return nil // the parameter describes the whole query string.
+		}
+		if p.In != helpers.Query {
+			continue
+		}
+		declared[p.Name] = true
+		if p.Schema == nil || !p.IsDefaultFormEncoding() {
+			continue
+		}
+		sch := p.Schema.Schema()
+		if sch == nil || !isObjectSchema(sch) {
+			continue
+		}
+		if allowsAdditionalProperties(sch) {
+			return nil // any key may be a property of the object.
+		}
+		if sch.Properties != nil {
+			for name := range sch.Properties.KeysFromOldest() {
+				declared[name] = true
+			}
+		}
+	}
+
+	var validationErrors []*errors.ValidationError
+	for _, key := range sortedKeys(request.URL.Query()) {
+		name := key
+		if i := strings.IndexRune(key, '['); i > 0 && strings.HasSuffix(key, "]") {
+			name = key[:i]
+		}
+		if !declared[name] {
+			validationErrors = append(validationErrors, errors.UnknownQueryParameter(request, key))
+		}
+	}
+	return validationErrors
+}
+
+// unknownHeaders returns an error for every header of the request that is not declared by the operation, is not a
+// standard HTTP header, and is not allowed by the options.
+func (v *paramValidator) unknownHeaders(request *http.Request, pathItem *v3.PathItem,
+	params []*v3.Parameter,
+) []*errors.ValidationError {
+	declared := make(map[string]bool)
+	for name := range v.securityKeyNames(request, pathItem, helpers.Header) {
+		declared[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, p := range params {
+		if p.In == helpers.Header {
+			declared[http.CanonicalHeaderKey(p.Name)] = true
+		}
+	}
+
+	var validationErrors []*errors.ValidationError
+	for _, name := range sortedKeys(request.Header) {
+		canonical := http.CanonicalHeaderKey(name)
+		if declared[canonical] || helpers.IsStandardHeader(canonical) || v.allowedHeader(canonical) {
+			continue
+		}
+		validationErrors = append(validationErrors, errors.UnknownHeader(request, name))
+	}
+	return validationErrors
+}
+
+// unknownCookies returns an error for every cookie of the request that is not declared by the operation, cookie
+// names are case-sensitive.
+func (v *paramValidator) unknownCookies(request *http.Request, pathItem *v3.PathItem,
+	params []*v3.Parameter,
+) []*errors.ValidationError {
+	declared := v.securityKeyNames(request, pathItem, helpers.Cookie)
+	for _, p := range params {
+		if p.In == helpers.Cookie {
+			declared[p.Name] = true
+		}
+	}
+
+	var validationErrors []*errors.ValidationError
+	seen := make(map[string]bool)
+	for _, cookie := range request.Cookies() {
+		if declared[cookie.Name] || seen[cookie.Name] {
+			continue
+		}
+		seen[cookie.Name] = true
+		validationErrors = append(validationErrors, errors.UnknownCookie(request, cookie.Name))
+	}
+	return validationErrors
+}
+
+// allowedHeader checks a header against the headers strict mode allows, an entry that ends with an asterisk
+// allows any header that starts with it.
+func (v *paramValidator) allowedHeader(name string) bool {
+	for _, allowed := range v.options.StrictAllowedHeaders {
+		if prefix, ok := strings.CutSuffix(allowed, helpers.Asterisk); ok {
+			if len(name) >= len(prefix) && strings.EqualFold(name[:len(prefix)], prefix) {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(name, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// securityKeyNames returns the names of the apiKey security schemes sent in a location (query, header or cookie),
+// that the operation, or the document, requires.
+func (v *paramValidator) securityKeyNames(request *http.Request, pathItem *v3.PathItem, in string) map[string]bool {
+	names := make(map[string]bool)
+	if v.document == nil || v.document.Components == nil || v.document.Components.SecuritySchemes == nil {
+		return names
+	}
+	requirements := append(helpers.ExtractSecurityForOperation(request, pathItem), v.document.Security...)
+	for _, requirement := range requirements {
+		if requirement == nil || requirement.Requirements == nil {
+			continue
+		}
+		for name := range requirement.Requirements.KeysFromOldest() {
+			scheme := v.document.Components.SecuritySchemes.GetOrZero(name)
+			if scheme != nil && strings.EqualFold(scheme.Type, "apiKey") && strings.EqualFold(scheme.In, in) {
+				names[scheme.Name] = true
+			}
+		}
+	}
+	return names
+}
+
+// allowsAdditionalProperties returns true if an object schema explicitly allows properties it does not define.
+func allowsAdditionalProperties(sch *base.Schema) bool {
+	if sch.AdditionalProperties == nil {
+		return false
+	}
+	if sch.AdditionalProperties.IsB() {
+		return sch.AdditionalProperties.B
+	}
+	return true
+}
+
+func isObjectSchema(sch *base.Schema) bool {
+	for _, typ := range sch.Type {
+		if typ == helpers.Object {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,141 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package parameters
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+var strictModeSpec = `openapi: 3.1.0
+components:
+  securitySchemes:
+    ApiKey:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    SessionKey:
+      type: apiKey
+      in: cookie
+      name: session
+paths:
+  /burgers:
+    get:
+      security:
+        - ApiKey: []
+        - SessionKey: []
+      parameters:
+        - name: limit
+          in: query
+          schema:
+            type: integer
+        - name: filter
+          in: query
+          style: deepObject
+          schema:
+            type: object
+            properties:
+              vegan:
+                type: boolean
+        - name: size
+          in: query
+          schema:
+            type: object
+            properties:
+              width:
+                type: integer
+              height:
+                type: integer
+        - name: X-Tenant
+          in: header
+          schema:
+            type: string
+        - name: PattyPreference
+          in: cookie
+          schema:
+            type: string`
+
+func strictModeValidator(t *testing.T, opts ...config.Option) ParameterValidator {
+	doc, err := libopenapi.NewDocument([]byte(strictModeSpec))
+	require.NoError(t, err)
+	m, _ := doc.BuildV3Model()
+	return NewParameterValidator(&m.Model, opts...)
+}
+
+func TestStrictMode_QueryParams(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet,
+		"https://things.com/burgers?limit=10&filter[vegan]=true&width=2&height=3&limt=10", nil)
+
+	// without strict mode, undeclared parameters are ignored
+	valid, errs := strictModeValidator(t).ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = strictModeValidator(t, config.WithStrictMode()).ValidateQueryParams(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.ParameterValidation, errs[0].ValidationType)
+	assert.Equal(t, helpers.ParameterValidationQuery, errs[0].ValidationSubType)
+	assert.Equal(t, "Query parameter 'limt' is not defined", errs[0].Message)
+	assert.Equal(t, "limt", errs[0].ParameterName)
+	assert.Equal(t, "/burgers", errs[0].SpecPath)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/burgers?limit=10&width=2", nil)
+	valid, errs = strictModeValidator(t, config.WithStrictMode()).ValidateQueryParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+}
+
+func TestStrictMode_Headers(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	request.Header.Set("Accept", "application/json")
+	request.Header.Set("User-Agent", "burger-client")
+	request.Header.Set("x-tenant", "pb33f")
+	request.Header.Set("X-API-Key", "secret")
+	request.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	request.Header.Set("X-Forwarded-For", "10.0.0.1")
+	request.Header.Set("X-Tennant", "typo")
+	request.Header.Set("X-Internal-Hop", "1")
+
+	valid, errs := strictModeValidator(t).ValidateHeaderParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = strictModeValidator(t, config.WithStrictMode()).ValidateHeaderParams(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 2)
+	assert.Equal(t, helpers.ParameterValidationHeader, errs[0].ValidationSubType)
+	assert.Equal(t, "Header 'X-Internal-Hop' is not defined", errs[0].Message)
+	assert.Equal(t, "Header 'X-Tennant' is not defined", errs[1].Message)
+
+	valid, errs = strictModeValidator(t, config.WithStrictMode(),
+		config.WithStrictAllowedHeaders("x-internal-*")).ValidateHeaderParams(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "X-Tennant", errs[0].ParameterName)
+}
+
+func TestStrictMode_Cookies(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	request.AddCookie(&http.Cookie{Name: "PattyPreference", Value: "rare"})
+	request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	request.AddCookie(&http.Cookie{Name: "pattypreference", Value: "well-done"})
+
+	valid, errs := strictModeValidator(t).ValidateCookieParams(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = strictModeValidator(t, config.WithStrictMode()).ValidateCookieParams(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.ParameterValidationCookie, errs[0].ValidationSubType)
+	assert.Equal(t, "Cookie 'pattypreference' is not defined", errs[0].Message)
+}
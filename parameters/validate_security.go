@@ -4,21 +4,28 @@
 package parameters
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strings"
+	"time"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/orderedmap"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/jwt"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
 func (v *paramValidator) ValidateSecurity(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options.RegexCache)
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.document, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -45,143 +52,418 @@ func (v *paramValidator) ValidateSecurityWithPathItem(request *http.Request, pat
 	// extract security for the operation
 	security := helpers.ExtractSecurityForOperation(request, pathItem)
 
-	if security == nil {
+	if len(security) == 0 {
 		return true, nil
 	}
 
+	// the requirements are alternatives, the request must satisfy one of them. Every scheme of a requirement must
+	// be satisfied.
 	allErrors := []*errors.ValidationError{}
-
-	for _, sec := range security {
+	for i, sec := range security {
 		if sec.ContainsEmptyRequirement {
 			return true, nil
 		}
+		validationErrors := v.validateSecurityRequirement(request, sec, i+1, len(security))
+		if len(validationErrors) == 0 {
+			return true, nil
+		}
+		errors.PopulateValidationErrors(validationErrors, request, pathValue)
+		allErrors = append(allErrors, validationErrors...)
+	}
+
+	return false, allErrors
+}
 
-		for pair := orderedmap.First(sec.Requirements); pair != nil; pair = pair.Next() {
-			secName := pair.Key()
+// validateSecurityRequirement checks every security scheme of a requirement, and returns the errors of the schemes
+// that are not satisfied. When the requirement is one of several alternatives, or has several schemes, the reason
+// of each error explains which alternative and which scheme failed.
+func (v *paramValidator) validateSecurityRequirement(request *http.Request, sec *base.SecurityRequirement,
+	alternative, alternatives int,
+) []*errors.ValidationError {
+	var members []string
+	for secName := range sec.Requirements.KeysFromOldest() {
+		members = append(members, secName)
+	}
+
+	var allErrors []*errors.ValidationError
+	for pair := orderedmap.First(sec.Requirements); pair != nil; pair = pair.Next() {
+		secName := pair.Key()
+		validationErrors := v.validateSecurityScheme(request, secName, sec, pair.Value())
+		if len(validationErrors) == 0 {
+			continue
+		}
+		if alternatives > 1 || len(members) > 1 {
+			errors.ExplainSecurityRequirement(validationErrors, secName, members, alternative, alternatives)
+		}
+		allErrors = append(allErrors, validationErrors...)
+	}
+	return allErrors
+}
 
-			// look up security from components
-			if v.document.Components == nil || v.document.Components.SecuritySchemes.GetOrZero(secName) == nil {
-				validationErrors := []*errors.ValidationError{
+// validateSecurityScheme checks a security scheme of a requirement, and returns nil if the request satisfies it.
+// Schemes that cannot be checked are satisfied.
+func (v *paramValidator) validateSecurityScheme(request *http.Request, secName string, sec *base.SecurityRequirement,
+	scopes []string,
+) []*errors.ValidationError {
+	// look up security from components
+	if v.document.Components == nil || v.document.Components.SecuritySchemes.GetOrZero(secName) == nil {
+		return []*errors.ValidationError{
+			{
+				Message: fmt.Sprintf("Security scheme '%s' is missing", secName),
+				Reason: fmt.Sprintf("The security scheme '%s' is defined as being required, "+
+					"however it's missing from the components", secName),
+				ValidationType: "security",
+				SpecLine:       sec.GoLow().Requirements.ValueNode.Line,
+				SpecCol:        sec.GoLow().Requirements.ValueNode.Column,
+				HowToFix:       "Add the missing security scheme to the components",
+			},
+		}
+	}
+	secScheme := v.document.Components.SecuritySchemes.GetOrZero(secName)
+	switch strings.ToLower(secScheme.Type) {
+	case "http":
+		switch strings.ToLower(secScheme.Scheme) {
+		case "bearer":
+			if request.Header.Get("Authorization") == "" {
+				return []*errors.ValidationError{
 					{
-						Message: fmt.Sprintf("Security scheme '%s' is missing", secName),
-						Reason: fmt.Sprintf("The security scheme '%s' is defined as being required, "+
-							"however it's missing from the components", secName),
-						ValidationType: "security",
-						SpecLine:       sec.GoLow().Requirements.ValueNode.Line,
-						SpecCol:        sec.GoLow().Requirements.ValueNode.Column,
-						HowToFix:       "Add the missing security scheme to the components",
+						Message:           fmt.Sprintf("Authorization header for '%s' scheme", secScheme.Scheme),
+						Reason:            "Authorization header was not found",
+						ValidationType:    "security",
+						ValidationSubType: secScheme.Scheme,
+						SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
+						SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
+						HowToFix:          "Add an 'Authorization' header to this request",
+					},
+				}
+			}
+			token, ok := bearerToken(request)
+			if !ok {
+				return []*errors.ValidationError{errors.BearerTokenMissing(secName, secScheme, sec)}
+			}
+			if strings.EqualFold(secScheme.BearerFormat, "JWT") {
+				return v.validateJWT(request, secName, secScheme, sec, token)
+			}
+		case "basic", "digest":
+			// check for an authorization header
+			if request.Header.Get("Authorization") == "" {
+				return []*errors.ValidationError{
+					{
+						Message:           fmt.Sprintf("Authorization header for '%s' scheme", secScheme.Scheme),
+						Reason:            "Authorization header was not found",
+						ValidationType:    "security",
+						ValidationSubType: secScheme.Scheme,
+						SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
+						SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
+						HowToFix:          "Add an 'Authorization' header to this request",
 					},
 				}
-				errors.PopulateValidationErrors(validationErrors, request, pathValue)
-
-				return false, validationErrors
 			}
-			secScheme := v.document.Components.SecuritySchemes.GetOrZero(secName)
-			switch strings.ToLower(secScheme.Type) {
-			case "http":
-				switch strings.ToLower(secScheme.Scheme) {
-				case "basic", "bearer", "digest":
-					// check for an authorization header
-					if request.Header.Get("Authorization") == "" {
-						validationErrors := []*errors.ValidationError{
-							{
-								Message:           fmt.Sprintf("Authorization header for '%s' scheme", secScheme.Scheme),
-								Reason:            "Authorization header was not found",
-								ValidationType:    "security",
-								ValidationSubType: secScheme.Scheme,
-								SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
-								SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
-								HowToFix:          "Add an 'Authorization' header to this request",
-							},
-						}
-
-						errors.PopulateValidationErrors(validationErrors, request, pathValue)
-						allErrors = append(allErrors, validationErrors...)
-					} else {
-						return true, nil
-					}
+			return v.validateCredentials(request, secName, secScheme, sec)
+		}
+
+	case "oauth2", "openidconnect":
+		return v.validateBearerToken(request, secName, secScheme, sec, scopes)
+
+	case "mutualtls":
+		return v.validateClientCertificate(request, secName, secScheme, sec)
+
+	case "apikey":
+		// check if the api key is in the request
+		if secScheme.In == "header" {
+			if request.Header.Get(secScheme.Name) == "" {
+				return []*errors.ValidationError{
+					{
+						Message:           fmt.Sprintf("API Key %s not found in header", secScheme.Name),
+						Reason:            "API Key not found in http header for security scheme 'apiKey' with type 'header'",
+						ValidationType:    "security",
+						ValidationSubType: "apiKey",
+						SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
+						SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
+						HowToFix:          fmt.Sprintf("Add the API Key via '%s' as a header of the request", secScheme.Name),
+					},
 				}
+			}
+		}
+		if secScheme.In == "query" {
+			if request.URL.Query().Get(secScheme.Name) == "" {
+				copyUrl := *request.URL
+				fixed := &copyUrl
+				q := fixed.Query()
+				q.Add(secScheme.Name, "your-api-key")
+				fixed.RawQuery = q.Encode()
 
-			case "apikey":
-				// check if the api key is in the request
-				if secScheme.In == "header" {
-					if request.Header.Get(secScheme.Name) == "" {
-						validationErrors := []*errors.ValidationError{
-							{
-								Message:           fmt.Sprintf("API Key %s not found in header", secScheme.Name),
-								Reason:            "API Key not found in http header for security scheme 'apiKey' with type 'header'",
-								ValidationType:    "security",
-								ValidationSubType: "apiKey",
-								SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
-								SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
-								HowToFix:          fmt.Sprintf("Add the API Key via '%s' as a header of the request", secScheme.Name),
-							},
-						}
-
-						errors.PopulateValidationErrors(validationErrors, request, pathValue)
-						allErrors = append(allErrors, validationErrors...)
-					} else {
-						return true, nil
-					}
+				return []*errors.ValidationError{
+					{
+						Message:           fmt.Sprintf("API Key %s not found in query", secScheme.Name),
+						Reason:            "API Key not found in URL query for security scheme 'apiKey' with type 'query'",
+						ValidationType:    "security",
+						ValidationSubType: "apiKey",
+						SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
+						SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
+						HowToFix: fmt.Sprintf("Add an API Key via '%s' to the query string "+
+							"of the URL, for example '%s'", secScheme.Name, fixed.String()),
+					},
 				}
-				if secScheme.In == "query" {
-					if request.URL.Query().Get(secScheme.Name) == "" {
-						copyUrl := *request.URL
-						fixed := &copyUrl
-						q := fixed.Query()
-						q.Add(secScheme.Name, "your-api-key")
-						fixed.RawQuery = q.Encode()
-
-						validationErrors := []*errors.ValidationError{
-							{
-								Message:           fmt.Sprintf("API Key %s not found in query", secScheme.Name),
-								Reason:            "API Key not found in URL query for security scheme 'apiKey' with type 'query'",
-								ValidationType:    "security",
-								ValidationSubType: "apiKey",
-								SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
-								SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
-								HowToFix: fmt.Sprintf("Add an API Key via '%s' to the query string "+
-									"of the URL, for example '%s'", secScheme.Name, fixed.String()),
-							},
-						}
-
-						errors.PopulateValidationErrors(validationErrors, request, pathValue)
-						allErrors = append(allErrors, validationErrors...)
-					} else {
-						return true, nil
-					}
+			}
+		}
+		if secScheme.In == "cookie" {
+			cookies := request.Cookies()
+			cookieFound := false
+			for _, cookie := range cookies {
+				if cookie.Name == secScheme.Name {
+					cookieFound = true
+					break
 				}
-				if secScheme.In == "cookie" {
-					cookies := request.Cookies()
-					cookieFound := false
-					for _, cookie := range cookies {
-						if cookie.Name == secScheme.Name {
-							cookieFound = true
-							break
-						}
-					}
-					if !cookieFound {
-						validationErrors := []*errors.ValidationError{
-							{
-								Message:           fmt.Sprintf("API Key %s not found in cookies", secScheme.Name),
-								Reason:            "API Key not found in http request cookies for security scheme 'apiKey' with type 'cookie'",
-								ValidationType:    "security",
-								ValidationSubType: "apiKey",
-								SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
-								SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
-								HowToFix:          fmt.Sprintf("Submit an API Key '%s' as a cookie with the request", secScheme.Name),
-							},
-						}
-
-						errors.PopulateValidationErrors(validationErrors, request, pathValue)
-						allErrors = append(allErrors, validationErrors...)
-					} else {
-						return true, nil
-					}
+			}
+			if !cookieFound {
+				return []*errors.ValidationError{
+					{
+						Message:           fmt.Sprintf("API Key %s not found in cookies", secScheme.Name),
+						Reason:            "API Key not found in http request cookies for security scheme 'apiKey' with type 'cookie'",
+						ValidationType:    "security",
+						ValidationSubType: "apiKey",
+						SpecLine:          sec.GoLow().Requirements.ValueNode.Line,
+						SpecCol:           sec.GoLow().Requirements.ValueNode.Column,
+						HowToFix:          fmt.Sprintf("Submit an API Key '%s' as a cookie with the request", secScheme.Name),
+					},
 				}
 			}
 		}
 	}
+	return nil
+}
 
-	return false, allErrors
+// validateBearerToken checks that the request has a bearer token for an OAuth2 or OpenID Connect security scheme.
+// When there is a token introspector, the token must be active, and granted every scope the requirement lists.
+func (v *paramValidator) validateBearerToken(request *http.Request, name string, scheme *v3.SecurityScheme,
+	requirement *base.SecurityRequirement, scopes []string,
+) []*errors.ValidationError {
+	token, ok := bearerToken(request)
+	if !ok {
+		return []*errors.ValidationError{errors.BearerTokenMissing(name, scheme, requirement)}
+	}
+	if v.options.TokenIntrospector == nil {
+		return nil
+	}
+	granted, err := v.options.TokenIntrospector.Introspect(request, name, scheme, token)
+	if err != nil {
+		return []*errors.ValidationError{errors.BearerTokenInvalid(name, scheme, requirement, err)}
+	}
+	var missing []string
+	for _, scope := range scopes {
+		if !slices.Contains(granted, scope) {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return []*errors.ValidationError{errors.SecurityScopesMissing(name, scheme, requirement, missing)}
+	}
+	return nil
+}
+
+// validateJWT checks the structure and the claims of a JWT bearer token, and its signature when there is a key
+// provider.
+func (v *paramValidator) validateJWT(request *http.Request, name string, scheme *v3.SecurityScheme,
+	requirement *base.SecurityRequirement, raw string,
+) []*errors.ValidationError {
+	token, err := jwt.Parse(raw)
+	if err == nil {
+		err = token.ValidateClaims(time.Now(), v.options.JWTAudiences)
+	}
+	if err == nil && v.options.JWTKeyProvider != nil {
+		var keys []any
+		if keys, err = v.options.JWTKeyProvider.KeysFor(request, name, scheme, token.Kid(), token.Alg()); err == nil {
+			err = token.VerifyAny(keys)
+		}
+	}
+	if err != nil {
+		return []*errors.ValidationError{errors.JWTInvalid(name, scheme, requirement, err)}
+	}
+	return nil
+}
+
+// validateCredentials checks the format of the credentials sent for an HTTP 'basic' or 'digest' security scheme,
+// and authenticates them when there is a credential verifier.
+func (v *paramValidator) validateCredentials(request *http.Request, name string, scheme *v3.SecurityScheme,
+	requirement *base.SecurityRequirement,
+) []*errors.ValidationError {
+	authScheme, value, _ := strings.Cut(strings.TrimSpace(request.Header.Get(helpers.AuthorizationHeader)), " ")
+	if !strings.EqualFold(authScheme, scheme.Scheme) {
+		return []*errors.ValidationError{errors.CredentialsMissing(name, scheme, requirement, authScheme)}
+	}
+	var credentials *config.Credentials
+	var err error
+	if strings.EqualFold(scheme.Scheme, helpers.BasicScheme) {
+		credentials, err = basicCredentials(value)
+	} else {
+		credentials, err = digestCredentials(value)
+	}
+	if err != nil {
+		return []*errors.ValidationError{errors.CredentialsMalformed(name, scheme, requirement, err)}
+	}
+	if v.options.CredentialVerifier == nil {
+		return nil
+	}
+	if err = v.options.CredentialVerifier.VerifyCredentials(request, name, scheme, credentials); err != nil {
+		return []*errors.ValidationError{errors.CredentialsRejected(name, scheme, requirement, err)}
+	}
+	return nil
+}
+
+// basicCredentials decodes the base64 encoded 'username:password' of Basic credentials (RFC 7617).
+func basicCredentials(value string) (*config.Credentials, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("there are no credentials after the scheme")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("the credentials are not base64 encoded")
+	}
+	username, password, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return nil, fmt.Errorf("the decoded credentials are not of the form 'username:password'")
+	}
+	return &config.Credentials{Scheme: "basic", Username: username, Password: password}, nil
+}
+
+// digestCredentials parses the parameters of Digest credentials (RFC 7616): username (or username*), realm, nonce,
+// uri and response are required, and cnonce and nc are required with a qop.
+func digestCredentials(value string) (*config.Credentials, error) {
+	params, err := authParams(value)
+	if err != nil {
+		return nil, err
+	}
+	required := []string{"realm", "nonce", "uri", "response"}
+	if _, ok := params["qop"]; ok {
+		required = append(required, "cnonce", "nc")
+	}
+	for _, param := range required {
+		if params[param] == "" {
+			return nil, fmt.Errorf("the '%s' parameter is missing", param)
+		}
+	}
+	if strings.Trim(strings.ToLower(params["response"]), "0123456789abcdef") != "" {
+		return nil, fmt.Errorf("the 'response' parameter is not a hexadecimal digest")
+	}
+	if nc, ok := params["nc"]; ok && (len(nc) != 8 || strings.Trim(strings.ToLower(nc), "0123456789abcdef") != "") {
+		return nil, fmt.Errorf("the 'nc' parameter is not 8 hexadecimal digits")
+	}
+
+	username, ok := params["username"]
+	if extended, found := params["username*"]; found {
+		// RFC 8187 encoding: charset'language'percent-encoded value
+		parts := strings.SplitN(extended, "'", 3)
+		if ok || len(parts) != 3 || !strings.EqualFold(parts[0], "UTF-8") {
+			return nil, fmt.Errorf("the 'username*' parameter is not a UTF-8 encoded value, or is sent with 'username'")
+		}
+		if username, err = url.PathUnescape(parts[2]); err != nil {
+			return nil, fmt.Errorf("the 'username*' parameter is not percent-encoded")
+		}
+	} else if !ok || username == "" {
+		return nil, fmt.Errorf("the 'username' parameter is missing")
+	}
+	return &config.Credentials{Scheme: "digest", Username: username, Parameters: params}, nil
+}
+
+// authParams parses a comma separated list of 'name=value' parameters, values are tokens or quoted strings. The
+// names are lower-cased.
+func authParams(value string) (map[string]string, error) {
+	params := make(map[string]string)
+	rest := strings.Trim(value, " \t,")
+	if rest == "" {
+		return nil, fmt.Errorf("there are no parameters after the scheme")
+	}
+	for rest != "" {
+		name, after, found := strings.Cut(rest, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !found || name == "" || strings.ContainsAny(name, " \t,\"") {
+			return nil, fmt.Errorf("'%s' is not a 'name=value' parameter", strings.TrimSpace(rest))
+		}
+		if _, duplicate := params[name]; duplicate {
+			return nil, fmt.Errorf("the '%s' parameter is repeated", name)
+		}
+		rest = strings.TrimLeft(after, " \t")
+
+		var param strings.Builder
+		if strings.HasPrefix(rest, `"`) {
+			closed := false
+			i := 1
+			for ; i < len(rest) && !closed; i++ {
+				switch {
+				case rest[i] == '\\' && i+1 < len(rest):
+					i++
+					param.WriteByte(rest[i])
+				case rest[i] == '"':
+					closed = true
+				default:
+					param.WriteByte(rest[i])
+				}
+			}
+			if !closed {
+				return nil, fmt.Errorf("the value of the '%s' parameter is not a closed quoted string", name)
+			}
+			rest = rest[i:]
+		} else {
+			end := strings.IndexAny(rest, ", \t")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("the '%s' parameter has no value", name)
+			}
+			param.WriteString(rest[:end])
+			rest = rest[end:]
+		}
+		params[name] = param.String()
+
+		rest = strings.TrimLeft(rest, " \t")
+		if rest != "" && rest[0] != ',' {
+			return nil, fmt.Errorf("the '%s' parameter is not followed by a comma", name)
+		}
+		rest = strings.TrimLeft(rest, " \t,")
+	}
+	return params, nil
+}
+
+// validateClientCertificate checks that the request for a 'mutualTLS' security scheme was sent with a client
+// certificate that the TLS handshake verified, and checks the certificate when there is a client certificate
+// verifier.
+func (v *paramValidator) validateClientCertificate(request *http.Request, name string, scheme *v3.SecurityScheme,
+	requirement *base.SecurityRequirement,
+) []*errors.ValidationError {
+	var reason string
+	switch {
+	case request.TLS == nil:
+		reason = "the request was not sent over TLS"
+	case len(request.TLS.PeerCertificates) == 0:
+		reason = "the request was sent without a client certificate"
+	case len(request.TLS.VerifiedChains) == 0:
+		reason = "the client certificate was not verified by the TLS handshake"
+	}
+	if reason != "" {
+		return []*errors.ValidationError{errors.ClientCertificateMissing(name, scheme, requirement, reason)}
+	}
+	if v.options.ClientCertificateVerifier == nil {
+		return nil
+	}
+	certificate := request.TLS.PeerCertificates[0]
+	if err := v.options.ClientCertificateVerifier.VerifyClientCertificate(request, name, scheme, certificate,
+		request.TLS.VerifiedChains); err != nil {
+		return []*errors.ValidationError{
+			errors.ClientCertificateRejected(name, scheme, requirement, certificate.Subject.String(), err),
+		}
+	}
+	return nil
+}
+
+// bearerToken returns the token of an 'Authorization: Bearer <token>' header, the scheme is case-insensitive.
+func bearerToken(request *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(request.Header.Get(helpers.AuthorizationHeader), " ")
+	token = strings.TrimSpace(token)
+	if !found || !strings.EqualFold(scheme, helpers.BearerScheme) || token == "" {
+		return "", false
+	}
+	return token, true
 }
@@ -4,14 +4,35 @@
 package parameters
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
+	"slices"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pb33f/libopenapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
 
 	"github.com/pb33f/libopenapi-validator/config"
+	liberrors "github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/jwt"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
@@ -263,7 +284,7 @@ components:
 	v := NewParameterValidator(&m.Model)
 
 	request, _ := http.NewRequest(http.MethodPost, "https://things.com/products", nil)
-	request.Header.Add("Authorization", "Basic 1234")
+	request.Header.Add("Authorization", "Basic dXNlcjpwYXNz")
 
 	valid, errors := v.ValidateSecurity(request)
 	assert.True(t, valid)
@@ -717,3 +738,520 @@ components:
 	assert.True(t, valid)
 	assert.Equal(t, 0, len(errors))
 }
+
+var oauthSecuritySpec = `openapi: 3.1.0
+paths:
+  /products:
+    post:
+      security:
+        - OAuth:
+          - write:products
+          - read:products
+    get:
+      security:
+        - OpenID: []
+components:
+  securitySchemes:
+    OAuth:
+      type: oauth2
+      flows:
+        clientCredentials:
+          tokenUrl: https://pb33f.io/token
+          scopes:
+            write:products: write products
+            read:products: read products
+    OpenID:
+      type: openIdConnect
+      openIdConnectUrl: https://pb33f.io/.well-known/openid-configuration
+`
+
+func TestParamValidator_ValidateSecurity_OAuth2_NotFound(t *testing.T) {
+	doc, _ := libopenapi.NewDocument([]byte(oauthSecuritySpec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/products", nil)
+	request.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+
+	valid, errors := v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Bearer token for 'OAuth' scheme not found", errors[0].Message)
+	assert.Equal(t, helpers.SecurityValidation, errors[0].ValidationType)
+	assert.Equal(t, "oauth2", errors[0].ValidationSubType)
+	assert.Equal(t, "/products", errors[0].SpecPath)
+	assert.Equal(t, http.MethodPost, errors[0].RequestMethod)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/products", nil)
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "openIdConnect", errors[0].ValidationSubType)
+}
+
+func TestParamValidator_ValidateSecurity_OAuth2(t *testing.T) {
+	doc, _ := libopenapi.NewDocument([]byte(oauthSecuritySpec))
+	m, _ := doc.BuildV3Model()
+
+	// without an introspector, a bearer token is enough
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/products", nil)
+	request.Header.Set("Authorization", "bearer abc")
+	valid, errors := NewParameterValidator(&m.Model).ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	introspector := config.TokenIntrospectorFunc(func(_ *http.Request, name string, scheme *v3.SecurityScheme,
+		token string,
+	) ([]string, error) {
+		assert.Equal(t, "OAuth", name)
+		assert.Equal(t, "oauth2", scheme.Type)
+		switch token {
+		case "abc":
+			return []string{"read:products", "write:products"}, nil
+		case "def":
+			return []string{"read:products"}, nil
+		}
+		return nil, fmt.Errorf("token is not active")
+	})
+	v := NewParameterValidator(&m.Model, config.WithTokenIntrospector(introspector))
+
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// scopes are reported separately from missing and invalid tokens
+	request.Header.Set("Authorization", "Bearer def")
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, helpers.SecurityScopes, errors[0].ValidationSubType)
+	assert.Equal(t, "Bearer token for 'OAuth' scheme is missing scopes", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "'write:products'")
+	assert.Equal(t, "Request a token that is granted the missing scopes: write:products", errors[0].HowToFix)
+
+	request.Header.Set("Authorization", "Bearer xyz")
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "oauth2", errors[0].ValidationSubType)
+	assert.Equal(t, "Bearer token for 'OAuth' scheme is not valid", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "token is not active")
+}
+
+func TestParamValidator_ValidateSecurity_RequirementConjunction(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /products:
+    post:
+      security:
+        - ApiKeyAuth: []
+          BasicAuth: []
+        - SessionKey: []
+    get:
+      security: []
+components:
+  securitySchemes:
+    ApiKeyAuth:
+      type: apiKey
+      in: header
+      name: X-API-Key
+    BasicAuth:
+      type: http
+      scheme: basic
+    SessionKey:
+      type: apiKey
+      in: cookie
+      name: session
+`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	// the first scheme of the requirement is not enough, both are required
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/products", nil)
+	request.Header.Set("X-API-Key", "1234")
+	valid, errors := v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 2)
+	assert.Equal(t, "Authorization header for 'basic' scheme", errors[0].Message)
+	assert.Equal(t, "Authorization header was not found (security requirement 1 of 2 requires "+
+		"'ApiKeyAuth' and 'BasicAuth', 'BasicAuth' is not satisfied)", errors[0].Reason)
+	assert.Equal(t, "API Key session not found in cookies", errors[1].Message)
+	assert.Equal(t, "API Key not found in http request cookies for security scheme 'apiKey' with type 'cookie' "+
+		"(security requirement 2 of 2 requires 'SessionKey', 'SessionKey' is not satisfied)", errors[1].Reason)
+	assert.Equal(t, "/products", errors[1].SpecPath)
+
+	// every scheme of the first requirement
+	request.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// the second alternative
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/products", nil)
+	request.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// an empty list of requirements removes security
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/products", nil)
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+}
+
+func signJWT(t *testing.T, header, claims map[string]any, secret []byte) string {
+	h, _ := json.Marshal(header)
+	c, _ := json.Marshal(claims)
+	signed := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestParamValidator_ValidateSecurity_BearerJWT(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /products:
+    post:
+      security:
+        - JWT: []
+components:
+  securitySchemes:
+    JWT:
+      type: http
+      scheme: bearer
+      bearerFormat: JWT
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model, config.WithJWTAudiences("burgers"))
+
+	secret := []byte("a very secret secret")
+	header := map[string]any{"alg": "HS256", "kid": "burger"}
+	exp := float64(time.Now().Add(time.Hour).Unix())
+	token := signJWT(t, header, map[string]any{"sub": "pb33f", "exp": exp, "aud": "burgers"}, secret)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/products", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	valid, errors := v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	// any other authorization scheme is not a bearer token
+	request.Header.Set("Authorization", "Basic xyz")
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, "Bearer token for 'JWT' scheme not found", errors[0].Message)
+	assert.Equal(t, "bearer", errors[0].ValidationSubType)
+
+	for reason, token := range map[string]string{
+		"malformed token":                       "Bearer xyz",
+		"token has expired":                     "Bearer " + signJWT(t, header, map[string]any{"exp": float64(1), "aud": "burgers"}, secret),
+		"token is not issued for this audience": "Bearer " + signJWT(t, header, map[string]any{"aud": "fries"}, secret),
+		"'exp' is not a number":                 "Bearer " + signJWT(t, header, map[string]any{"exp": "tomorrow"}, secret),
+		"the token is not signed":               "Bearer eyJhbGciOiJub25lIn0.e30.",
+	} {
+		request.Header.Set("Authorization", token)
+		valid, errors = v.ValidateSecurity(request)
+		assert.False(t, valid, reason)
+		require.Len(t, errors, 1, reason)
+		assert.Equal(t, "Bearer token for 'JWT' scheme is not a valid JWT", errors[0].Message)
+		assert.Contains(t, errors[0].Reason, reason)
+		assert.Equal(t, "/products", errors[0].SpecPath)
+	}
+
+	// signatures are verified with the keys of a key provider
+	jwks, err := jwt.ParseJWKS([]byte(`{"keys": [{"kty": "oct", "kid": "burger", "k": "` +
+		base64.RawURLEncoding.EncodeToString(secret) + `"}]}`))
+	require.NoError(t, err)
+	v = NewParameterValidator(&m.Model, config.WithJWTKeyProvider(jwks))
+
+	request.Header.Set("Authorization", "Bearer "+token)
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	request.Header.Set("Authorization", "Bearer "+signJWT(t, header, map[string]any{"sub": "pb33f"}, []byte("wrong")))
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "signature is not valid")
+
+	request.Header.Set("Authorization", "Bearer "+signJWT(t, map[string]any{"alg": "HS256", "kid": "fries"},
+		map[string]any{"sub": "pb33f"}, secret))
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "there is no key for 'HS256' (kid 'fries')")
+}
+
+func TestParamValidator_ValidateSecurity_BasicCredentials(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /products:
+    post:
+      security:
+        - BasicAuth: []
+components:
+  securitySchemes:
+    BasicAuth:
+      type: http
+      scheme: basic
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewParameterValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/products", nil)
+	request.Header.Set("Authorization", "Bearer xyz")
+	valid, errors := v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, helpers.CredentialsMissing, errors[0].ValidationSubType)
+	assert.Equal(t, "Basic credentials for 'BasicAuth' scheme not found", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "uses the 'Bearer' scheme")
+	assert.Equal(t, "/products", errors[0].SpecPath)
+
+	for reason, header := range map[string]string{
+		"there are no credentials after the scheme":   "Basic",
+		"the credentials are not base64 encoded":      "Basic 1234!",
+		"the decoded credentials are not of the form": "Basic " + base64.StdEncoding.EncodeToString([]byte("pb33f")),
+	} {
+		request.Header.Set("Authorization", header)
+		valid, errors = v.ValidateSecurity(request)
+		assert.False(t, valid, reason)
+		require.Len(t, errors, 1, reason)
+		assert.Equal(t, helpers.CredentialsMalformed, errors[0].ValidationSubType, reason)
+		assert.Equal(t, "Basic credentials for 'BasicAuth' scheme are malformed", errors[0].Message)
+		assert.Contains(t, errors[0].Reason, reason)
+	}
+
+	// the scheme is case-insensitive
+	request.Header.Set("Authorization", "basic "+base64.StdEncoding.EncodeToString([]byte("pb33f:burgers")))
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	verifier := config.CredentialVerifierFunc(func(_ *http.Request, name string, _ *v3.SecurityScheme,
+		credentials *config.Credentials,
+	) error {
+		assert.Equal(t, "BasicAuth", name)
+		assert.Equal(t, "basic", credentials.Scheme)
+		if credentials.Username != "pb33f" || credentials.Password != "burgers" {
+			return fmt.Errorf("unknown user '%s'", credentials.Username)
+		}
+		return nil
+	})
+	v = NewParameterValidator(&m.Model, config.WithCredentialVerifier(verifier))
+
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+
+	request.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte("fries:burgers")))
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, helpers.CredentialsRejected, errors[0].ValidationSubType)
+	assert.Equal(t, "Basic credentials for 'BasicAuth' scheme are not valid", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "unknown user 'fries'")
+}
+
+func TestParamValidator_ValidateSecurity_DigestCredentials(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /products:
+    post:
+      security:
+        - DigestAuth: []
+components:
+  securitySchemes:
+    DigestAuth:
+      type: http
+      scheme: digest
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	var verified *config.Credentials
+	v := NewParameterValidator(&m.Model, config.WithCredentialVerifier(config.CredentialVerifierFunc(
+		func(_ *http.Request, _ string, _ *v3.SecurityScheme, credentials *config.Credentials) error {
+			verified = credentials
+			return nil
+		})))
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/products", nil)
+	request.Header.Set("Authorization", `Digest username="pb33f", realm="burgers@pb33f.io", `+
+		`nonce="dcd98b7102dd2f0e8b11d0f600bfb0c093", uri="/products", qop=auth, nc=00000001, `+
+		`cnonce="0a4f113b", response="6629fae49393a05397450978507c4ef1", opaque="a \"quoted\" value"`)
+	valid, errors := v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+	require.NotNil(t, verified)
+	assert.Equal(t, "digest", verified.Scheme)
+	assert.Equal(t, "pb33f", verified.Username)
+	assert.Equal(t, "auth", verified.Parameters["qop"])
+	assert.Equal(t, `a "quoted" value`, verified.Parameters["opaque"])
+
+	request.Header.Set("Authorization", `Digest username*=UTF-8''p%C3%A9tstore, realm="pb33f", nonce="abc", `+
+		`uri="/products", response="6629fae49393a05397450978507c4ef1"`)
+	valid, errors = v.ValidateSecurity(request)
+	assert.True(t, valid)
+	assert.Empty(t, errors)
+	assert.Equal(t, "pétstore", verified.Username)
+
+	request.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	valid, errors = v.ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Equal(t, helpers.CredentialsMissing, errors[0].ValidationSubType)
+	assert.Equal(t, "Digest credentials for 'DigestAuth' scheme not found", errors[0].Message)
+
+	for reason, params := range map[string]string{
+		"there are no parameters after the scheme":   "",
+		"the 'nonce' parameter is missing":           `username="pb33f", realm="pb33f", uri="/", response="abc"`,
+		"the 'cnonce' parameter is missing":          `username="pb33f", realm="pb33f", nonce="n", uri="/", response="abc", qop=auth, nc=00000001`,
+		"the 'username' parameter is missing":        `realm="pb33f", nonce="n", uri="/", response="abc"`,
+		"'response' parameter is not a hexadecimal":  `username="pb33f", realm="pb33f", nonce="n", uri="/", response="xyz"`,
+		"the 'nc' parameter is not 8 hexadecimal":    `username="pb33f", realm="pb33f", nonce="n", uri="/", response="abc", qop=auth, cnonce="c", nc=1`,
+		"the 'realm' parameter is repeated":          `username="pb33f", realm="pb33f", realm="pb33f"`,
+		"is not a closed quoted string":              `username="pb33f`,
+		"is not followed by a comma":                 `username="pb33f" realm="pb33f"`,
+		"is not a 'name=value' parameter":            `username`,
+		"the 'uri' parameter has no value":           `username="pb33f", uri=, realm="pb33f"`,
+		"the 'username*' parameter is not a UTF-8":   `username*=pb33f, realm="pb33f", nonce="n", uri="/", response="abc"`,
+		"'username*' parameter is not percent-encod": `username*=UTF-8''%zz, realm="pb33f", nonce="n", uri="/", response="abc"`,
+	} {
+		request.Header.Set("Authorization", "Digest "+params)
+		valid, errors = v.ValidateSecurity(request)
+		assert.False(t, valid, reason)
+		require.Len(t, errors, 1, reason)
+		assert.Equal(t, helpers.CredentialsMalformed, errors[0].ValidationSubType, reason)
+		assert.Equal(t, "Digest credentials for 'DigestAuth' scheme are malformed", errors[0].Message)
+		assert.Contains(t, errors[0].Reason, reason)
+		assert.Contains(t, errors[0].HowToFix, "'Authorization: Digest'")
+	}
+}
+
+// issueCertificate creates a certificate for a common name and DNS name, signed by the parent (self-signed when
+// the parent is nil).
+func issueCertificate(t *testing.T, name string, parent *tls.Certificate) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	issuer, signer := template, any(key)
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		issuer, signer = parent.Leaf, parent.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, issuer, &key.PublicKey, signer)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}
+}
+
+func TestParamValidator_ValidateSecurity_MutualTLS(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /products:
+    get:
+      security:
+        - ClientCert: []
+components:
+  securitySchemes:
+    ClientCert:
+      type: mutualTLS
+`
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+
+	ca := issueCertificate(t, "pb33f CA", nil)
+	burgers := issueCertificate(t, "burgers.pb33f.io", &ca)
+	fries := issueCertificate(t, "fries.pb33f.io", &ca)
+
+	verifier := config.ClientCertificateVerifierFunc(func(_ *http.Request, name string, _ *v3.SecurityScheme,
+		certificate *x509.Certificate, chains [][]*x509.Certificate,
+	) error {
+		assert.Equal(t, "ClientCert", name)
+		assert.Equal(t, "pb33f CA", chains[0][len(chains[0])-1].Subject.CommonName)
+		if certificate.Issuer.CommonName != "pb33f CA" || !slices.Contains(certificate.DNSNames, "burgers.pb33f.io") {
+			return fmt.Errorf("'%s' is not allowed", certificate.DNSNames[0])
+		}
+		return nil
+	})
+	validators := map[bool]ParameterValidator{
+		false: NewParameterValidator(&m.Model),
+		true:  NewParameterValidator(&m.Model, config.WithClientCertificateVerifier(verifier)),
+	}
+
+	var errors []*liberrors.ValidationError
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, errors = validators[r.URL.Query().Has("verify")].ValidateSecurity(r)
+	}))
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Leaf)
+	server.TLS = &tls.Config{ClientCAs: pool, ClientAuth: tls.VerifyClientCertIfGiven}
+	server.StartTLS()
+	defer server.Close()
+
+	send := func(certificate *tls.Certificate, query string) {
+		transport := server.Client().Transport.(*http.Transport).Clone()
+		if certificate != nil {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{*certificate}
+		}
+		response, err := (&http.Client{Transport: transport}).Get(server.URL + "/products" + query)
+		require.NoError(t, err)
+		_ = response.Body.Close()
+	}
+
+	send(&burgers, "")
+	assert.Empty(t, errors)
+	send(&fries, "")
+	assert.Empty(t, errors)
+
+	send(nil, "")
+	require.Len(t, errors, 1)
+	assert.Equal(t, helpers.CertificateMissing, errors[0].ValidationSubType)
+	assert.Equal(t, "Client certificate for 'ClientCert' scheme not found", errors[0].Message)
+	assert.Contains(t, errors[0].Reason, "the request was sent without a client certificate")
+	assert.Equal(t, "/products", errors[0].SpecPath)
+
+	// the subject, SANs and issuer are checked by the verifier
+	send(&burgers, "?verify")
+	assert.Empty(t, errors)
+	send(&fries, "?verify")
+	require.Len(t, errors, 1)
+	assert.Equal(t, helpers.CertificateRejected, errors[0].ValidationSubType)
+	assert.Equal(t, "Client certificate for 'ClientCert' scheme is not valid", errors[0].Message)
+	assert.Equal(t, "The client certificate 'CN=fries.pb33f.io' sent for the 'ClientCert' security scheme was "+
+		"rejected: 'fries.pb33f.io' is not allowed", errors[0].Reason)
+
+	// plain HTTP, and certificates that were not verified by the handshake
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/products", nil)
+	valid, errors := validators[false].ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "the request was not sent over TLS")
+
+	request.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{burgers.Leaf}}
+	valid, errors = validators[false].ValidateSecurity(request)
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.Contains(t, errors[0].Reason, "the client certificate was not verified by the TLS handshake")
+}
@@ -25,75 +25,141 @@ import (
 // that were picked up when locating the path.
 // The third return value will be the path that was found in the document, as it pertains to the contract, so all path
 // parameters will not have been replaced with their values from the request - allowing model lookups.
+//
+// When more than one path matches, concrete paths are matched before templated ones: the path with the most literal
+// segments wins, then the path with the most literal characters. If several paths are equally specific, the first
+// one in the document is returned along with an error that reports the ambiguity (FindPathWithOptions can leave the
+// error out, see config.WithoutAmbiguousPathErrors).
 func FindPath(request *http.Request, document *v3.Document, regexCache config.RegexCache) (*v3.PathItem, []*errors.ValidationError, string) {
+	servers := newServerIndex(document, regexCache)
+	pathItem, foundPath, hasOperation, ambiguous := findPathItem(request, document, servers, regexCache, false).result()
+	return pathResult(request, pathItem, foundPath, hasOperation, ambiguous)
+}
+
+// FindPathWithOptions works in the same way as FindPath, but uses the compiled routing index set on the options
+// (see config.WithPathRouter) when there is one, instead of scanning every path in the document. When strict
+// server matching is enabled, the scheme and host of the request must match one of the servers that apply to the
+// operation as well.
+func FindPathWithOptions(request *http.Request, document *v3.Document, options *config.ValidationOptions) (*v3.PathItem, []*errors.ValidationError, string) {
+	if options == nil {
+		return FindPath(request, document, nil)
+	}
+	if options.PathRouter != nil {
+		match := options.PathRouter.FindPathItem(request)
+		return pathResult(request, match.PathItem, match.PathValue, match.HasOperation, reportAmbiguous(options, match.Ambiguous))
+	}
+	servers := newServerIndex(document, options.RegexCache)
+	pathItem, foundPath, hasOperation, ambiguous := findPathItem(request, document, servers, options.RegexCache,
+		options.StrictServerMatching).result()
+	return pathResult(request, pathItem, foundPath, hasOperation, reportAmbiguous(options, ambiguous))
+}
+
+// reportAmbiguous returns the other templates that a request matches, unless ambiguous matches are not reported as
+// errors (see config.WithoutAmbiguousPathErrors).
+func reportAmbiguous(options *config.ValidationOptions, ambiguous []string) []string {
+	if options != nil && !options.AmbiguousPathErrors {
+		return nil
+	}
+	return ambiguous
+}
+
+// findPathItem scans every path in the document for the ones that match the request, and selects the most
+// specific (see selectCandidate). Each path is compared with the request path once the base path of the servers
+// that apply to it has been stripped, the servers are compiled by the index (a Router shares its own).
+func findPathItem(request *http.Request, document *v3.Document, servers *serverIndex, regexCache config.RegexCache, strict bool) *pathMatch {
 	basePaths := getBasePaths(document)
-	stripped := StripRequestPath(request, document)
 
-	reqPathSegments := strings.Split(stripped, "/")
-	if reqPathSegments[0] == "" {
-		reqPathSegments = reqPathSegments[1:]
+	type groupPath struct {
+		stripped string
+		server   *v3.Server
+		segments []string
+		ok       bool
+	}
+	resolved := make(map[int]*groupPath)
+	resolve := func(group int) *groupPath {
+		if gp, ok := resolved[group]; ok {
+			return gp
+		}
+		gp := &groupPath{}
+		gp.stripped, gp.server, gp.ok = servers.groups[group].requestPath(request, strict)
+		gp.segments = strings.Split(gp.stripped, "/")
+		if gp.segments[0] == "" {
+			gp.segments = gp.segments[1:]
+		}
+		resolved[group] = gp
+		return gp
 	}
 
-	var pItem *v3.PathItem
-	var foundPath string
+	var candidates []*candidate
+	index := 0
 	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
-		path := pair.Key()
 		pathItem := pair.Value()
+		index++
 
-		// if the stripped path has a fragment, then use that as part of the lookup
-		// if not, then strip off any fragments from the pathItem
-		if !strings.Contains(stripped, "#") {
-			if strings.Contains(path, "#") {
-				path = strings.Split(path, "#")[0]
+		for _, target := range servers.targets(pathItem, []*v3.Operation{helpers.ExtractOperation(request, pathItem)}) {
+			gp := resolve(target.group)
+			if !gp.ok {
+				continue
 			}
-		}
-
-		segs := strings.Split(path, "/")
-		if segs[0] == "" {
-			segs = segs[1:]
-		}
+			path := pair.Key()
 
-		ok := comparePaths(segs, reqPathSegments, basePaths, regexCache)
-		if !ok {
-			continue
-		}
-		pItem = pathItem
-		foundPath = path
-		switch request.Method {
-		case http.MethodGet:
-			if pathItem.Get != nil {
-				return pathItem, nil, path
-			}
-		case http.MethodPost:
-			if pathItem.Post != nil {
-				return pathItem, nil, path
+			// if the stripped path has a fragment, then use that as part of the lookup
+			// if not, then strip off any fragments from the pathItem
+			if !strings.Contains(gp.stripped, "#") {
+				if strings.Contains(path, "#") {
+					path = strings.Split(path, "#")[0]
+				}
 			}
-		case http.MethodPut:
-			if pathItem.Put != nil {
-				return pathItem, nil, path
-			}
-		case http.MethodDelete:
-			if pathItem.Delete != nil {
-				return pathItem, nil, path
-			}
-		case http.MethodOptions:
-			if pathItem.Options != nil {
-				return pathItem, nil, path
-			}
-		case http.MethodHead:
-			if pathItem.Head != nil {
-				return pathItem, nil, path
-			}
-		case http.MethodPatch:
-			if pathItem.Patch != nil {
-				return pathItem, nil, path
+
+			segs := strings.Split(path, "/")
+			if segs[0] == "" {
+				segs = segs[1:]
 			}
-		case http.MethodTrace:
-			if pathItem.Trace != nil {
-				return pathItem, nil, path
+
+			ok := comparePaths(segs, gp.segments, basePaths, regexCache)
+			if !ok {
+				continue
 			}
+			candidates = append(candidates, newCandidate(index, path, pathItem, target, segs))
 		}
 	}
+
+	selected, hasOperation, ambiguous := selectCandidate(request, candidates)
+	if selected == nil {
+		return &pathMatch{}
+	}
+	gp := resolved[selected.group]
+	return &pathMatch{
+		candidate:    selected,
+		hasOperation: hasOperation,
+		ambiguous:    ambiguous,
+		server:       gp.server,
+		segments:     gp.segments,
+	}
+}
+
+// pathResult converts the outcome of a path lookup into the values returned by FindPath. Ambiguous templates are
+// reported as an error, callers leave them out when they are asked to.
+func pathResult(request *http.Request, pItem *v3.PathItem, foundPath string, hasOperation bool, ambiguous []string) (*v3.PathItem, []*errors.ValidationError, string) {
+	if pItem != nil && hasOperation && len(ambiguous) > 0 {
+		validationErrors := []*errors.ValidationError{{
+			ValidationType:    helpers.ParameterValidationPath,
+			ValidationSubType: helpers.PathAmbiguous,
+			Message: fmt.Sprintf("%s Path '%s' matches more than one path in the specification",
+				request.Method, request.URL.Path),
+			Reason: fmt.Sprintf("The %s request for '%s' matches '%s', and also '%s', which are just as specific",
+				request.Method, request.URL.Path, foundPath, strings.Join(ambiguous, "', '")),
+			SpecLine: -1,
+			SpecCol:  -1,
+			HowToFix: errors.HowToFixPathAmbiguous,
+			Context:  append([]string{foundPath}, ambiguous...),
+		}}
+		errors.PopulateValidationErrors(validationErrors, request, foundPath)
+		return pItem, validationErrors, foundPath
+	}
+	if pItem != nil && hasOperation {
+		return pItem, nil, foundPath
+	}
 	if pItem != nil {
 		validationErrors := []*errors.ValidationError{{
 			ValidationType:    helpers.ParameterValidationPath,
@@ -150,18 +216,14 @@ func getBasePaths(document *v3.Document) []string {
 	return basePaths
 }
 
-// StripRequestPath strips the base path from the request path, based on the server paths provided in the specification
+// StripRequestPath strips the base path from the request path, based on the servers of the document. Server
+// variables are expanded to their enum values, or their default.
 func StripRequestPath(request *http.Request, document *v3.Document) string {
-	basePaths := getBasePaths(document)
-
-	// strip any base path
-	stripped := stripBaseFromPath(request.URL.EscapedPath(), basePaths)
-	if request.URL.Fragment != "" {
-		stripped = fmt.Sprintf("%s#%s", stripped, request.URL.Fragment)
-	}
-	if len(stripped) > 0 && !strings.HasPrefix(stripped, "/") {
-		stripped = "/" + stripped
+	var servers []*v3.Server
+	if document != nil {
+		servers = document.Servers
 	}
+	stripped, _, _ := newServerGroup(servers, nil).requestPath(request, false)
 	return stripped
 }
 
@@ -181,15 +243,6 @@ func checkPathAgainstBase(docPath, urlPath string, basePaths []string) bool {
 	return false
 }
 
-func stripBaseFromPath(path string, basePaths []string) string {
-	for i := range basePaths {
-		if strings.HasPrefix(path, basePaths[i]) {
-			return path[len(basePaths[i]):]
-		}
-	}
-	return path
-}
-
 func comparePaths(mapped, requested, basePaths []string, regexCache config.RegexCache) bool {
 	if len(mapped) != len(requested) {
 		return false // short circuit out
@@ -0,0 +1,126 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"net/http"
+	"slices"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// candidate is a path in the document that matches a request.
+type candidate struct {
+	index           int // position of the path in the document
+	template        string
+	pathItem        *v3.PathItem
+	group           int           // the group of servers the path was reached through
+	operation       *v3.Operation // set when the group of servers belongs to this operation alone
+	literalSegments int           // segments without any path variables
+	literalChars    int           // characters outside path variables, across all segments
+}
+
+func newCandidate(index int, template string, pathItem *v3.PathItem, target serverTarget, segs []string) *candidate {
+	c := &candidate{index: index, template: template, pathItem: pathItem, group: target.group, operation: target.operation}
+	for _, seg := range segs {
+		if !isTemplatedSegment(seg) {
+			c.literalSegments++
+			c.literalChars += len(seg)
+			continue
+		}
+		c.literalChars += len(seg)
+		if idxs, err := helpers.BraceIndices(seg); err == nil {
+			for i := 0; i < len(idxs); i += 2 {
+				c.literalChars -= idxs[i+1] - idxs[i]
+			}
+		}
+	}
+	return c
+}
+
+// compareSpecificity orders candidates from the most specific to the least. Concrete paths are matched before
+// templated ones: more literal segments win, then more literal characters. Equally specific candidates keep their
+// document order.
+func compareSpecificity(a, b *candidate) int {
+	if a.literalSegments != b.literalSegments {
+		return b.literalSegments - a.literalSegments
+	}
+	if a.literalChars != b.literalChars {
+		return b.literalChars - a.literalChars
+	}
+	return a.index - b.index
+}
+
+// hasOperation returns true if the candidate can serve the request method. An operation with its own servers can
+// only be reached through them.
+func (c *candidate) hasOperation(op *v3.Operation) bool {
+	if op == nil {
+		return false
+	}
+	if c.operation != nil {
+		return c.operation == op
+	}
+	return len(op.Servers) == 0
+}
+
+// pathMatch is the outcome of a path lookup.
+type pathMatch struct {
+	candidate    *candidate // nil when no path matched
+	hasOperation bool
+	ambiguous    []string
+	server       *v3.Server // the server the request was matched through, if any
+	segments     []string   // the request path segments, without the base path of the server
+}
+
+func (m *pathMatch) result() (*v3.PathItem, string, bool, []string) {
+	if m.candidate == nil {
+		return nil, "", false, nil
+	}
+	return m.candidate.pathItem, m.candidate.template, m.hasOperation, m.ambiguous
+}
+
+func equallySpecific(a, b *candidate) bool {
+	return a.literalSegments == b.literalSegments && a.literalChars == b.literalChars
+}
+
+// selectCandidate picks the most specific candidate that defines an operation for the request method. If none do,
+// the most specific candidate is returned without an operation. The templates of any other candidates that define
+// the operation, and are just as specific as the winner, are returned as ambiguous.
+func selectCandidate(request *http.Request, candidates []*candidate) (*candidate, bool, []string) {
+	if len(candidates) == 0 {
+		return nil, false, nil
+	}
+	withOperation := make([]*candidate, 0, len(candidates))
+	reachable := candidates[:0:0]
+	for _, c := range candidates {
+		op := helpers.ExtractOperation(request, c.pathItem)
+		if c.operation != nil && c.operation != op {
+			// reached through the servers of another method's operation
+			continue
+		}
+		reachable = append(reachable, c)
+		if c.hasOperation(op) {
+			withOperation = append(withOperation, c)
+		}
+	}
+	if len(reachable) == 0 {
+		return nil, false, nil
+	}
+	if len(withOperation) == 0 {
+		return slices.MinFunc(reachable, compareSpecificity), false, nil
+	}
+
+	slices.SortFunc(withOperation, compareSpecificity)
+	best := withOperation[0]
+	var ambiguous []string
+	for _, c := range withOperation[1:] {
+		if !equallySpecific(best, c) {
+			break
+		}
+		ambiguous = append(ambiguous, c.template)
+	}
+	return best, true, ambiguous
+}
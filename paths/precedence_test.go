@@ -0,0 +1,208 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+// findBoth runs the lookup through the linear scan and the router, and checks they agree.
+func findBoth(t *testing.T, m *v3.Document, method, url string) (*v3.PathItem, string, int) {
+	t.Helper()
+	assertSameAsFindPath(t, m, method, url)
+	request, _ := http.NewRequest(method, url, nil)
+	pathItem, errs, path := FindPath(request, m, nil)
+	return pathItem, path, len(errs)
+}
+
+func TestFindPath_ConcreteBeforeTemplated(t *testing.T) {
+	for _, spec := range []string{`openapi: 3.1.0
+paths:
+  /users/{id}:
+    get:
+      operationId: getUser
+  /users/me:
+    get:
+      operationId: getMe
+`, `openapi: 3.1.0
+paths:
+  /users/me:
+    get:
+      operationId: getMe
+  /users/{id}:
+    get:
+      operationId: getUser
+`} {
+		m := buildModel(t, []byte(spec))
+
+		pathItem, path, errs := findBoth(t, m, http.MethodGet, "https://things.com/users/me")
+		assert.Zero(t, errs)
+		assert.Equal(t, "getMe", pathItem.Get.OperationId)
+		assert.Equal(t, "/users/me", path)
+
+		pathItem, path, errs = findBoth(t, m, http.MethodGet, "https://things.com/users/123")
+		assert.Zero(t, errs)
+		assert.Equal(t, "getUser", pathItem.Get.OperationId)
+		assert.Equal(t, "/users/{id}", path)
+	}
+}
+
+func TestFindPath_MostLiteralCharacters(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /reports/{id}:
+    get:
+      operationId: report
+  /reports/{id}.json:
+    get:
+      operationId: reportJson
+  /reports/{id}/{format}:
+    get:
+      operationId: reportFormat
+  /reports/{id}/pdf:
+    get:
+      operationId: reportPdf
+`
+	m := buildModel(t, []byte(spec))
+
+	pathItem, _, errs := findBoth(t, m, http.MethodGet, "https://things.com/reports/1.json")
+	assert.Zero(t, errs)
+	assert.Equal(t, "reportJson", pathItem.Get.OperationId)
+
+	pathItem, _, errs = findBoth(t, m, http.MethodGet, "https://things.com/reports/1")
+	assert.Zero(t, errs)
+	assert.Equal(t, "report", pathItem.Get.OperationId)
+
+	pathItem, _, errs = findBoth(t, m, http.MethodGet, "https://things.com/reports/1/pdf")
+	assert.Zero(t, errs)
+	assert.Equal(t, "reportPdf", pathItem.Get.OperationId)
+
+	pathItem, _, errs = findBoth(t, m, http.MethodGet, "https://things.com/reports/1/csv")
+	assert.Zero(t, errs)
+	assert.Equal(t, "reportFormat", pathItem.Get.OperationId)
+}
+
+func TestFindPath_MethodOnlyOnTemplatedPath(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /users/me:
+    get:
+      operationId: getMe
+  /users/{id}:
+    delete:
+      operationId: deleteUser
+`
+	m := buildModel(t, []byte(spec))
+
+	// the concrete path has no DELETE, so the templated path is used.
+	pathItem, path, errs := findBoth(t, m, http.MethodDelete, "https://things.com/users/me")
+	assert.Zero(t, errs)
+	assert.Equal(t, "deleteUser", pathItem.Delete.OperationId)
+	assert.Equal(t, "/users/{id}", path)
+
+	// neither has PUT, the most specific path is reported.
+	pathItem, path, errs = findBoth(t, m, http.MethodPut, "https://things.com/users/me")
+	assert.Equal(t, 1, errs)
+	assert.NotNil(t, pathItem)
+	assert.Equal(t, "/users/me", path)
+}
+
+func TestFindPath_Ambiguous(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /{entity}/books:
+    get:
+      operationId: entityBooks
+  /books/{id}:
+    get:
+      operationId: getBook
+  /books/{isbn}:
+    post:
+      operationId: postBook
+`
+	m := buildModel(t, []byte(spec))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/books/books", nil)
+
+	// the first equally specific path in the document is used, and the ambiguity is reported alongside it, so
+	// validation can carry on.
+	pathItem, errs, path := FindPath(request, m, nil)
+	assert.Equal(t, "entityBooks", pathItem.Get.OperationId)
+	assert.Equal(t, "/{entity}/books", path)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].IsPathAmbiguousError())
+
+	for _, router := range []config.PathRouter{nil, NewRouter(m)} {
+		options := config.NewValidationOptions(config.WithPathRouter(router))
+		pathItem, errs, path = FindPathWithOptions(request, m, options)
+		assert.Equal(t, "entityBooks", pathItem.Get.OperationId)
+		assert.Equal(t, "/{entity}/books", path)
+		require.Len(t, errs, 1)
+		assert.True(t, errs[0].IsPathAmbiguousError())
+
+		match, matchErrs := MatchRoute(request, m, options)
+		require.NotNil(t, match)
+		require.Len(t, matchErrs, 1)
+		assert.True(t, matchErrs[0].IsPathAmbiguousError())
+	}
+
+	// when asked not to, the ambiguity is only listed in the match.
+	for _, router := range []config.PathRouter{nil, NewRouter(m, config.WithoutAmbiguousPathErrors())} {
+		options := config.NewValidationOptions(config.WithoutAmbiguousPathErrors(), config.WithPathRouter(router))
+		item, noErrs, template := FindPathWithOptions(request, m, options)
+		assert.Equal(t, "entityBooks", item.Get.OperationId)
+		assert.Equal(t, "/{entity}/books", template)
+		assert.Empty(t, noErrs)
+
+		match, noErrs := MatchRoute(request, m, options)
+		require.NotNil(t, match)
+		assert.Empty(t, noErrs)
+		assert.Equal(t, []string{"/books/{id}"}, match.Ambiguous)
+	}
+	_, noErrs, _ := NewRouter(m, config.WithoutAmbiguousPathErrors()).FindPath(request)
+	assert.Empty(t, noErrs)
+
+	// the errors of the last lookup, through the router
+	assert.True(t, errs[0].IsPathAmbiguousError())
+	assert.Equal(t, "GET Path '/books/books' matches more than one path in the specification", errs[0].Message)
+	assert.Equal(t, "The GET request for '/books/books' matches '/{entity}/books', and also '/books/{id}', "+
+		"which are just as specific", errs[0].Reason)
+	assert.Equal(t, []string{"/{entity}/books", "/books/{id}"}, errs[0].Context)
+	assert.Equal(t, "/{entity}/books", errs[0].SpecPath)
+
+	route := NewRouter(m).Lookup(request)
+	require.NotNil(t, route)
+	assert.Equal(t, "/{entity}/books", route.Template)
+	assert.Equal(t, []string{"/books/{id}"}, route.Ambiguous)
+	assertSameAsFindPath(t, m, http.MethodGet, "https://things.com/books/books")
+
+	// only paths that define the operation can be ambiguous
+	pathItem, path, errCount := findBoth(t, m, http.MethodPost, "https://things.com/books/books")
+	assert.Zero(t, errCount)
+	assert.Equal(t, "postBook", pathItem.Post.OperationId)
+	assert.Equal(t, "/books/{isbn}", path)
+
+	// a request that only one template matches is not ambiguous
+	pathItem, _, errCount = findBoth(t, m, http.MethodGet, "https://things.com/books/1")
+	assert.Zero(t, errCount)
+	assert.Equal(t, "getBook", pathItem.Get.OperationId)
+}
+
+func TestNewCandidate_Specificity(t *testing.T) {
+	c := newCandidate(1, "/users/{id}.json/me", nil, serverTarget{}, splitPath("/users/{id}.json/me"))
+	assert.Equal(t, 2, c.literalSegments)
+	assert.Equal(t, len("users")+len(".json")+len("me"), c.literalChars)
+
+	c = newCandidate(1, "/{a}/{b:[0-9]+}", nil, serverTarget{}, splitPath("/{a}/{b:[0-9]+}"))
+	assert.Zero(t, c.literalSegments)
+	assert.Zero(t, c.literalChars)
+}
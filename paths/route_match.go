@@ -0,0 +1,163 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// RouteMatch is the result of matching a request against the paths in a document. It carries everything a router
+// needs to dispatch the request, so the URL does not have to be parsed again.
+type RouteMatch struct {
+	PathItem      *v3.PathItem
+	Operation     *v3.Operation     // nil when the path item has no operation for the request method
+	Template      string            // The path template, as it appears in the document
+	Server        *v3.Server        // The server the request was matched through, nil when no servers apply
+	RawPathParams map[string]string // Path variables as they were sent, keyed by name
+	PathParams    map[string]any    // Path parameters, decoded and converted to the type of their schema
+	Ambiguous     []string          // Other templates that match the request, and are just as specific as this one
+}
+
+// MatchRoute matches the request against the paths in the document, in the same way as FindPathWithOptions, and
+// extracts the values of the path parameters of the operation. Values are decoded according to the style of the
+// parameter, and converted to the type of its schema (integers to int64, numbers to float64, booleans to bool,
+// arrays to []any and objects to map[string]any). A value that cannot be converted is left as a decoded string, the
+// parameter validator reports it.
+//
+// The match is nil when no path matches the request, it is returned alongside any errors when the path matches,
+// but the operation does not exist, or the match is ambiguous (unless config.WithoutAmbiguousPathErrors is set).
+func MatchRoute(request *http.Request, document *v3.Document, options *config.ValidationOptions) (*RouteMatch, []*errors.ValidationError) {
+	if options == nil {
+		options = config.NewValidationOptions()
+	}
+
+	var route *Route
+	switch router := options.PathRouter.(type) {
+	case *Router:
+		route = router.Lookup(request)
+	case nil:
+		linear := &Router{
+			document:   document,
+			regexCache: options.RegexCache,
+			strict:     options.StrictServerMatching,
+			servers:    newServerIndex(document, options.RegexCache),
+		}
+		route = linear.lookupLinear(request)
+	default:
+		if match := router.FindPathItem(request); match.PathItem != nil {
+			stripped := StripRequestPathForPathItem(request, document, match.PathItem, options.RegexCache)
+			route = (&Router{}).newRoute(request, match.PathItem, match.PathValue, match.HasOperation, match.Ambiguous,
+				splitPath(stripped), nil)
+		}
+	}
+
+	if route == nil {
+		_, errs, _ := pathResult(request, nil, "", false, nil)
+		return nil, errs
+	}
+	_, errs, _ := pathResult(request, route.PathItem, route.Template, route.Operation != nil,
+		reportAmbiguous(options, route.Ambiguous))
+	return newRouteMatch(request, route), errs
+}
+
+func newRouteMatch(request *http.Request, route *Route) *RouteMatch {
+	match := &RouteMatch{
+		PathItem:      route.PathItem,
+		Operation:     route.Operation,
+		Template:      route.Template,
+		Server:        route.Server,
+		RawPathParams: route.Variables,
+		PathParams:    make(map[string]any, len(route.Variables)),
+		Ambiguous:     route.Ambiguous,
+	}
+	for _, p := range helpers.ExtractParamsForOperation(request, route.PathItem) {
+		if p == nil || p.In != helpers.Path {
+			continue
+		}
+		if raw, ok := route.Variables[p.Name]; ok {
+			match.PathParams[p.Name] = decodePathParam(p, raw)
+		}
+	}
+	// variables without a parameter definition are still decoded.
+	for name, raw := range route.Variables {
+		if _, ok := match.PathParams[name]; !ok {
+			match.PathParams[name] = pathUnescape(raw)
+		}
+	}
+	return match
+}
+
+// decodePathParam decodes the raw value of a path parameter, according to its style and schema.
+func decodePathParam(p *v3.Parameter, raw string) any {
+	var sch *base.Schema
+	if p.Schema != nil {
+		sch = p.Schema.Schema()
+	}
+	explode := p.Explode != nil && *p.Explode
+	typ := helpers.SchemaPrimaryType(sch)
+
+	value := raw
+	switch p.Style {
+	case helpers.LabelStyle:
+		value = strings.TrimPrefix(value, helpers.Period)
+	case helpers.MatrixStyle:
+		value = strings.TrimPrefix(value, helpers.SemiColon)
+		if !(explode && typ == helpers.Object) {
+			value = strings.TrimPrefix(value, p.Name+helpers.Equals)
+		}
+	}
+
+	switch typ {
+	case helpers.Array:
+		separator := helpers.Comma
+		if explode {
+			switch p.Style {
+			case helpers.LabelStyle:
+				separator = helpers.Period
+			case helpers.MatrixStyle:
+				separator = helpers.SemiColon + p.Name + helpers.Equals
+			}
+		}
+		var items *base.Schema
+		if sch.Items != nil && sch.Items.IsA() {
+			items = sch.Items.A.Schema()
+		}
+		var values []any
+		for _, item := range strings.Split(value, separator) {
+			values = append(values, helpers.CastValueForSchema(pathUnescape(item), items))
+		}
+		return values
+	case helpers.Object:
+		decoded := pathUnescape(value)
+		if !explode {
+			return helpers.ConstructMapFromCSV(decoded)
+		}
+		switch p.Style {
+		case helpers.LabelStyle:
+			return helpers.ConstructKVFromLabelEncoding(decoded)
+		case helpers.MatrixStyle:
+			return helpers.ConstructKVFromMatrixCSV(decoded)
+		default:
+			return helpers.ConstructKVFromCSV(decoded)
+		}
+	}
+	return helpers.CastValueForSchema(pathUnescape(value), sch)
+}
+
+func pathUnescape(value string) string {
+	if decoded, err := url.PathUnescape(value); err == nil {
+		return decoded
+	}
+	return value
+}
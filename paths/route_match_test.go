@@ -0,0 +1,180 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+const routeMatchSpec = `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+  /prices/{price}/{vegan}:
+    get:
+      operationId: getPrice
+      parameters:
+        - name: price
+          in: path
+          schema:
+            type: number
+        - name: vegan
+          in: path
+          schema:
+            type: boolean
+  /sauces/{names}/{colors}/{sizes}:
+    get:
+      operationId: getSauces
+      parameters:
+        - name: names
+          in: path
+          schema:
+            type: array
+            items:
+              type: string
+        - name: colors
+          in: path
+          style: label
+          explode: true
+          schema:
+            type: array
+            items:
+              type: string
+        - name: sizes
+          in: path
+          style: matrix
+          schema:
+            type: array
+            items:
+              type: integer
+  /fries/{fry}/{dip}:
+    get:
+      operationId: getFries
+      parameters:
+        - name: fry
+          in: path
+          explode: true
+          schema:
+            type: object
+        - name: dip
+          in: path
+          style: matrix
+          explode: true
+          schema:
+            type: object
+  /drinks/{drink}:
+    get:
+      operationId: getDrink
+`
+
+// matchAll runs MatchRoute with each kind of lookup, and checks they agree.
+func matchAll(t *testing.T, m *v3.Document, request *http.Request) *RouteMatch {
+	t.Helper()
+	match, errs := MatchRoute(request, m, nil)
+	for _, options := range []*config.ValidationOptions{
+		config.NewValidationOptions(config.WithPathRouter(NewRouter(m))),
+		config.NewValidationOptions(config.WithPathRouter(stubRouter{NewRouter(m)})),
+	} {
+		other, otherErrs := MatchRoute(request, m, options)
+		assert.Len(t, otherErrs, len(errs))
+		if match == nil {
+			assert.Nil(t, other)
+			continue
+		}
+		require.NotNil(t, other)
+		assert.Equal(t, match.Template, other.Template)
+		assert.Equal(t, match.PathParams, other.PathParams)
+		assert.Equal(t, match.RawPathParams, other.RawPathParams)
+	}
+	return match
+}
+
+// stubRouter hides the Router type, so MatchRoute treats it as any other config.PathRouter.
+type stubRouter struct {
+	router *Router
+}
+
+func (s stubRouter) FindPathItem(request *http.Request) config.PathMatch {
+	return s.router.FindPathItem(request)
+}
+
+func TestMatchRoute(t *testing.T) {
+	m := buildModel(t, []byte(routeMatchSpec))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/burgers/42", nil)
+	match := matchAll(t, m, request)
+	require.NotNil(t, match)
+	assert.Equal(t, "getBurger", match.Operation.OperationId)
+	assert.Same(t, m.Servers[0], match.Server)
+	assert.Equal(t, map[string]any{"burgerId": int64(42)}, match.PathParams)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/prices/1.5/true", nil)
+	match = matchAll(t, m, request)
+	require.NotNil(t, match)
+	assert.Equal(t, map[string]any{"price": 1.5, "vegan": true}, match.PathParams)
+
+	// values that do not fit the schema are left as strings, for the parameter validator to report
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/burgers/big%20mac", nil)
+	match = matchAll(t, m, request)
+	require.NotNil(t, match)
+	assert.Equal(t, map[string]any{"burgerId": "big mac"}, match.PathParams)
+	assert.Equal(t, map[string]string{"burgerId": "big%20mac"}, match.RawPathParams)
+
+	// variables without a parameter are decoded, but not typed
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/drinks/12", nil)
+	match = matchAll(t, m, request)
+	require.NotNil(t, match)
+	assert.Equal(t, map[string]any{"drink": "12"}, match.PathParams)
+}
+
+func TestMatchRoute_Styles(t *testing.T) {
+	m := buildModel(t, []byte(routeMatchSpec))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/sauces/ketchup,hot%2Cmayo/.red.green/;sizes=1,2", nil)
+	match := matchAll(t, m, request)
+	require.NotNil(t, match)
+	assert.Equal(t, []any{"ketchup", "hot,mayo"}, match.PathParams["names"])
+	assert.Equal(t, []any{"red", "green"}, match.PathParams["colors"])
+	assert.Equal(t, []any{int64(1), int64(2)}, match.PathParams["sizes"])
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/fries/salt=1,size=big/;sauce=mayo;hot=true", nil)
+	match = matchAll(t, m, request)
+	require.NotNil(t, match)
+	assert.Equal(t, map[string]any{"salt": int64(1), "size": "big"}, match.PathParams["fry"])
+	assert.Equal(t, map[string]any{"sauce": "mayo", "hot": true}, match.PathParams["dip"])
+}
+
+func TestMatchRoute_Errors(t *testing.T) {
+	m := buildModel(t, []byte(routeMatchSpec))
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/api/burgers/42", nil)
+	match, errs := MatchRoute(request, m, nil)
+	require.NotNil(t, match)
+	assert.Nil(t, match.Operation)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].IsOperationMissingError())
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/pizza", nil)
+	match, errs = MatchRoute(request, m, nil)
+	assert.Nil(t, match)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].IsPathMissingError())
+}
@@ -0,0 +1,346 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// Route is a path in the document, as located by a Router.
+type Route struct {
+	PathItem  *v3.PathItem
+	Operation *v3.Operation     // nil when the path item has no operation for the request method, reachable through the server
+	Template  string            // The path template, as it appears in the document
+	Variables map[string]string // Path variables extracted from the request (as sent, not decoded), keyed by name
+	Ambiguous []string          // Other templates that match the request, and are just as specific as this one
+	Server    *v3.Server        // The server the request was matched through, nil when no servers apply
+}
+
+// Router is a compiled routing index for the paths in a document. Paths are stored in a trie keyed by path
+// segment, so a lookup only visits the branches that can match the request, rather than every path in the
+// document. It returns the same results as FindPath, and is safe for concurrent use once built.
+//
+// Each group of servers in the document (see config.WithStrictServerMatching) has its own trie, paths are stored in
+// the trie of every group of servers they can be reached through.
+type Router struct {
+	document   *v3.Document
+	regexCache config.RegexCache
+	strict     bool
+	ambiguous  bool // ambiguous matches are reported as errors by FindPath
+	servers    *serverIndex
+	groups     []*routeGroup            // indexed in the same way as the groups of servers
+	segments   map[string]*paramSegment // compiled templated segments, keyed by the segment template
+	irregular  bool                     // the document has empty or dot segments, which only the linear scan handles
+}
+
+type routeGroup struct {
+	plain     *routeNode // paths with any fragment removed
+	fragments *routeNode // paths as they appear in the document, used when the request has a fragment
+}
+
+type routeNode struct {
+	literals map[string]*routeNode
+	params   []*paramEdge
+	routes   []*candidate
+}
+
+type paramEdge struct {
+	segment *paramSegment
+	node    *routeNode
+}
+
+type paramSegment struct {
+	regex *regexp.Regexp
+	names []string
+}
+
+// NewRouter builds a Router for the paths in the supplied document. Only the RegexCache, StrictServerMatching and
+// AmbiguousPathErrors options are used.
+func NewRouter(document *v3.Document, opts ...config.Option) *Router {
+	options := config.NewValidationOptions(opts...)
+	r := &Router{
+		document:   document,
+		regexCache: options.RegexCache,
+		strict:     options.StrictServerMatching,
+		ambiguous:  options.AmbiguousPathErrors,
+		servers:    newServerIndex(document, options.RegexCache),
+		segments:   make(map[string]*paramSegment),
+	}
+	if document == nil || document.Paths == nil {
+		r.servers.seal()
+		return r
+	}
+
+	index := 0
+	for pair := orderedmap.First(document.Paths.PathItems); pair != nil; pair = pair.Next() {
+		index++
+		pathItem := pair.Value()
+		var operations []*v3.Operation
+		if pathItem != nil {
+			for op := range helpers.ExtractOperations(pathItem).ValuesFromOldest() {
+				operations = append(operations, op)
+			}
+		}
+		for _, target := range r.servers.targets(pathItem, operations) {
+			group := r.group(target.group)
+			path := pair.Key()
+			r.insert(group.fragments, index, path, pathItem, target)
+			if before, _, found := strings.Cut(path, "#"); found {
+				path = before
+			}
+			r.insert(group.plain, index, path, pathItem, target)
+		}
+	}
+	r.servers.seal()
+	return r
+}
+
+func (r *Router) group(id int) *routeGroup {
+	for len(r.groups) <= id {
+		r.groups = append(r.groups, &routeGroup{plain: &routeNode{}, fragments: &routeNode{}})
+	}
+	return r.groups[id]
+}
+
+func (r *Router) insert(root *routeNode, index int, template string, pathItem *v3.PathItem, target serverTarget) {
+	segs := splitPath(template)
+	for i, seg := range segs {
+		if (seg == "" && i < len(segs)-1) || seg == "." || seg == ".." {
+			r.irregular = true
+		}
+	}
+
+	// compile every templated segment first, a template that does not compile can never match.
+	for _, seg := range segs {
+		if isTemplatedSegment(seg) && r.compileSegment(seg) == nil {
+			return
+		}
+	}
+
+	node := root
+	for _, seg := range segs {
+		if !isTemplatedSegment(seg) {
+			if node.literals == nil {
+				node.literals = make(map[string]*routeNode)
+			}
+			child, ok := node.literals[seg]
+			if !ok {
+				child = &routeNode{}
+				node.literals[seg] = child
+			}
+			node = child
+			continue
+		}
+		var edge *paramEdge
+		segment := r.segments[seg]
+		for _, p := range node.params {
+			if p.segment == segment {
+				edge = p
+				break
+			}
+		}
+		if edge == nil {
+			edge = &paramEdge{segment: segment, node: &routeNode{}}
+			node.params = append(node.params, edge)
+		}
+		node = edge.node
+	}
+	node.routes = append(node.routes, newCandidate(index, template, pathItem, target, segs))
+}
+
+// compileSegment compiles (once) the regular expression for a templated segment, nil means it cannot be compiled.
+func (r *Router) compileSegment(seg string) *paramSegment {
+	if segment, ok := r.segments[seg]; ok {
+		return segment
+	}
+	rgx, err := helpers.GetRegexForPath(seg)
+	if err != nil {
+		r.segments[seg] = nil
+		return nil
+	}
+	segment := &paramSegment{regex: rgx, names: segmentNames(seg)}
+	r.segments[seg] = segment
+	return segment
+}
+
+// Lookup locates the Route that matches the request. When several paths match, the most specific one that defines
+// an operation for the request method wins (see FindPath); if none do, the most specific path is returned, with a
+// nil Operation. Lookup returns nil when no path matches at all.
+func (r *Router) Lookup(request *http.Request) *Route {
+	if r.irregular {
+		return r.lookupLinear(request)
+	}
+
+	type groupPath struct {
+		server   *v3.Server
+		segments []string
+	}
+	resolved := make([]*groupPath, len(r.groups))
+
+	var scratch [8]*candidate
+	matched := scratch[:0]
+	for i, group := range r.groups {
+		stripped, server, ok := r.servers.groups[i].requestPath(request, r.strict)
+		if !ok {
+			continue
+		}
+		segs := splitPath(stripped)
+		if hasDotSegments(segs) {
+			return r.lookupLinear(request)
+		}
+		resolved[i] = &groupPath{server: server, segments: segs}
+
+		root := group.plain
+		if strings.Contains(stripped, "#") {
+			root = group.fragments
+		}
+		matched = root.match(segs, matched)
+	}
+
+	selected, hasOperation, ambiguous := selectCandidate(request, matched)
+	if selected == nil {
+		return nil
+	}
+	gp := resolved[selected.group]
+	return r.newRoute(request, selected.pathItem, selected.template, hasOperation, ambiguous, gp.segments, gp.server)
+}
+
+// lookupLinear falls back to scanning every path in the document.
+func (r *Router) lookupLinear(request *http.Request) *Route {
+	m := findPathItem(request, r.document, r.servers, r.regexCache, r.strict)
+	if m.candidate == nil {
+		return nil
+	}
+	return r.newRoute(request, m.candidate.pathItem, m.candidate.template, m.hasOperation, m.ambiguous, m.segments, m.server)
+}
+
+// FindPathItem implements config.PathRouter.
+func (r *Router) FindPathItem(request *http.Request) config.PathMatch {
+	route := r.Lookup(request)
+	if route == nil {
+		return config.PathMatch{}
+	}
+	return config.PathMatch{
+		PathItem:     route.PathItem,
+		PathValue:    route.Template,
+		HasOperation: route.Operation != nil,
+		Ambiguous:    route.Ambiguous,
+	}
+}
+
+// FindPath works in the same way as the package level FindPath, using the routing index.
+func (r *Router) FindPath(request *http.Request) (*v3.PathItem, []*errors.ValidationError, string) {
+	match := r.FindPathItem(request)
+	var ambiguous []string
+	if r.ambiguous {
+		ambiguous = match.Ambiguous
+	}
+	return pathResult(request, match.PathItem, match.PathValue, match.HasOperation, ambiguous)
+}
+
+func (n *routeNode) match(segs []string, matched []*candidate) []*candidate {
+	if len(segs) == 0 {
+		return append(matched, n.routes...)
+	}
+	if child, ok := n.literals[segs[0]]; ok {
+		matched = child.match(segs[1:], matched)
+	}
+	for _, p := range n.params {
+		if p.segment.regex.MatchString(segs[0]) {
+			matched = p.node.match(segs[1:], matched)
+		}
+	}
+	return matched
+}
+
+func (r *Router) newRoute(request *http.Request, pathItem *v3.PathItem, template string, hasOperation bool,
+	ambiguous []string, segs []string, server *v3.Server,
+) *Route {
+	var operation *v3.Operation
+	if hasOperation {
+		operation = helpers.ExtractOperation(request, pathItem)
+	}
+	route := &Route{
+		PathItem:  pathItem,
+		Operation: operation,
+		Template:  template,
+		Variables: make(map[string]string),
+		Ambiguous: ambiguous,
+		Server:    server,
+	}
+	templateSegs := splitPath(template)
+	if len(templateSegs) != len(segs) {
+		return route
+	}
+	for i, seg := range templateSegs {
+		if !isTemplatedSegment(seg) {
+			continue
+		}
+		segment, ok := r.segments[seg]
+		if !ok {
+			// the router was not built with this template, compile it without caching, the router may be shared.
+			if rgx, err := helpers.GetRegexForPath(seg); err == nil {
+				segment = &paramSegment{regex: rgx, names: segmentNames(seg)}
+			}
+		}
+		if segment == nil {
+			continue
+		}
+		values := segment.regex.FindStringSubmatch(segs[i])
+		for j, name := range segment.names {
+			if j+1 < len(values) {
+				route.Variables[name] = values[j+1]
+			}
+		}
+	}
+	return route
+}
+
+func splitPath(path string) []string {
+	segs := strings.Split(path, "/")
+	if segs[0] == "" {
+		segs = segs[1:]
+	}
+	return segs
+}
+
+func isTemplatedSegment(seg string) bool {
+	return strings.ContainsAny(seg, "{}")
+}
+
+func hasDotSegments(segs []string) bool {
+	for _, seg := range segs {
+		if seg == "." || seg == ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentNames returns the names of the path variables in a templated segment, in order.
+func segmentNames(seg string) []string {
+	idxs, err := helpers.BraceIndices(seg)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(idxs)/2)
+	for i := 0; i < len(idxs); i += 2 {
+		name, _, _ := strings.Cut(seg[idxs[i]+1:idxs[i+1]-1], ":")
+		// label and matrix prefixes, and the explode modifier, are not part of the name.
+		name = strings.TrimSuffix(name, helpers.Asterisk)
+		name = strings.TrimPrefix(strings.TrimPrefix(name, helpers.Period), helpers.SemiColon)
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,283 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+func buildModel(t testing.TB, spec []byte) *v3.Document {
+	doc, err := libopenapi.NewDocument(spec)
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	return &m.Model
+}
+
+// assertSameAsFindPath checks the router gives exactly the same answer as the linear scan.
+func assertSameAsFindPath(t *testing.T, m *v3.Document, method, url string) {
+	t.Helper()
+	request, _ := http.NewRequest(method, url, nil)
+
+	expectedItem, expectedErrs, expectedPath := FindPath(request, m, nil)
+	item, errs, path := NewRouter(m).FindPath(request)
+
+	assert.Same(t, expectedItem, item, "%s %s", method, url)
+	assert.Equal(t, expectedPath, path, "%s %s", method, url)
+	require.Len(t, errs, len(expectedErrs), "%s %s", method, url)
+	for i := range errs {
+		assert.Equal(t, expectedErrs[i].ValidationSubType, errs[i].ValidationSubType)
+		assert.Equal(t, expectedErrs[i].Message, errs[i].Message)
+		assert.Equal(t, expectedErrs[i].SpecPath, errs[i].SpecPath)
+	}
+}
+
+func TestRouter_SameAsFindPath_Petstore(t *testing.T) {
+	b, _ := os.ReadFile("../test_specs/petstorev3.json")
+	m := buildModel(t, b)
+
+	for _, tc := range []struct{ method, url string }{
+		{http.MethodGet, "https://things.com/pet/doggy"},
+		{http.MethodGet, "https://things.com/pet/12334"},
+		{http.MethodPost, "https://things.com/pet"},
+		{http.MethodPut, "https://things.com/pet"},
+		{http.MethodDelete, "https://things.com/pet"},
+		{http.MethodGet, "https://things.com/pet/findByStatus"},
+		{http.MethodGet, "https://things.com/pet/findByTags"},
+		{http.MethodPost, "https://things.com/pet/1/uploadImage"},
+		{http.MethodGet, "https://things.com/store/inventory"},
+		{http.MethodGet, "https://things.com/store/order/1"},
+		{http.MethodDelete, "https://things.com/store/order/1"},
+		{http.MethodGet, "https://things.com/user/login"},
+		{http.MethodGet, "https://things.com/user/bob"},
+		{http.MethodGet, "https://things.com/api/v3/pet/1"},
+		{http.MethodGet, "https://things.com/nope"},
+		{http.MethodGet, "https://things.com/pet/1/2/3"},
+		{http.MethodGet, "https://things.com/"},
+		{"CONNECT", "https://things.com/pet/1"},
+	} {
+		assertSameAsFindPath(t, m, tc.method, tc.url)
+	}
+}
+
+func TestRouter_SameAsFindPath_Overlapping(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /:
+    get:
+      operationId: root
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+  /burgers/latest:
+    get:
+      operationId: latestBurger
+    post:
+      operationId: createLatest
+  /burgers/{burgerId}/locate:
+    patch:
+      operationId: locateBurger
+  /burgers/{burgerId}.json:
+    put:
+      operationId: burgerJson
+  /burgers/:
+    get:
+      operationId: trailing
+  /hashy#one:
+    post:
+      operationId: one
+  /hashy#two:
+    post:
+      operationId: two
+  /entities('{Entity}'):
+    get:
+      operationId: entity
+  /orders/{id:[0-9]+}:
+    get:
+      operationId: order
+  /broken/{id:
+    get:
+      operationId: broken
+`
+	m := buildModel(t, []byte(spec))
+
+	for _, tc := range []struct{ method, url string }{
+		{http.MethodGet, "https://things.com/api/"},
+		{http.MethodGet, "https://things.com/api"},
+		{http.MethodGet, "https://things.com/api/burgers/latest"},
+		{http.MethodPost, "https://things.com/api/burgers/latest"},
+		{http.MethodPost, "https://things.com/api/burgers/1"},
+		{http.MethodDelete, "https://things.com/api/burgers/latest"},
+		{http.MethodPatch, "https://things.com/api/burgers/1/locate"},
+		{http.MethodPut, "https://things.com/api/burgers/1.json"},
+		{http.MethodGet, "https://things.com/api/burgers/"},
+		{http.MethodGet, "https://things.com/api/burgers//locate"},
+		{http.MethodPost, "https://things.com/api/hashy#one"},
+		{http.MethodPost, "https://things.com/api/hashy#two"},
+		{http.MethodPost, "https://things.com/api/hashy"},
+		{http.MethodGet, "https://things.com/api/entities('1')"},
+		{http.MethodGet, "https://things.com/api/orders/12"},
+		{http.MethodGet, "https://things.com/api/orders/twelve"},
+		{http.MethodGet, "https://things.com/api/broken/1"},
+		{http.MethodGet, "https://things.com/api/burgers/../burgers/1"},
+		{http.MethodGet, "https://things.com/api/burgers/pkg%3Agithub%2Frs"},
+	} {
+		assertSameAsFindPath(t, m, tc.method, tc.url)
+	}
+}
+
+func TestRouter_SameAsFindPath_Irregular(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers//{burgerId}:
+    get:
+      operationId: doubleSlash
+  /./cakes:
+    get:
+      operationId: dotCakes
+`
+	m := buildModel(t, []byte(spec))
+	assert.True(t, NewRouter(m).irregular)
+
+	assertSameAsFindPath(t, m, http.MethodGet, "https://things.com/burgers//1")
+	assertSameAsFindPath(t, m, http.MethodGet, "https://things.com/burgers/1")
+	assertSameAsFindPath(t, m, http.MethodGet, "https://things.com/cakes")
+	assertSameAsFindPath(t, m, http.MethodGet, "https://things.com/./cakes")
+}
+
+func TestRouter_Lookup(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/dressings/{dressingId}:
+    get:
+      operationId: getDressing
+  /orders/{id:[0-9]+}-{version}:
+    delete:
+      operationId: deleteOrder
+`
+	m := buildModel(t, []byte(spec))
+	router := NewRouter(m)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/big%20mac/dressings/ketchup", nil)
+	route := router.Lookup(request)
+	require.NotNil(t, route)
+	assert.Equal(t, "/burgers/{burgerId}/dressings/{dressingId}", route.Template)
+	assert.Equal(t, "getDressing", route.Operation.OperationId)
+	assert.Equal(t, map[string]string{"burgerId": "big%20mac", "dressingId": "ketchup"}, route.Variables)
+
+	request, _ = http.NewRequest(http.MethodDelete, "https://things.com/orders/12-beta", nil)
+	route = router.Lookup(request)
+	require.NotNil(t, route)
+	assert.Equal(t, map[string]string{"id": "12", "version": "beta"}, route.Variables)
+
+	// path matches, but there is no operation
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/orders/12-beta", nil)
+	route = router.Lookup(request)
+	require.NotNil(t, route)
+	assert.Nil(t, route.Operation)
+	match := router.FindPathItem(request)
+	assert.NotNil(t, match.PathItem)
+	assert.Equal(t, "/orders/{id:[0-9]+}-{version}", match.PathValue)
+	assert.False(t, match.HasOperation)
+
+	// no path matches
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/orders/twelve-beta", nil)
+	assert.Nil(t, router.Lookup(request))
+	match = router.FindPathItem(request)
+	assert.Nil(t, match.PathItem)
+	assert.Empty(t, match.PathValue)
+	assert.False(t, match.HasOperation)
+}
+
+func TestRouter_NilDocument(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+
+	assert.Nil(t, NewRouter(nil).Lookup(request))
+	assert.Nil(t, NewRouter(&v3.Document{}).Lookup(request))
+}
+
+func TestFindPathWithOptions(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      operationId: getBurger
+`
+	m := buildModel(t, []byte(spec))
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/1", nil)
+
+	for _, options := range []*config.ValidationOptions{
+		nil,
+		config.NewValidationOptions(config.WithRegexCache(&sync.Map{})),
+		config.NewValidationOptions(config.WithPathRouter(NewRouter(m))),
+	} {
+		pathItem, errs, path := FindPathWithOptions(request, m, options)
+		assert.Empty(t, errs)
+		assert.Equal(t, "getBurger", pathItem.Get.OperationId)
+		assert.Equal(t, "/burgers/{burgerId}", path)
+	}
+
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/1", nil)
+	pathItem, errs, path := FindPathWithOptions(request, m, config.NewValidationOptions(config.WithPathRouter(NewRouter(m))))
+	assert.NotNil(t, pathItem)
+	assert.Equal(t, "/burgers/{burgerId}", path)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].IsOperationMissingError())
+}
+
+// generateSpec builds a spec with n paths, spread over resources that each have a collection, an item and a
+// nested collection, which is roughly the shape of a large real-world API.
+func generateSpec(n int) []byte {
+	var sb strings.Builder
+	sb.WriteString("openapi: 3.1.0\npaths:\n")
+	for i := 0; i < n/3; i++ {
+		fmt.Fprintf(&sb, "  /resource%d:\n    get:\n      operationId: list%d\n", i, i)
+		fmt.Fprintf(&sb, "  /resource%d/{id}:\n    get:\n      operationId: get%d\n", i, i)
+		fmt.Fprintf(&sb, "  /resource%d/{id}/children/{childId}:\n    get:\n      operationId: child%d\n", i, i)
+	}
+	return []byte(sb.String())
+}
+
+func benchmarkFindPath(b *testing.B, paths int, router bool) {
+	m := buildModel(b, generateSpec(paths))
+	// the last resource in the document is the worst case for the linear scan.
+	request, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("https://things.com/resource%d/1/children/2", paths/3-1), nil)
+	regexCache := &sync.Map{}
+	r := NewRouter(m)
+
+	b.ResetTimer()
+	for b.Loop() {
+		var pathItem *v3.PathItem
+		if router {
+			pathItem, _, _ = r.FindPath(request)
+		} else {
+			pathItem, _, _ = FindPath(request, m, regexCache)
+		}
+		if pathItem == nil {
+			b.Fatal("path not found")
+		}
+	}
+}
+
+func BenchmarkFindPath_Linear_30(b *testing.B)   { benchmarkFindPath(b, 30, false) }
+func BenchmarkFindPath_Linear_300(b *testing.B)  { benchmarkFindPath(b, 300, false) }
+func BenchmarkFindPath_Linear_1400(b *testing.B) { benchmarkFindPath(b, 1400, false) }
+func BenchmarkFindPath_Router_30(b *testing.B)   { benchmarkFindPath(b, 30, true) }
+func BenchmarkFindPath_Router_300(b *testing.B)  { benchmarkFindPath(b, 300, true) }
+func BenchmarkFindPath_Router_1400(b *testing.B) { benchmarkFindPath(b, 1400, true) }
@@ -0,0 +1,351 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// serverMatcher matches requests against a single server URL, with its variables expanded to their enum values
+// (or their default, when there is no enum).
+type serverMatcher struct {
+	server *v3.Server
+	scheme *regexp.Regexp // nil when the server URL has no scheme
+	host   *regexp.Regexp // nil when the server URL is relative
+	port   bool           // the server URL has an explicit port
+	base   *regexp.Regexp // matches the base path at the start of a request path, nil when there is none
+}
+
+// serverIndex assigns every path item and operation in a document to the group of servers that applies to it.
+// Group 0 is always the servers of the document. Groups are compiled the first time their servers are seen, until
+// the index is sealed: a sealed index is only read, so it can be shared by concurrent requests.
+type serverIndex struct {
+	document   *v3.Document
+	regexCache config.RegexCache
+	groups     []*serverGroup
+	ids        map[string]int // keyed by the servers of each list (see serverKey)
+	sealed     bool
+}
+
+// serverTarget is a group of servers a path item can be reached through. The operation is set when the group
+// belongs to an operation that overrides the servers of its path item.
+type serverTarget struct {
+	group     int
+	operation *v3.Operation
+}
+
+func newServerIndex(document *v3.Document, regexCache config.RegexCache) *serverIndex {
+	x := &serverIndex{document: document, regexCache: regexCache, ids: make(map[string]int)}
+	var servers []*v3.Server
+	if document != nil {
+		servers = document.Servers
+	}
+	x.groups = []*serverGroup{newServerGroup(servers, regexCache)}
+	if len(servers) > 0 {
+		x.ids[serverKey(servers)] = 0
+	}
+	return x
+}
+
+// serverKey identifies a list of servers by every server in it, in order. The low-level model of a server is used
+// when there is one, as the high-level model of an operation can be built more than once from the same low-level
+// model.
+func serverKey(servers []*v3.Server) string {
+	var key strings.Builder
+	for _, server := range servers {
+		var id any = server
+		if server != nil && server.GoLow() != nil {
+			id = server.GoLow()
+		}
+		_, _ = fmt.Fprintf(&key, "%p,", id)
+	}
+	return key.String()
+}
+
+// seal stops the index from compiling new groups, it is only read from then on.
+func (x *serverIndex) seal() {
+	x.sealed = true
+}
+
+// group returns the group of a list of servers, and false when a sealed index has not seen the list.
+func (x *serverIndex) group(servers []*v3.Server) (int, bool) {
+	if len(servers) == 0 {
+		return 0, true
+	}
+	key := serverKey(servers)
+	if id, ok := x.ids[key]; ok {
+		return id, true
+	}
+	if x.sealed {
+		return 0, false
+	}
+	x.groups = append(x.groups, newServerGroup(servers, x.regexCache))
+	x.ids[key] = len(x.groups) - 1
+	return len(x.groups) - 1, true
+}
+
+// targets returns the groups of servers the path item can be reached through: those of the path item (or the
+// document), plus those of any of the supplied operations that override them. Servers that a sealed index has not
+// seen are not compiled: the path item falls back to the servers of the document, the operations are skipped.
+func (x *serverIndex) targets(pathItem *v3.PathItem, operations []*v3.Operation) []serverTarget {
+	group, _ := x.group(effectiveServers(x.document, pathItem, nil))
+	targets := []serverTarget{{group: group}}
+	for _, op := range operations {
+		if op != nil && len(op.Servers) > 0 {
+			if group, ok := x.group(op.Servers); ok {
+				targets = append(targets, serverTarget{group: group, operation: op})
+			}
+		}
+	}
+	return targets
+}
+
+// stripRequestPath strips the base path from the request path in the same way as StripRequestPathForPathItem,
+// with the groups of servers of the index.
+func (x *serverIndex) stripRequestPath(request *http.Request, pathItem *v3.PathItem) string {
+	var operation *v3.Operation
+	if pathItem != nil {
+		operation = helpers.ExtractOperation(request, pathItem)
+	}
+	servers := effectiveServers(x.document, pathItem, operation)
+	var g *serverGroup
+	if group, ok := x.group(servers); ok {
+		g = x.groups[group]
+	} else {
+		g = newServerGroup(servers, x.regexCache)
+	}
+	stripped, _, _ := g.requestPath(request, false)
+	return stripped
+}
+
+// serverGroup is the list of servers that applies to a set of operations: the operation's own servers, those of
+// its path item, or those of the document, in that order of preference.
+type serverGroup struct {
+	servers []*serverMatcher
+}
+
+func newServerGroup(servers []*v3.Server, regexCache config.RegexCache) *serverGroup {
+	g := &serverGroup{}
+	for _, s := range servers {
+		if s != nil {
+			g.servers = append(g.servers, compileServer(s, regexCache))
+		}
+	}
+	return g
+}
+
+func compileServer(s *v3.Server, regexCache config.RegexCache) *serverMatcher {
+	m := &serverMatcher{server: s}
+	rest := s.URL
+	if scheme, after, found := strings.Cut(rest, "://"); found && !strings.Contains(scheme, "/") {
+		m.scheme = compileServerTemplate("^", scheme, "$", true, s.Variables, regexCache)
+		rest = "//" + after
+	}
+	var path string
+	if strings.HasPrefix(rest, "//") {
+		host, p, _ := strings.Cut(rest[2:], "/")
+		path = "/" + p
+		_, port, err := net.SplitHostPort(host)
+		m.port = err == nil && port != ""
+		m.host = compileServerTemplate("^", host, "$", true, s.Variables, regexCache)
+	} else {
+		path = rest
+	}
+	path = strings.TrimRight(path, "/")
+	if path != "" {
+		if !strings.HasPrefix(path, "/") {
+			path = "/" + path
+		}
+		m.base = compileServerTemplate("^", path, "", false, s.Variables, regexCache)
+	}
+	return m
+}
+
+// compileServerTemplate turns part of a server URL into a regular expression, replacing each variable with the
+// values it can take. Schemes and hosts are case-insensitive, paths are not. Compiled expressions are shared through
+// the regex cache, when there is one.
+func compileServerTemplate(prefix, tpl, suffix string, caseInsensitive bool,
+	variables *orderedmap.Map[string, *v3.ServerVariable], regexCache config.RegexCache,
+) *regexp.Regexp {
+	var pattern strings.Builder
+	if caseInsensitive {
+		pattern.WriteString("(?i)")
+	}
+	pattern.WriteString(prefix)
+	idxs, err := helpers.BraceIndices(tpl)
+	if err != nil {
+		idxs = nil
+	}
+	end := 0
+	for i := 0; i < len(idxs); i += 2 {
+		pattern.WriteString(regexp.QuoteMeta(tpl[end:idxs[i]]))
+		end = idxs[i+1]
+		pattern.WriteString(serverVariablePattern(tpl[idxs[i]+1:end-1], variables))
+	}
+	pattern.WriteString(regexp.QuoteMeta(tpl[end:]))
+	pattern.WriteString(suffix)
+
+	if regexCache != nil {
+		if cached, found := regexCache.Load(pattern.String()); found {
+			if rgx, ok := cached.(*regexp.Regexp); ok {
+				return rgx
+			}
+		}
+	}
+	rgx := regexp.MustCompile(pattern.String())
+	if regexCache != nil {
+		regexCache.Store(pattern.String(), rgx)
+	}
+	return rgx
+}
+
+func serverVariablePattern(name string, variables *orderedmap.Map[string, *v3.ServerVariable]) string {
+	var variable *v3.ServerVariable
+	if variables != nil {
+		variable = variables.GetOrZero(name)
+	}
+	if variable == nil {
+		return "[^/]*"
+	}
+	values := make([]string, 0, len(variable.Enum)+1)
+	for _, v := range variable.Enum {
+		values = append(values, regexp.QuoteMeta(v))
+	}
+	if variable.Default != "" {
+		values = append(values, regexp.QuoteMeta(variable.Default))
+	}
+	if len(values) == 0 {
+		return "[^/]*"
+	}
+	return "(?:" + strings.Join(values, "|") + ")"
+}
+
+// matchHost checks the scheme and host of the request against the server, relative servers match any host.
+func (m *serverMatcher) matchHost(request *http.Request) bool {
+	if m.scheme != nil && !m.scheme.MatchString(requestScheme(request)) {
+		return false
+	}
+	if m.host == nil {
+		return true
+	}
+	host := request.URL.Host
+	if host == "" {
+		host = request.Host
+	}
+	if !m.port {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+	}
+	return m.host.MatchString(host)
+}
+
+// stripBase removes the base path of the server from the start of the (escaped) request path. The base path must
+// end on a segment boundary.
+func (m *serverMatcher) stripBase(path string) (string, bool) {
+	loc := m.base.FindStringIndex(path)
+	if loc == nil {
+		return path, false
+	}
+	rest := path[loc[1]:]
+	if rest != "" && rest[0] != '/' {
+		return path, false
+	}
+	return rest, true
+}
+
+// strip removes the base path of the first server in the group that matches the request, servers with a base path
+// are preferred over those without one. When none match, the path is returned untouched, and no server, unless
+// strict is set, in which case ok is false. Strict also requires the scheme and host of the request to match.
+func (g *serverGroup) strip(request *http.Request, path string, strict bool) (stripped string, server *v3.Server, ok bool) {
+	if len(g.servers) == 0 {
+		return path, nil, true
+	}
+	for _, m := range g.servers {
+		if m.base == nil || (strict && !m.matchHost(request)) {
+			continue
+		}
+		if rest, found := m.stripBase(path); found {
+			return rest, m.server, true
+		}
+	}
+	for _, m := range g.servers {
+		if m.base == nil && (!strict || m.matchHost(request)) {
+			return path, m.server, true
+		}
+	}
+	return path, nil, !strict
+}
+
+// requestPath returns the escaped path of the request, without the base path of the first matching server in the
+// group, and with any fragment re-attached.
+func (g *serverGroup) requestPath(request *http.Request, strict bool) (string, *v3.Server, bool) {
+	stripped, server, ok := g.strip(request, request.URL.EscapedPath(), strict)
+	if request.URL.Fragment != "" {
+		stripped = stripped + "#" + request.URL.Fragment
+	}
+	if !strings.HasPrefix(stripped, "/") {
+		stripped = "/" + stripped
+	}
+	return stripped, server, ok
+}
+
+func requestScheme(request *http.Request) string {
+	if request.URL.Scheme != "" {
+		return request.URL.Scheme
+	}
+	if request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// effectiveServers returns the servers that apply to an operation.
+func effectiveServers(document *v3.Document, pathItem *v3.PathItem, operation *v3.Operation) []*v3.Server {
+	if operation != nil && len(operation.Servers) > 0 {
+		return operation.Servers
+	}
+	if pathItem != nil && len(pathItem.Servers) > 0 {
+		return pathItem.Servers
+	}
+	if document != nil {
+		return document.Servers
+	}
+	return nil
+}
+
+// StripRequestPathForPathItem strips the base path from the request path, based on the servers that apply to the
+// operation in the path item that handles the request: its own servers, then the path item's, then the document's.
+// The regex cache is optional.
+func StripRequestPathForPathItem(request *http.Request, document *v3.Document, pathItem *v3.PathItem, regexCache config.RegexCache) string {
+	var operation *v3.Operation
+	if pathItem != nil {
+		operation = helpers.ExtractOperation(request, pathItem)
+	}
+	stripped, _, _ := newServerGroup(effectiveServers(document, pathItem, operation), regexCache).requestPath(request, false)
+	return stripped
+}
+
+// StripRequestPathWithOptions works in the same way as StripRequestPathForPathItem, but uses the servers compiled
+// by the routing index set on the options (see config.WithPathRouter), when it was built for the document.
+func StripRequestPathWithOptions(request *http.Request, document *v3.Document, pathItem *v3.PathItem, options *config.ValidationOptions) string {
+	if options == nil {
+		return StripRequestPathForPathItem(request, document, pathItem, nil)
+	}
+	if router, ok := options.PathRouter.(*Router); ok && router.document == document {
+		return router.servers.stripRequestPath(request, pathItem)
+	}
+	return StripRequestPathForPathItem(request, document, pathItem, options.RegexCache)
+}
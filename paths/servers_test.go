@@ -0,0 +1,302 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package paths
+
+import (
+	"crypto/tls"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+)
+
+// findStrict runs a strict lookup through the linear scan and the router, checks they agree, and returns the route.
+func findStrict(t *testing.T, m *v3.Document, request *http.Request) (*Route, int) {
+	t.Helper()
+	options := config.NewValidationOptions(config.WithStrictServerMatching())
+	expectedItem, expectedErrs, expectedPath := FindPathWithOptions(request, m, options)
+
+	router := NewRouter(m, config.WithStrictServerMatching())
+	item, errs, path := router.FindPath(request)
+	assert.Same(t, expectedItem, item)
+	assert.Equal(t, expectedPath, path)
+	require.Len(t, errs, len(expectedErrs))
+
+	return router.Lookup(request), len(errs)
+}
+
+func TestFindPath_ServerVariables(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://{env}.things.com/api/{version}
+    variables:
+      env:
+        default: api
+        enum: [api, staging]
+      version:
+        default: v1
+        enum: [v1, v2]
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+`
+	m := buildModel(t, []byte(spec))
+
+	for _, url := range []string{
+		"https://api.things.com/api/v1/burgers",
+		"https://staging.things.com/api/v2/burgers",
+		"HTTPS://API.things.com/api/v2/burgers",
+	} {
+		assertSameAsFindPath(t, m, http.MethodGet, url)
+		request, _ := http.NewRequest(http.MethodGet, url, nil)
+		route, errs := findStrict(t, m, request)
+		assert.Zero(t, errs, url)
+		require.NotNil(t, route, url)
+		assert.Equal(t, "listBurgers", route.Operation.OperationId)
+		assert.Same(t, m.Servers[0], route.Server)
+	}
+
+	// the version is not one of the enum values, so the base path is not stripped.
+	assertSameAsFindPath(t, m, http.MethodGet, "https://api.things.com/api/v3/burgers")
+	request, _ := http.NewRequest(http.MethodGet, "https://api.things.com/api/v3/burgers", nil)
+	_, errs, _ := FindPath(request, m, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "missing", errs[0].ValidationSubType)
+
+	// base paths are case-sensitive, unlike schemes and hosts.
+	assertSameAsFindPath(t, m, http.MethodGet, "https://api.things.com/API/V2/burgers")
+	request, _ = http.NewRequest(http.MethodGet, "https://api.things.com/API/V2/burgers", nil)
+	_, errs, _ = FindPath(request, m, nil)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "missing", errs[0].ValidationSubType)
+
+	// the host does not match the env enum, which only matters in strict mode.
+	request, _ = http.NewRequest(http.MethodGet, "https://prod.things.com/api/v1/burgers", nil)
+	_, errs, _ = FindPath(request, m, nil)
+	assert.Empty(t, errs)
+	route, errCount := findStrict(t, m, request)
+	assert.Nil(t, route)
+	assert.Equal(t, 1, errCount)
+}
+
+func TestFindPath_StrictServerMatching(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+  - url: http://localhost:8080
+paths:
+  /burgers:
+    get:
+      operationId: listBurgers
+`
+	m := buildModel(t, []byte(spec))
+
+	for _, tc := range []struct {
+		url    string
+		server int // index of the expected server, -1 for no match
+	}{
+		{"https://things.com/api/burgers", 0},
+		{"http://things.com/api/burgers", -1},     // wrong scheme
+		{"https://other.com/api/burgers", -1},     // wrong host
+		{"https://things.com:443/api/burgers", 0}, // the server has no port, so any port will do
+		{"http://localhost:8080/burgers", 1},
+		{"http://localhost:9090/burgers", -1},
+	} {
+		request, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+		route, errs := findStrict(t, m, request)
+		if tc.server < 0 {
+			assert.Nil(t, route, tc.url)
+			assert.Equal(t, 1, errs, tc.url)
+			continue
+		}
+		assert.Zero(t, errs, tc.url)
+		require.NotNil(t, route, tc.url)
+		assert.Same(t, m.Servers[tc.server], route.Server, tc.url)
+	}
+
+	// server side requests carry no scheme or host in the URL, the Host header and TLS state are used instead.
+	request, _ := http.NewRequest(http.MethodGet, "/api/burgers", nil)
+	request.Host = "things.com"
+	request.TLS = &tls.ConnectionState{}
+	route, errs := findStrict(t, m, request)
+	assert.Zero(t, errs)
+	require.NotNil(t, route)
+	assert.Same(t, m.Servers[0], route.Server)
+
+	request.TLS = nil
+	route, _ = findStrict(t, m, request)
+	assert.Nil(t, route)
+}
+
+func TestFindPath_PathAndOperationServers(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/v1
+paths:
+  /pets:
+    servers:
+      - url: https://things.com/v2
+    get:
+      operationId: listPets
+  /burgers:
+    get:
+      operationId: listBurgers
+    post:
+      operationId: createBurger
+      servers:
+        - url: https://admin.things.com/v3
+`
+	m := buildModel(t, []byte(spec))
+	pets := m.Paths.PathItems.GetOrZero("/pets")
+	burgers := m.Paths.PathItems.GetOrZero("/burgers")
+
+	for _, tc := range []struct{ method, url string }{
+		{http.MethodGet, "https://things.com/v2/pets"},
+		{http.MethodGet, "https://things.com/v1/pets"},
+		{http.MethodGet, "https://things.com/v1/burgers"},
+		{http.MethodPost, "https://admin.things.com/v3/burgers"},
+		{http.MethodPost, "https://things.com/v1/burgers"},
+		{http.MethodGet, "https://admin.things.com/v3/burgers"},
+	} {
+		assertSameAsFindPath(t, m, tc.method, tc.url)
+	}
+
+	// path level servers replace those of the document
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/v2/pets", nil)
+	route, errs := findStrict(t, m, request)
+	assert.Zero(t, errs)
+	require.NotNil(t, route)
+	assert.Same(t, pets.Servers[0], route.Server)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v1/pets", nil)
+	route, _ = findStrict(t, m, request)
+	assert.Nil(t, route)
+
+	// operation level servers replace those of the path item, for that operation only
+	request, _ = http.NewRequest(http.MethodPost, "https://admin.things.com/v3/burgers", nil)
+	route, errs = findStrict(t, m, request)
+	assert.Zero(t, errs)
+	require.NotNil(t, route)
+	assert.Equal(t, "createBurger", route.Operation.OperationId)
+	assert.Same(t, burgers.Post.Servers[0], route.Server)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v1/burgers", nil)
+	route, errs = findStrict(t, m, request)
+	assert.Zero(t, errs)
+	require.NotNil(t, route)
+	assert.Equal(t, "listBurgers", route.Operation.OperationId)
+	assert.Same(t, m.Servers[0], route.Server)
+
+	// the path matches through the document servers, but the operation is only served by its own.
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/v1/burgers", nil)
+	route, _ = findStrict(t, m, request)
+	require.NotNil(t, route)
+	assert.Nil(t, route.Operation)
+	_, fpErrs, _ := FindPathWithOptions(request, m, config.NewValidationOptions(config.WithStrictServerMatching()))
+	require.Len(t, fpErrs, 1)
+	assert.True(t, fpErrs[0].IsOperationMissingError())
+
+	request, _ = http.NewRequest(http.MethodGet, "https://admin.things.com/v3/burgers", nil)
+	route, _ = findStrict(t, m, request)
+	assert.Nil(t, route)
+
+	// path parameters are stripped of the base path that applies to the operation
+	request, _ = http.NewRequest(http.MethodPost, "https://admin.things.com/v3/burgers", nil)
+	assert.Equal(t, "/burgers", StripRequestPathForPathItem(request, m, burgers, nil))
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v2/pets", nil)
+	assert.Equal(t, "/pets", StripRequestPathForPathItem(request, m, pets, nil))
+}
+
+func TestRouter_ServerIndex(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/v1
+paths:
+  /pets:
+    servers:
+      - url: https://things.com/v2
+    get:
+      operationId: listPets
+  /burgers:
+    post:
+      operationId: createBurger
+      servers:
+        - url: https://admin.things.com/v3
+`
+	m := buildModel(t, []byte(spec))
+	router := NewRouter(m, config.WithStrictServerMatching())
+	require.Len(t, router.servers.groups, 3)
+	options := config.NewValidationOptions(config.WithPathRouter(router))
+
+	// the servers the router compiled are used to strip request paths, and lookups do not compile new ones.
+	request, _ := http.NewRequest(http.MethodPost, "https://admin.things.com/v3/burgers", nil)
+	assert.Equal(t, "/burgers", StripRequestPathWithOptions(request, m, m.Paths.PathItems.GetOrZero("/burgers"), options))
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v2/pets", nil)
+	assert.Equal(t, "/pets", StripRequestPathWithOptions(request, m, m.Paths.PathItems.GetOrZero("/pets"), options))
+	assert.NotNil(t, router.lookupLinear(request))
+
+	// servers added after the router was built are compiled for the request alone.
+	item := &v3.PathItem{Servers: []*v3.Server{{URL: "https://things.com/v4"}}}
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v4/fries", nil)
+	assert.Equal(t, "/fries", StripRequestPathWithOptions(request, m, item, options))
+	assert.Len(t, router.servers.groups, 3)
+
+	// without a router for the document, the servers are compiled for the request.
+	assert.Equal(t, "/fries", StripRequestPathWithOptions(request, m, item, nil))
+	assert.Equal(t, "/fries", StripRequestPathWithOptions(request, m, item, config.NewValidationOptions()))
+}
+
+func TestServerIndex_Group(t *testing.T) {
+	shared := &v3.Server{URL: "https://things.com/v1"}
+	x := newServerIndex(&v3.Document{}, nil)
+
+	// lists that start with the same server are still different lists.
+	first, _ := x.group([]*v3.Server{shared, {URL: "https://things.com/v2"}})
+	second, _ := x.group([]*v3.Server{shared, {URL: "https://things.com/v3"}})
+	assert.NotEqual(t, first, second)
+
+	servers := []*v3.Server{shared}
+	third, _ := x.group(servers)
+	again, _ := x.group(servers)
+	assert.Equal(t, third, again)
+	assert.Len(t, x.groups, 4)
+
+	// a sealed index does not compile lists it has not seen.
+	x.seal()
+	_, ok := x.group([]*v3.Server{shared, {URL: "https://things.com/v4"}})
+	assert.False(t, ok)
+}
+
+func TestStripRequestPath_SegmentBoundary(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: /api
+paths:
+  /ary:
+    get:
+      operationId: ary
+`
+	m := buildModel(t, []byte(spec))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/apiary", nil)
+	assert.Equal(t, "/apiary", StripRequestPath(request, m))
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/ary", nil)
+	assert.Equal(t, "/ary", StripRequestPath(request, m))
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api", nil)
+	assert.Equal(t, "/", StripRequestPath(request, m))
+
+	assertSameAsFindPath(t, m, http.MethodGet, "https://things.com/apiary")
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/apiary", nil)
+	pathItem, errs, _ := FindPath(request, m, nil)
+	assert.Nil(t, pathItem)
+	assert.Len(t, errs, 1)
+}
@@ -0,0 +1,232 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
+	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/parameters"
+)
+
+// decodeMultipartBody decodes a multipart/form-data body into an object that can be validated against the schema of
+// the media type, each part becomes a property keyed by its form name. Parts are decoded according to their
+// property schema: JSON parts (by content type, or encoding) are decoded as JSON, other parts are coerced to the
+// primitive type of the schema. Opaque parts (format: binary, or a contentMediaType) are never read into memory,
+// they are represented by an empty string, so that required parts are still checked by the schema. The body has
+// already been read within the multipart memory limit.
+//
+// The content type and headers of each part are checked against the encoding of its property, any problems are
+// returned as validation errors. The error is only set when the body cannot be decoded at all.
+func decodeMultipartBody(request *http.Request, body []byte, contentType string, schema *base.Schema,
+	encoding *orderedmap.Map[string, *v3.Encoding], options *config.ValidationOptions,
+) (any, []*errors.ValidationError, error) {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, nil, err
+	}
+	boundary := params[helpers.Boundary]
+	if boundary == "" {
+		return nil, nil, fmt.Errorf("the content type '%s' has no multipart boundary", contentType)
+	}
+
+	var validationErrors []*errors.ValidationError
+	values := make(map[string][]any)
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		name := part.FormName()
+		if name == "" {
+			_ = part.Close()
+			continue
+		}
+
+		propSchema := helpers.PropertySchema(schema, name)
+		itemSchema := propSchema
+		if isArraySchema(propSchema) && propSchema.Items != nil && propSchema.Items.IsA() {
+			itemSchema = propSchema.Items.A.Schema()
+		}
+		var enc *v3.Encoding
+		if encoding != nil {
+			enc = encoding.GetOrZero(name)
+		}
+
+		partContentType := part.Header.Get(helpers.ContentTypeHeader)
+		if partContentType == "" {
+			partContentType = defaultPartContentType(part, itemSchema)
+		}
+		if enc != nil {
+			validationErrors = append(validationErrors, checkPartEncoding(request, name, part, partContentType, enc, options)...)
+		}
+
+		if isOpaqueSchema(itemSchema) {
+			_, _ = io.Copy(io.Discard, part)
+			values[name] = append(values[name], "")
+			continue
+		}
+
+		data, err := io.ReadAll(part)
+		if err != nil {
+			return nil, nil, err
+		}
+		values[name] = append(values[name], decodePart(data, partContentType, enc, itemSchema))
+	}
+
+	decoded := make(map[string]any, len(values))
+	for name, vals := range values {
+		if len(vals) == 1 && !isArraySchema(helpers.PropertySchema(schema, name)) {
+			decoded[name] = vals[0]
+			continue
+		}
+		decoded[name] = vals
+	}
+	return decoded, validationErrors, nil
+}
+
+// multipartMemoryLimit returns the number of bytes of a multipart/form-data body that may be read into memory.
+func multipartMemoryLimit(options *config.ValidationOptions) int64 {
+	if options.MultipartMemoryLimit <= 0 {
+		return config.DefaultMultipartMemoryLimit
+	}
+	return options.MultipartMemoryLimit
+}
+
+// decodePart decodes the value of a part that is not opaque.
+func decodePart(data []byte, contentType string, enc *v3.Encoding, sch *base.Schema) any {
+	isJSON := strings.Contains(strings.ToLower(contentType), helpers.JSONType)
+	if enc != nil && enc.ContentType != "" {
+		isJSON = isJSON || strings.Contains(strings.ToLower(enc.ContentType), helpers.JSONType)
+	}
+	if isJSON {
+		var v any
+		if err := json.Unmarshal(data, &v); err == nil {
+			return v
+		}
+	}
+	return helpers.CastValueForSchema(string(data), sch)
+}
+
+// checkPartEncoding checks the content type and headers of a part against the encoding of its property.
+func checkPartEncoding(request *http.Request, name string, part *multipart.Part, contentType string, enc *v3.Encoding,
+	options *config.ValidationOptions,
+) []*errors.ValidationError {
+	var validationErrors []*errors.ValidationError
+	if enc.ContentType != "" && !contentTypeAllowed(contentType, enc.ContentType) {
+		validationErrors = append(validationErrors, errors.MultipartPartContentTypeInvalid(request, name, contentType, enc))
+	}
+	if enc.Headers == nil {
+		return validationErrors
+	}
+	for headerName, header := range enc.Headers.FromOldest() {
+		// the content type of a part is described by the encoding, not its headers.
+		if header == nil || strings.EqualFold(headerName, helpers.ContentTypeHeader) {
+			continue
+		}
+		value := part.Header.Get(headerName)
+		if value == "" {
+			if header.Required {
+				validationErrors = append(validationErrors, errors.MultipartPartHeaderMissing(request, name, headerName, header))
+			}
+			continue
+		}
+		if header.Schema == nil {
+			continue
+		}
+		sch := header.Schema.Schema()
+		validationErrors = append(validationErrors, parameters.ValidateSingleParameterSchema(
+			sch,
+			helpers.CastValueForSchema(value, sch),
+			fmt.Sprintf("Part '%s' header", name),
+			fmt.Sprintf("The header of part '%s'", name),
+			headerName,
+			helpers.RequestBodyValidation,
+			helpers.MultipartHeader,
+			options,
+		)...)
+	}
+	return validationErrors
+}
+
+// contentTypeAllowed checks a content type against a comma separated list of media types, or media ranges
+// (e.g. image/*), as used by the contentType property of an encoding.
+func contentTypeAllowed(contentType, allowed string) bool {
+	ct, _, _ := helpers.ExtractContentType(contentType)
+	ctRange := strings.SplitN(strings.ToLower(ct), "/", 2)
+	for _, a := range strings.Split(allowed, helpers.Comma) {
+		a, _, _ = helpers.ExtractContentType(strings.TrimSpace(a))
+		aRange := strings.SplitN(strings.ToLower(a), "/", 2)
+		if len(aRange) != 2 || len(ctRange) != 2 {
+			if strings.EqualFold(a, ct) {
+				return true
+			}
+			continue
+		}
+		if (aRange[0] == "*" || aRange[0] == ctRange[0]) && (aRange[1] == "*" || aRange[1] == ctRange[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// defaultPartContentType returns the content type of a part that does not set one: files and opaque parts are
+// application/octet-stream, objects and arrays are application/json, and anything else is text/plain.
+func defaultPartContentType(part *multipart.Part, sch *base.Schema) string {
+	if part.FileName() != "" || isOpaqueSchema(sch) {
+		return "application/octet-stream"
+	}
+	if sch != nil {
+		for _, typ := range sch.Type {
+			if typ == helpers.Object || typ == helpers.Array {
+				return helpers.JSONContentType
+			}
+		}
+	}
+	return "text/plain"
+}
+
+// isOpaqueSchema returns true if values of the schema are binary data, which is not validated. The high level
+// schema does not carry contentMediaType, so it is read from the low level model.
+func isOpaqueSchema(sch *base.Schema) bool {
+	if sch == nil {
+		return false
+	}
+	if sch.Format == "binary" {
+		return true
+	}
+	low := sch.GoLow()
+	return low != nil && low.ContentMediaType.Value != ""
+}
+
+func isArraySchema(sch *base.Schema) bool {
+	if sch == nil {
+		return false
+	}
+	for _, typ := range sch.Type {
+		if typ == helpers.Array {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@
 package requests
 
 import (
+	"context"
 	"net/http"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -28,6 +29,16 @@ type RequestBodyValidator interface {
 	ValidateRequestBodyWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
 }
 
+// ContextRequestBodyValidator is a RequestBodyValidator that can abandon validation when a context is done. The
+// RequestBodyValidator created by NewRequestBodyValidator is always a ContextRequestBodyValidator.
+type ContextRequestBodyValidator interface {
+	RequestBodyValidator
+
+	// ValidateRequestBodyWithPathItemCtx will validate the request body for an operation, abandoning schema evaluation
+	// and returning a cancellation error if the context is done before validation completes.
+	ValidateRequestBodyWithPathItemCtx(ctx context.Context, request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+}
+
 // NewRequestBodyValidator will create a new RequestBodyValidator from an OpenAPI 3+ document
 func NewRequestBodyValidator(document *v3.Document, opts ...config.Option) RequestBodyValidator {
 	options := config.NewValidationOptions(opts...)
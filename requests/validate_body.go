@@ -4,8 +4,12 @@
 package requests
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -14,10 +18,11 @@ import (
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
 )
 
 func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options.RegexCache)
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.document, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -25,6 +30,10 @@ func (v *requestBodyValidator) ValidateRequestBody(request *http.Request) (bool,
 }
 
 func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateRequestBodyWithPathItemCtx(context.Background(), request, pathItem, pathValue)
+}
+
+func (v *requestBodyValidator) ValidateRequestBodyWithPathItemCtx(ctx context.Context, request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.ParameterValidationPath,
@@ -66,9 +75,17 @@ func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Req
 		return false, []*errors.ValidationError{errors.RequestContentTypeNotFound(operation, request, pathValue)}
 	}
 
-	// we currently only support JSON validation for request bodies
+	// streams (OpenAPI 3.2) are validated item by item.
+	if mediaType.Schema == nil && mediaType.ItemSchema != nil && helpers.IsSequentialMediaType(contentType) {
+		validationSucceeded, validationErrors := v.validateRequestItems(ctx, request, mediaType)
+		errors.PopulateValidationErrors(validationErrors, request, pathValue)
+		return validationSucceeded, validationErrors
+	}
+
+	// we currently support JSON, XML and form validation for request bodies
 	// this will capture *everything* that contains some form of 'json' in the content type
-	if !strings.Contains(strings.ToLower(contentType), helpers.JSONType) {
+	if !strings.Contains(strings.ToLower(contentType), helpers.JSONType) && !helpers.IsFormURLEncoded(contentType) &&
+		!helpers.IsMultipartFormData(contentType) && !schema_validation.IsXMLContentType(contentType) {
 		return true, nil
 	}
 
@@ -81,10 +98,13 @@ func (v *requestBodyValidator) ValidateRequestBodyWithPathItem(request *http.Req
 	schema := mediaType.Schema.Schema()
 
 	validationSucceeded, validationErrors := ValidateRequestSchema(&ValidateRequestSchemaInput{
-		Request: request,
-		Schema:  schema,
-		Version: helpers.VersionToFloat(v.document.Version),
-		Options: []config.Option{config.WithExistingOpts(v.options)},
+		Request:     request,
+		Schema:      schema,
+		Version:     helpers.VersionToFloat(v.document.Version),
+		Options:     []config.Option{config.WithExistingOpts(v.options)},
+		Context:     ctx,
+		ContentType: contentType,
+		Encoding:    mediaType.Encoding,
 	})
 
 	errors.PopulateValidationErrors(validationErrors, request, pathValue)
@@ -108,3 +128,38 @@ func (v *requestBodyValidator) extractContentType(contentType string, operation
 	}
 	return nil, false
 }
+
+// validateRequestItems validates each item of a stream (JSON Lines, JSON text sequences, server-sent events...)
+// against the itemSchema of the media type. Failures are located by the index of the item.
+func (v *requestBodyValidator) validateRequestItems(ctx context.Context, request *http.Request,
+	mediaType *v3.MediaType,
+) (bool, []*errors.ValidationError) {
+	itemSchema := mediaType.ItemSchema.Schema()
+	if itemSchema == nil || request.Body == nil {
+		return true, nil
+	}
+	body, err := io.ReadAll(request.Body)
+	_ = request.Body.Close()
+	request.Body = io.NopCloser(bytes.NewBuffer(body))
+	if err != nil {
+		return true, nil
+	}
+
+	var validationErrors []*errors.ValidationError
+	for i, item := range helpers.SplitSequentialItems(request.Header.Get(helpers.ContentTypeHeader), body) {
+		itemRequest := request.Clone(request.Context())
+		itemRequest.Body = io.NopCloser(bytes.NewReader(item))
+		itemRequest.ContentLength = int64(len(item))
+		_, itemErrors := ValidateRequestSchema(&ValidateRequestSchemaInput{
+			Request:     itemRequest,
+			Schema:      itemSchema,
+			Version:     helpers.VersionToFloat(v.document.Version),
+			Options:     []config.Option{config.WithExistingOpts(v.options)},
+			Context:     ctx,
+			ContentType: helpers.JSONContentType,
+		})
+		errors.PrefixSchemaLocations(itemErrors, strconv.Itoa(i))
+		validationErrors = append(validationErrors, itemErrors...)
+	}
+	return len(validationErrors) == 0, validationErrors
+}
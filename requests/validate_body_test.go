@@ -5,9 +5,14 @@ package requests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"strings"
 	"sync"
 	"testing"
 
@@ -16,6 +21,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
 
@@ -1495,3 +1501,547 @@ components:
 	assert.Len(t, errors[0].SchemaValidationErrors, 1)
 	assert.Equal(t, "'test' is not valid email: missing @", errors[0].SchemaValidationErrors[0].Reason)
 }
+
+func TestValidateBody_WithPathItemCtx_Cancelled(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model).(ContextRequestBodyValidator)
+
+	body := []byte(`{"name":"Big Mac"}`)
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		bytes.NewBuffer(body))
+	request.Header.Set("Content-Type", "application/json")
+
+	pathItem, _, pathValue := paths.FindPath(request, &m.Model, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	valid, errors := v.ValidateRequestBodyWithPathItemCtx(ctx, request, pathItem, pathValue)
+
+	assert.False(t, valid)
+	require.Len(t, errors, 1)
+	assert.True(t, errors[0].IsCancellationError())
+	assert.Equal(t, "/burgers/createBurger", errors[0].SpecPath)
+
+	// the body has not been consumed, so it can still be validated.
+	valid, errors = v.ValidateRequestBodyWithPathItemCtx(context.Background(), request, pathItem, pathValue)
+	assert.True(t, valid)
+	assert.Len(t, errors, 0)
+}
+
+func TestValidateBody_FormURLEncoded(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/x-www-form-urlencoded:
+            schema:
+              type: object
+              required: [name]
+              additionalProperties: false
+              properties:
+                name:
+                  type: string
+                patties:
+                  type: integer
+                  maximum: 3
+                vegetarian:
+                  type: boolean
+                toppings:
+                  type: array
+                  items:
+                    type: string
+                sauces:
+                  type: array
+                  items:
+                    type: string
+                    enum: [ketchup, mayo]
+                meta:
+                  type: object
+                  properties:
+                    source:
+                      type: string
+            encoding:
+              sauces:
+                style: pipeDelimited
+                explode: false
+              meta:
+                contentType: application/json`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	post := func(body string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+			bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return request
+	}
+
+	valid, errs := v.ValidateRequestBody(post(
+		"name=big+mac&patties=2&vegetarian=false&toppings=onion&toppings=pickle&sauces=ketchup|mayo" +
+			"&meta=%7B%22source%22%3A%22app%22%7D"))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// each violation points at the form field
+	valid, errs = v.ValidateRequestBody(post("name=big+mac&patties=four&vegetarian=maybe&sauces=ketchup|mustard"))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	fields := make(map[string]bool)
+	for _, sve := range errs[0].SchemaValidationErrors {
+		fields[sve.FieldName] = true
+	}
+	assert.True(t, fields["patties"])
+	assert.True(t, fields["vegetarian"])
+	assert.True(t, fields["1"]) // the second sauce
+	assert.Equal(t, "/burgers/createBurger", errs[0].SpecPath)
+
+	valid, errs = v.ValidateRequestBody(post("name=big+mac&patties=4"))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	require.Len(t, errs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "$.patties", errs[0].SchemaValidationErrors[0].FieldPath)
+
+	// unknown fields are kept, so additionalProperties applies
+	valid, errs = v.ValidateRequestBody(post("name=big+mac&cheese=yes"))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+
+	// a body that cannot be decoded
+	valid, errs = v.ValidateRequestBody(post("name=%zz"))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Reason, "cannot be decoded")
+}
+
+const multipartSpec = `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}/photos:
+    post:
+      requestBody:
+        required: true
+        content:
+          multipart/form-data:
+            schema:
+              type: object
+              required: [photo, caption]
+              properties:
+                caption:
+                  type: string
+                  maxLength: 20
+                rating:
+                  type: integer
+                tags:
+                  type: array
+                  items:
+                    type: string
+                details:
+                  type: object
+                  required: [camera]
+                  properties:
+                    camera:
+                      type: string
+                photo:
+                  type: string
+                  format: binary
+                thumbnail:
+                  type: string
+                  contentMediaType: image/png
+            encoding:
+              photo:
+                contentType: image/png, image/jpeg
+                headers:
+                  X-Photo-Rating:
+                    required: true
+                    schema:
+                      type: integer
+                      minimum: 1
+              thumbnail:
+                contentType: image/*`
+
+type multipartField struct {
+	name, fileName, contentType, value string
+	headers                            map[string]string
+}
+
+func newMultipartRequest(t *testing.T, fields ...multipartField) *http.Request {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for _, f := range fields {
+		header := textproto.MIMEHeader{}
+		disposition := fmt.Sprintf(`form-data; name="%s"`, f.name)
+		if f.fileName != "" {
+			disposition += fmt.Sprintf(`; filename="%s"`, f.fileName)
+		}
+		header.Set("Content-Disposition", disposition)
+		if f.contentType != "" {
+			header.Set("Content-Type", f.contentType)
+		}
+		for k, v := range f.headers {
+			header.Set(k, v)
+		}
+		part, err := writer.CreatePart(header)
+		require.NoError(t, err)
+		_, _ = part.Write([]byte(f.value))
+	}
+	require.NoError(t, writer.Close())
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/1/photos", &body)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestValidateBody_MultipartFormData(t *testing.T) {
+	doc, _ := libopenapi.NewDocument([]byte(multipartSpec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	photo := multipartField{
+		name: "photo", fileName: "burger.png", contentType: "image/png", value: "\x89PNG not really json",
+		headers: map[string]string{"X-Photo-Rating": "5"},
+	}
+
+	request := newMultipartRequest(t,
+		multipartField{name: "caption", value: "big mac"},
+		multipartField{name: "rating", value: "4"},
+		multipartField{name: "tags", value: "lunch"},
+		multipartField{name: "details", contentType: "application/json", value: `{"camera":"phone"}`},
+		photo,
+		multipartField{name: "thumbnail", fileName: "small.png", contentType: "image/png", value: "\x00\x01"},
+	)
+	valid, errs := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// the body can still be read after validation
+	require.NoError(t, request.ParseMultipartForm(1024))
+	assert.Equal(t, "big mac", request.FormValue("caption"))
+}
+
+func TestValidateBody_MultipartFormData_Invalid(t *testing.T) {
+	doc, _ := libopenapi.NewDocument([]byte(multipartSpec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	request := newMultipartRequest(t,
+		multipartField{name: "caption", value: "a caption that is far too long"},
+		multipartField{name: "rating", value: "five"},
+		multipartField{name: "details", contentType: "application/json", value: `{}`},
+		multipartField{
+			name: "photo", fileName: "burger.gif", contentType: "image/gif", value: "GIF89a",
+			headers: map[string]string{"X-Photo-Rating": "0"},
+		},
+		multipartField{name: "thumbnail", fileName: "small.txt", contentType: "text/plain", value: "hi"},
+	)
+	valid, errs := v.ValidateRequestBody(request)
+	assert.False(t, valid)
+
+	subTypes := make(map[string]int)
+	fields := make(map[string]bool)
+	for _, e := range errs {
+		subTypes[e.ValidationSubType]++
+		for _, sve := range e.SchemaValidationErrors {
+			fields[sve.FieldName] = true
+		}
+	}
+	assert.Equal(t, 2, subTypes[helpers.MultipartContentType]) // photo and thumbnail
+	assert.Equal(t, 1, subTypes[helpers.MultipartHeader])      // the rating header is below the minimum
+	assert.Equal(t, 1, subTypes[helpers.Schema])
+	assert.True(t, fields["caption"])
+	assert.True(t, fields["rating"])
+	assert.True(t, fields["details"])
+	for _, e := range errs {
+		assert.Equal(t, "/burgers/{burgerId}/photos", e.SpecPath)
+	}
+
+	// required parts, and required part headers
+	request = newMultipartRequest(t,
+		multipartField{name: "photo", fileName: "burger.png", contentType: "image/png", value: "png"},
+	)
+	valid, errs = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 2)
+	assert.Equal(t, helpers.MultipartHeader, errs[0].ValidationSubType)
+	assert.Equal(t, "POST request body part 'photo' is missing the 'X-Photo-Rating' header", errs[0].Message)
+	assert.Equal(t, helpers.Schema, errs[1].ValidationSubType)
+	assert.Contains(t, errs[1].SchemaValidationErrors[0].Reason, "caption")
+}
+
+func TestValidateBody_MultipartFormData_MemoryLimit(t *testing.T) {
+	doc, _ := libopenapi.NewDocument([]byte(multipartSpec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model, config.WithMultipartMemoryLimit(1024))
+
+	request := newMultipartRequest(t,
+		multipartField{name: "caption", value: "big mac"},
+		multipartField{
+			name: "photo", fileName: "burger.png", contentType: "image/png", value: strings.Repeat("x", 256),
+			headers: map[string]string{"X-Photo-Rating": "5"},
+		},
+	)
+	valid, errs := v.ValidateRequestBody(request)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// binary parts count towards the limit too, the body is not read past it
+	request = newMultipartRequest(t,
+		multipartField{name: "caption", value: "big mac"},
+		multipartField{
+			name: "photo", fileName: "burger.png", contentType: "image/png", value: strings.Repeat("x", 2048),
+			headers: map[string]string{"X-Photo-Rating": "5"},
+		},
+	)
+	length := request.ContentLength
+	valid, errs = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.MultipartMemoryLimit, errs[0].ValidationSubType)
+	assert.Equal(t, "The multipart request body exceeds the memory limit of 1024 bytes", errs[0].Reason)
+
+	// the whole body can still be read later
+	body, _ := io.ReadAll(request.Body)
+	assert.Equal(t, length, int64(len(body)))
+
+	// no boundary
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/burgers/1/photos", strings.NewReader("nope"))
+	request.Header.Set("Content-Type", "multipart/form-data")
+	valid, errs = v.ValidateRequestBody(request)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0].Reason, "no multipart boundary")
+}
+
+func TestValidateBody_XMLRequest(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/vnd.burger+xml:
+            schema:
+              type: object
+              xml:
+                name: burger
+              required: [name]
+              properties:
+                name:
+                  type: string
+                toppings:
+                  type: array
+                  xml:
+                    wrapped: true
+                  items:
+                    type: string
+                    xml:
+                      name: topping`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	post := func(body string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+			bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/vnd.burger+xml")
+		return request
+	}
+
+	valid, errs := v.ValidateRequestBody(post(
+		`<burger><name>Big Mac</name><toppings><topping>onion</topping><topping>pickle</topping></toppings></burger>`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateRequestBody(post(`<burger><toppings><topping>onion</topping></toppings></burger>`))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.RequestBodyValidation, errs[0].ValidationType)
+	assert.Equal(t, "/burgers/createBurger", errs[0].SpecPath)
+	assert.Equal(t, http.MethodPost, errs[0].RequestMethod)
+}
+
+func TestValidateBody_ReadOnlyProperties(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Burger'
+components:
+  schemas:
+    Burger:
+      type: object
+      required: [id, name]
+      properties:
+        id:
+          type: string
+          readOnly: true
+        name:
+          type: string
+        sides:
+          type: array
+          items:
+            type: object
+            required: [id]
+            properties:
+              id:
+                type: integer
+                readOnly: true`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	post := func(body string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers", bytes.NewBufferString(body))
+		request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+		return request
+	}
+
+	// readOnly properties are not required in a request, even when the schema says so
+	valid, errs := v.ValidateRequestBody(post(`{"name": "Big Mac", "sides": [{}]}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateRequestBody(post(`{"id": "abc", "name": "Big Mac", "sides": [{"id": 1}]}`))
+	assert.False(t, valid)
+	require.Len(t, errs, 2)
+	assert.Equal(t, helpers.ReadOnly, errs[0].ValidationSubType)
+	assert.Equal(t, "POST request body for '/burgers' contains the readOnly property 'id'", errs[0].Message)
+	assert.Equal(t, "$.id", errs[0].SchemaValidationErrors[0].FieldPath)
+	assert.Equal(t, "$.sides[0].id", errs[1].SchemaValidationErrors[0].FieldPath)
+
+	// properties that are not readOnly are still required
+	valid, errs = v.ValidateRequestBody(post(`{"sides": []}`))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, helpers.Schema, errs[0].ValidationSubType)
+	assert.Contains(t, errs[0].SchemaValidationErrors[0].Reason, "name")
+	assert.NotContains(t, errs[0].SchemaValidationErrors[0].Reason, "id")
+}
+
+func TestValidateBody_DiscriminatorRouting(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+  /animals:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Animal'
+components:
+  schemas:
+    Pet:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: petType
+        mapping:
+          kitty: '#/components/schemas/Cat'
+          dog: Dog
+    Animal:
+      oneOf:
+        - $ref: '#/components/schemas/Cat'
+        - $ref: '#/components/schemas/Dog'
+      discriminator:
+        propertyName: petType
+    Cat:
+      type: object
+      required: [petType, lives]
+      properties:
+        petType:
+          type: string
+        lives:
+          type: integer
+    Dog:
+      type: object
+      required: [petType, bark]
+      properties:
+        petType:
+          type: string
+        bark:
+          type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewRequestBodyValidator(&m.Model)
+
+	post := func(path, body string) *http.Request {
+		request, _ := http.NewRequest(http.MethodPost, "https://things.com"+path, bytes.NewBufferString(body))
+		request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+		return request
+	}
+
+	valid, errs := v.ValidateRequestBody(post("/pets", `{"petType": "kitty", "lives": 9}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateRequestBody(post("/pets", `{"petType": "dog", "bark": "woof"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// only the selected schema is validated, so there is a single precise failure
+	valid, errs = v.ValidateRequestBody(post("/pets", `{"petType": "kitty", "lives": "nine"}`))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	require.Len(t, errs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "$.lives", errs[0].SchemaValidationErrors[0].FieldPath)
+	assert.Contains(t, errs[0].SchemaValidationErrors[0].Reason, "got string, want integer")
+
+	// values the mapping does not list select the schema of the same name
+	valid, errs = v.ValidateRequestBody(post("/pets", `{"petType": "Cat", "lives": 9}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateRequestBody(post("/pets", `{"petType": "hamster"}`))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	require.Len(t, errs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "discriminator property 'petType' has unknown value 'hamster', expected one of 'Cat', 'Dog', 'dog', 'kitty'",
+		errs[0].SchemaValidationErrors[0].Reason)
+
+	// without a mapping, schema names are the values
+	valid, errs = v.ValidateRequestBody(post("/animals", `{"petType": "Dog", "bark": "woof"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateRequestBody(post("/animals", `{"petType": "Hamster"}`))
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	require.Len(t, errs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "discriminator property 'petType' has unknown value 'Hamster', expected one of 'Cat', 'Dog'",
+		errs[0].SchemaValidationErrors[0].Reason)
+}
@@ -5,6 +5,7 @@ package requests
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -14,12 +15,15 @@ import (
 	"strconv"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
 	"github.com/pb33f/libopenapi/utils"
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"go.yaml.in/yaml/v4"
 	"golang.org/x/text/language"
 	"golang.org/x/text/message"
 
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+
 	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/errors"
@@ -35,6 +39,13 @@ type ValidateRequestSchemaInput struct {
 	Schema  *base.Schema    // Required: The OpenAPI schema to validate against
 	Version float32         // Required: OpenAPI version (3.0 or 3.1)
 	Options []config.Option // Optional: Functional options (defaults applied if empty/nil)
+	Context context.Context // Optional: Abandons validation when done (never cancelled if nil)
+
+	// ContentType is the media type of the body, JSON is assumed when empty. Form bodies
+	// (application/x-www-form-urlencoded and multipart/form-data) are decoded into an object, using the Encoding map
+	// of the media type. XML bodies are decoded using the xml objects of the schema.
+	ContentType string
+	Encoding    *orderedmap.Map[string, *v3.Encoding]
 }
 
 // ValidateRequestSchema will validate a http.Request pointer against a schema.
@@ -63,37 +74,27 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*errors.V
 		}}
 	}
 
+	var cached *cache.SchemaCacheEntry
 	if validationOptions.SchemaCache != nil {
 		hash := input.Schema.GoLow().Hash()
-		if cached, ok := validationOptions.SchemaCache.Load(hash); ok && cached != nil && cached.CompiledSchema != nil {
-			renderedSchema = cached.RenderedInline
-			referenceSchema = cached.ReferenceSchema
-			jsonSchema = cached.RenderedJSON
-			compiledSchema = cached.CompiledSchema
+		if entry, ok := validationOptions.SchemaCache.Load(hash); ok && entry != nil && entry.CompiledSchema != nil {
+			cached = entry
+			renderedSchema = entry.RenderedInline
+			referenceSchema = entry.ReferenceSchema
+			jsonSchema = entry.RenderedJSON
+			compiledSchema = entry.RequestSchema
 		}
 	}
 
-	// Cache miss or no cache - render and compile
+	// Cache miss, no cache, or the schema has not been compiled for requests yet - render and compile
 	if compiledSchema == nil {
-		renderedSchema, _ = input.Schema.RenderInline()
-		referenceSchema = string(renderedSchema)
-		jsonSchema, _ = utils.ConvertYAMLtoJSON(renderedSchema)
-
-		var err error
-		schemaName := fmt.Sprintf("%x", input.Schema.GoLow().Hash())
-		compiledSchema, err = helpers.NewCompiledSchemaWithVersion(
-			schemaName,
-			jsonSchema,
-			validationOptions,
-			input.Version,
-		)
-		if err != nil {
+		compilationFailed := func(err error) []*errors.ValidationError {
 			violation := &errors.SchemaValidationFailure{
 				Reason:          fmt.Sprintf("failed to compile JSON schema: %s", err.Error()),
 				Location:        "schema compilation",
 				ReferenceSchema: referenceSchema,
 			}
-			validationErrors = append(validationErrors, &errors.ValidationError{
+			return append(validationErrors, &errors.ValidationError{
 				ValidationType:    helpers.RequestBodyValidation,
 				ValidationSubType: helpers.Schema,
 				Message: fmt.Sprintf("%s request body for '%s' failed schema compilation",
@@ -105,26 +106,78 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*errors.V
 				HowToFix:               "check the request schema for invalid JSON Schema syntax, complex regex patterns, or unsupported schema constructs",
 				Context:                referenceSchema,
 			})
-			return false, validationErrors
 		}
 
+		var err error
+		entry := &cache.SchemaCacheEntry{}
+		schemaName := fmt.Sprintf("%x", input.Schema.GoLow().Hash())
+		if cached != nil {
+			*entry = *cached
+		} else {
+			renderedSchema, _ = input.Schema.RenderInline()
+			referenceSchema = string(renderedSchema)
+			jsonSchema, _ = utils.ConvertYAMLtoJSON(renderedSchema)
+			jsonSchema = helpers.AnnotateDiscriminators(input.Schema, jsonSchema)
+
+			entry.Schema = input.Schema
+			entry.RenderedInline = renderedSchema
+			entry.ReferenceSchema = referenceSchema
+			entry.RenderedJSON = jsonSchema
+			entry.CompiledSchema, err = helpers.NewCompiledSchemaWithVersion(
+				schemaName,
+				jsonSchema,
+				validationOptions,
+				input.Version,
+			)
+			if err != nil {
+				return false, compilationFailed(err)
+			}
+		}
+
+		// readOnly properties are never required in a request, and strict additionalProperties may close objects.
+		compiledSchema = entry.CompiledSchema
+		requestSchema, relaxed := helpers.TransformSchemaForDirection(jsonSchema, helpers.RequestDirection)
+		if relaxed || helpers.StrictPropertiesApply(validationOptions, helpers.RequestDirection) {
+			compiledSchema, err = helpers.NewCompiledSchemaForDirection(
+				schemaName,
+				requestSchema,
+				validationOptions,
+				input.Version,
+				helpers.RequestDirection,
+			)
+			if err != nil {
+				return false, compilationFailed(err)
+			}
+		}
+		entry.RequestSchema = compiledSchema
+
 		if validationOptions.SchemaCache != nil {
-			hash := input.Schema.GoLow().Hash()
-			validationOptions.SchemaCache.Store(hash, &cache.SchemaCacheEntry{
-				Schema:          input.Schema,
-				RenderedInline:  renderedSchema,
-				ReferenceSchema: referenceSchema,
-				RenderedJSON:    jsonSchema,
-				CompiledSchema:  compiledSchema,
-			})
+			validationOptions.SchemaCache.Store(input.Schema.GoLow().Hash(), entry)
 		}
 	}
 
 	request := input.Request
 	schema := input.Schema
 
+	if err := helpers.ContextError(input.Context); err != nil {
+		return false, []*errors.ValidationError{errors.ValidationCancelled(err, request)}
+	}
+
 	var requestBody []byte
-	if request != nil && request.Body != nil {
+	if request != nil && request.Body != nil && helpers.IsMultipartFormData(input.ContentType) {
+		limit := multipartMemoryLimit(validationOptions)
+		requestBody, _ = io.ReadAll(io.LimitReader(request.Body, limit+1))
+		if int64(len(requestBody)) > limit {
+			// put back what was read, the rest of the body is left to be read later by another player in the chain.
+			request.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(requestBody), request.Body), request.Body}
+			return false, []*errors.ValidationError{errors.MultipartMemoryLimitExceeded(request, limit)}
+		}
+		_ = request.Body.Close()
+		request.Body = io.NopCloser(bytes.NewBuffer(requestBody))
+	} else if request != nil && request.Body != nil {
 		requestBody, _ = io.ReadAll(request.Body)
 
 		// close the request body, so it can be re-read later by another player in the chain
@@ -136,7 +189,19 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*errors.V
 	var decodedObj interface{}
 
 	if len(requestBody) > 0 {
-		err := json.Unmarshal(requestBody, &decodedObj)
+		var err error
+		if helpers.IsFormURLEncoded(input.ContentType) {
+			decodedObj, err = decodeFormBody(requestBody, schema, input.Encoding)
+		} else if helpers.IsMultipartFormData(input.ContentType) {
+			var partErrors []*errors.ValidationError
+			decodedObj, partErrors, err = decodeMultipartBody(request, requestBody, input.ContentType, schema,
+				input.Encoding, validationOptions)
+			validationErrors = append(validationErrors, partErrors...)
+		} else if schema_validation.IsXMLContentType(input.ContentType) {
+			decodedObj, err = schema_validation.DecodeXML(string(requestBody), schema)
+		} else {
+			err = json.Unmarshal(requestBody, &decodedObj)
+		}
 		if err != nil {
 			// cannot decode the request body, so it's not valid
 			violation := &errors.SchemaValidationFailure{
@@ -192,11 +257,22 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*errors.V
 		return false, validationErrors
 	}
 
+	// readOnly properties are set by the server, they cannot be sent in a request.
+	for _, violation := range schema_validation.FindAccessModeViolations(decodedObj, schema, helpers.RequestDirection,
+		validationOptions, input.Version) {
+		validationErrors = append(validationErrors, errors.ReadOnlyPropertyInRequest(request, violation.Property,
+			violation.InstancePath, violation.Schema))
+	}
+
 	// validate the object against the schema
-	scErrs := compiledSchema.Validate(decodedObj)
+	scErrs := helpers.ValidateSchemaWithContext(input.Context, compiledSchema, decodedObj)
 	if scErrs != nil {
 
-		jk := scErrs.(*jsonschema.ValidationError)
+		jk, ok := scErrs.(*jsonschema.ValidationError)
+		if !ok {
+			// evaluation was abandoned, the context is done.
+			return false, []*errors.ValidationError{errors.ValidationCancelled(scErrs, request)}
+		}
 
 		// flatten the validationErrors
 		schFlatErrs := jk.BasicOutput().Errors
@@ -293,3 +369,13 @@ func ValidateRequestSchema(input *ValidateRequestSchemaInput) (bool, []*errors.V
 	}
 	return true, nil
 }
+
+// decodeFormBody decodes a form body, the decoded object is returned as any, so it can be validated in the same
+// way as a JSON body.
+func decodeFormBody(body []byte, schema *base.Schema, encoding *orderedmap.Map[string, *v3.Encoding]) (any, error) {
+	decoded, err := helpers.DecodeFormURLEncoded(body, schema, encoding)
+	if err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
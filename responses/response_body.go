@@ -4,6 +4,7 @@
 package responses
 
 import (
+	"context"
 	"net/http"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -28,6 +29,16 @@ type ResponseBodyValidator interface {
 	ValidateResponseBodyWithPathItem(request *http.Request, response *http.Response, pathItem *v3.PathItem, pathFound string) (bool, []*errors.ValidationError)
 }
 
+// ContextResponseBodyValidator is a ResponseBodyValidator that can abandon validation when a context is done. The
+// ResponseBodyValidator created by NewResponseBodyValidator is always a ContextResponseBodyValidator.
+type ContextResponseBodyValidator interface {
+	ResponseBodyValidator
+
+	// ValidateResponseBodyWithPathItemCtx will validate the response body for a http.Response pointer, abandoning
+	// schema evaluation and returning a cancellation error if the context is done before validation completes.
+	ValidateResponseBodyWithPathItemCtx(ctx context.Context, request *http.Request, response *http.Response, pathItem *v3.PathItem, pathFound string) (bool, []*errors.ValidationError)
+}
+
 // NewResponseBodyValidator will create a new ResponseBodyValidator from an OpenAPI 3+ document
 func NewResponseBodyValidator(document *v3.Document, opts ...config.Option) ResponseBodyValidator {
 	options := config.NewValidationOptions(opts...)
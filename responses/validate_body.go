@@ -4,7 +4,10 @@
 package responses
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
@@ -17,13 +20,14 @@ import (
 	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
+	"github.com/pb33f/libopenapi-validator/schema_validation"
 )
 
 func (v *responseBodyValidator) ValidateResponseBody(
 	request *http.Request,
 	response *http.Response,
 ) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.document, v.options.RegexCache)
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.document, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
@@ -31,6 +35,10 @@ func (v *responseBodyValidator) ValidateResponseBody(
 }
 
 func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.Request, response *http.Response, pathItem *v3.PathItem, pathFound string) (bool, []*errors.ValidationError) {
+	return v.ValidateResponseBodyWithPathItemCtx(context.Background(), request, response, pathItem, pathFound)
+}
+
+func (v *responseBodyValidator) ValidateResponseBodyWithPathItemCtx(ctx context.Context, request *http.Request, response *http.Response, pathItem *v3.PathItem, pathFound string) (bool, []*errors.ValidationError) {
 	if pathItem == nil {
 		return false, []*errors.ValidationError{{
 			ValidationType:    helpers.ParameterValidationPath,
@@ -56,6 +64,7 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 
 	// extract the media type from the content type header.
 	mediaTypeSting, _, _ := helpers.ExtractContentType(contentType)
+	accept := request.Header.Get(helpers.AcceptHeader)
 
 	// check if the response code is in the contract
 	foundResponse := operation.Responses.Codes.GetOrZero(codeStr)
@@ -69,10 +78,10 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 
 	if foundResponse != nil {
 		if foundResponse.Content != nil { // only validate if we have content types.
-			// check content type has been defined in the contract
-			if mediaType, ok := foundResponse.Content.Get(mediaTypeSting); ok {
+			// check content type has been defined in the contract (or is covered by a media range)
+			if _, mediaType, ok := helpers.FindMediaType(contentType, foundResponse.Content); ok {
 				validationErrors = append(validationErrors,
-					v.checkResponseSchema(request, response, mediaTypeSting, mediaType)...)
+					v.checkResponseSchema(ctx, request, response, mediaTypeSting, mediaType)...)
 			} else {
 				// check that the operation *actually* returns a body. (i.e. a 204 response)
 				if foundResponse.Content != nil && orderedmap.Len(foundResponse.Content) > 0 {
@@ -81,15 +90,19 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 						errors.ResponseContentTypeNotFound(operation, request, response, codeStr, false))
 				}
 			}
+			if notAcceptable(response, contentType, accept, foundResponse.Content) {
+				validationErrors = append(validationErrors,
+					errors.ResponseNotAcceptable(operation, request, response, codeStr, foundResponse.Content))
+			}
 		}
 	} else {
 		// no code match, check for default response
 		if operation.Responses.Default != nil && operation.Responses.Default.Content != nil {
-			// check content type has been defined in the contract
-			if mediaType, ok := operation.Responses.Default.Content.Get(mediaTypeSting); ok {
+			// check content type has been defined in the contract (or is covered by a media range)
+			if _, mediaType, ok := helpers.FindMediaType(contentType, operation.Responses.Default.Content); ok {
 				foundResponse = operation.Responses.Default
 				validationErrors = append(validationErrors,
-					v.checkResponseSchema(request, response, contentType, mediaType)...)
+					v.checkResponseSchema(ctx, request, response, contentType, mediaType)...)
 			} else {
 				// check that the operation *actually* returns a body. (i.e. a 204 response)
 				if operation.Responses.Default.Content != nil && orderedmap.Len(operation.Responses.Default.Content) > 0 {
@@ -98,6 +111,10 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 						errors.ResponseContentTypeNotFound(operation, request, response, codeStr, true))
 				}
 			}
+			if notAcceptable(response, contentType, accept, operation.Responses.Default.Content) {
+				validationErrors = append(validationErrors,
+					errors.ResponseNotAcceptable(operation, request, response, codeStr, operation.Responses.Default.Content))
+			}
 		} else {
 			// TODO: add support for '2XX' and '3XX' responses in the contract
 			// no default, no code match, nothing!
@@ -109,7 +126,8 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 	if foundResponse != nil {
 		// check for headers in the response
 		if foundResponse.Headers != nil {
-			if ok, herrs := ValidateResponseHeaders(request, response, foundResponse.Headers); !ok {
+			if ok, herrs := ValidateResponseHeaders(request, response, foundResponse.Headers,
+				config.WithExistingOpts(v.options)); !ok {
 				validationErrors = append(validationErrors, herrs...)
 			}
 		}
@@ -123,7 +141,23 @@ func (v *responseBodyValidator) ValidateResponseBodyWithPathItem(request *http.R
 	return true, nil
 }
 
+// notAcceptable returns true if a response with a body has a content type that the Accept header of the request
+// does not accept, when the contract describes content for the response. A response that is already a
+// '406 Not Acceptable' is never reported.
+func notAcceptable(response *http.Response, contentType, accept string,
+	content *orderedmap.Map[string, *v3.MediaType],
+) bool {
+	if contentType == "" || accept == "" || response.StatusCode == http.StatusNotAcceptable {
+		return false
+	}
+	if orderedmap.Len(content) == 0 {
+		return false
+	}
+	return !helpers.IsAcceptable(contentType, accept)
+}
+
 func (v *responseBodyValidator) checkResponseSchema(
+	ctx context.Context,
 	request *http.Request,
 	response *http.Response,
 	contentType string,
@@ -131,21 +165,28 @@ func (v *responseBodyValidator) checkResponseSchema(
 ) []*errors.ValidationError {
 	var validationErrors []*errors.ValidationError
 
-	// currently, we can only validate JSON based responses, so check for the presence
+	// streams (OpenAPI 3.2) are validated item by item.
+	if mediaType.Schema == nil && mediaType.ItemSchema != nil && helpers.IsSequentialMediaType(contentType) {
+		return v.checkResponseItems(ctx, request, response, mediaType)
+	}
+
+	// currently, we can only validate JSON and XML based responses, so check for the presence
 	// of 'json' in the content type (what ever it may be) so we can perform a schema check on it.
-	// anything other than JSON, will be ignored.
-	if strings.Contains(strings.ToLower(contentType), helpers.JSONType) {
+	// anything other than JSON or XML, will be ignored.
+	if strings.Contains(strings.ToLower(contentType), helpers.JSONType) || schema_validation.IsXMLContentType(contentType) {
 		// extract schema from media type
 		if mediaType.Schema != nil {
 			schema := mediaType.Schema.Schema()
 
 			// Validate response schema
 			valid, vErrs := ValidateResponseSchema(&ValidateResponseSchemaInput{
-				Request:  request,
-				Response: response,
-				Schema:   schema,
-				Version:  helpers.VersionToFloat(v.document.Version),
-				Options:  []config.Option{config.WithExistingOpts(v.options)},
+				Request:     request,
+				Response:    response,
+				Schema:      schema,
+				Version:     helpers.VersionToFloat(v.document.Version),
+				Options:     []config.Option{config.WithExistingOpts(v.options)},
+				Context:     ctx,
+				ContentType: contentType,
 			})
 			if !valid {
 				validationErrors = append(validationErrors, vErrs...)
@@ -154,3 +195,42 @@ func (v *responseBodyValidator) checkResponseSchema(
 	}
 	return validationErrors
 }
+
+// checkResponseItems validates each item of a stream (JSON Lines, JSON text sequences, server-sent events...)
+// against the itemSchema of the media type. Failures are located by the index of the item.
+func (v *responseBodyValidator) checkResponseItems(
+	ctx context.Context,
+	request *http.Request,
+	response *http.Response,
+	mediaType *v3.MediaType,
+) []*errors.ValidationError {
+	itemSchema := mediaType.ItemSchema.Schema()
+	if itemSchema == nil || response.Body == nil {
+		return nil
+	}
+	body, err := io.ReadAll(response.Body)
+	_ = response.Body.Close()
+	response.Body = io.NopCloser(bytes.NewBuffer(body))
+	if err != nil {
+		return nil
+	}
+
+	var validationErrors []*errors.ValidationError
+	for i, item := range helpers.SplitSequentialItems(response.Header.Get(helpers.ContentTypeHeader), body) {
+		itemResponse := *response
+		itemResponse.Body = io.NopCloser(bytes.NewReader(item))
+		itemResponse.ContentLength = int64(len(item))
+		_, itemErrors := ValidateResponseSchema(&ValidateResponseSchemaInput{
+			Request:     request,
+			Response:    &itemResponse,
+			Schema:      itemSchema,
+			Version:     helpers.VersionToFloat(v.document.Version),
+			Options:     []config.Option{config.WithExistingOpts(v.options)},
+			Context:     ctx,
+			ContentType: helpers.JSONContentType,
+		})
+		errors.PrefixSchemaLocations(itemErrors, strconv.Itoa(i))
+		validationErrors = append(validationErrors, itemErrors...)
+	}
+	return validationErrors
+}
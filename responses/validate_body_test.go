@@ -5,9 +5,11 @@ package responses
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
@@ -18,6 +20,7 @@ import (
 	"github.com/pb33f/libopenapi"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/pb33f/libopenapi-validator/config"
 	"github.com/pb33f/libopenapi-validator/helpers"
 	"github.com/pb33f/libopenapi-validator/paths"
 )
@@ -1520,3 +1523,297 @@ func (er *errorReader) Read(p []byte) (n int, err error) {
 func (er *errorReader) Close() error {
 	return nil
 }
+
+func TestValidateBody_WithPathItemCtx_Cancelled(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      responses:
+        default:
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model).(ContextResponseBodyValidator)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger", nil)
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+
+	response := &http.Response{
+		Header:     http.Header{},
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"name":"Big Mac"}`)),
+	}
+	response.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+
+	pathItem, _, pathValue := paths.FindPath(request, &m.Model, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	valid, errs := v.ValidateResponseBodyWithPathItemCtx(ctx, request, response, pathItem, pathValue)
+
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.True(t, errs[0].IsCancellationError())
+	assert.Equal(t, "/burgers/createBurger", errs[0].SpecPath)
+}
+
+func TestValidateBody_XMLResponse(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers/{burgerId}:
+    get:
+      responses:
+        '200':
+          content:
+            application/xml:
+              schema:
+                type: object
+                xml:
+                  name: burger
+                required: [name, patties]
+                properties:
+                  id:
+                    type: integer
+                    xml:
+                      attribute: true
+                  name:
+                    type: string
+                  patties:
+                    type: integer
+                    maximum: 3`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model)
+
+	respond := func(body string) *http.Response {
+		res := httptest.NewRecorder()
+		res.Header().Set(helpers.ContentTypeHeader, "application/xml; charset=utf-8")
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.WriteString(body)
+		return res.Result()
+	}
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers/1", nil)
+
+	valid, errs := v.ValidateResponseBody(request, respond(`<burger id="1"><name>Big Mac</name><patties>2</patties></burger>`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateResponseBody(request, respond(`<burger id="1"><name>Big Mac</name><patties>9</patties></burger>`))
+	assert.False(t, valid)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, helpers.ResponseBodyValidation, errs[0].ValidationType)
+		assert.Equal(t, "/burgers/{burgerId}", errs[0].SpecPath)
+		assert.Equal(t, http.MethodGet, errs[0].RequestMethod)
+		assert.Equal(t, "$.patties", errs[0].SchemaValidationErrors[0].FieldPath)
+	}
+
+	valid, errs = v.ValidateResponseBody(request, respond(`<burger id="1"><name>Big Mac</name></burger>`))
+	assert.False(t, valid)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].SchemaValidationErrors[0].Reason, "patties")
+	}
+	valid, errs = v.ValidateResponseBody(request, respond(`<burger><name>Big Mac`))
+	assert.False(t, valid)
+	if assert.Len(t, errs, 1) {
+		assert.Contains(t, errs[0].Reason, "malformed xml")
+	}
+}
+
+func TestValidateBody_WriteOnlyProperties(t *testing.T) {
+	spec := `openapi: 3.0.3
+paths:
+  /users/{id}:
+    get:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name, password]
+                properties:
+                  name:
+                    type: string
+                  password:
+                    type: string
+                    writeOnly: true`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/users/1", nil)
+	respond := func(body string) *http.Response {
+		res := httptest.NewRecorder()
+		res.Header().Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.WriteString(body)
+		return res.Result()
+	}
+
+	// writeOnly properties are not required in a response, even when the schema says so
+	valid, errs := v.ValidateResponseBody(request, respond(`{"name": "dave"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateResponseBody(request, respond(`{"name": "dave", "password": "hunter2"}`))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.ResponseBodyValidation, errs[0].ValidationType)
+	assert.Equal(t, helpers.WriteOnly, errs[0].ValidationSubType)
+	assert.Equal(t, "200 response body for '/users/1' contains the writeOnly property 'password'", errs[0].Message)
+	assert.Equal(t, "/users/{id}", errs[0].SpecPath)
+}
+
+func TestValidateBody_ContentNegotiation(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    get:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+            text/*:
+              schema:
+                type: string
+        default:
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [code]
+                properties:
+                  code:
+                    type: integer`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model)
+
+	respond := func(code int, contentType, body string) *http.Response {
+		res := httptest.NewRecorder()
+		res.Header().Set(helpers.ContentTypeHeader, contentType)
+		res.WriteHeader(code)
+		_, _ = res.WriteString(body)
+		return res.Result()
+	}
+	get := func(accept string) *http.Request {
+		request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+		if accept != "" {
+			request.Header.Set(helpers.AcceptHeader, accept)
+		}
+		return request
+	}
+
+	// structured syntax suffixes are described by their base type, and validated against its schema
+	valid, errs := v.ValidateResponseBody(get(""), respond(http.StatusOK, "application/vnd.burger+json", `{"name": "Big Mac"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	valid, errs = v.ValidateResponseBody(get(""), respond(http.StatusOK, "application/vnd.burger+json", `{}`))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.Schema, errs[0].ValidationSubType)
+
+	// media ranges in the contract cover the content type
+	valid, errs = v.ValidateResponseBody(get(""), respond(http.StatusOK, "text/plain", "Big Mac"))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	valid, errs = v.ValidateResponseBody(get(""), respond(http.StatusOK, "image/png", "png"))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.RequestBodyContentType, errs[0].ValidationSubType)
+
+	// the content type satisfies the accept header
+	valid, errs = v.ValidateResponseBody(get("text/html;q=0.9, application/json"), respond(http.StatusOK, "application/json", `{"name": "Big Mac"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	valid, errs = v.ValidateResponseBody(get("application/*;q=0.2"), respond(http.StatusOK, "application/json", `{"name": "Big Mac"}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	// the content type is not acceptable, the service should have responded with a 406
+	valid, errs = v.ValidateResponseBody(get("text/html, application/json;q=0"), respond(http.StatusOK, "application/json", `{"name": "Big Mac"}`))
+	assert.False(t, valid)
+	if assert.Len(t, errs, 1) {
+		assert.True(t, errs[0].IsNotAcceptableError())
+		assert.Equal(t, "GET / 200 operation response content type 'application/json' is not acceptable", errs[0].Message)
+		assert.Contains(t, errs[0].HowToFix, "(text/*)")
+		assert.Equal(t, "/burgers", errs[0].SpecPath)
+	}
+
+	// the default response is checked too
+	valid, errs = v.ValidateResponseBody(get("text/html"), respond(http.StatusInternalServerError, "application/json", `{"code": 500}`))
+	assert.False(t, valid)
+	if assert.Len(t, errs, 1) {
+		assert.True(t, errs[0].IsNotAcceptableError())
+		assert.Contains(t, errs[0].HowToFix, "(none are defined)")
+	}
+
+	// a 406 is the right response to an unacceptable request
+	valid, errs = v.ValidateResponseBody(get("text/html"), respond(http.StatusNotAcceptable, "application/json", `{"code": 406}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+}
+
+func TestValidateBody_StrictAdditionalProperties(t *testing.T) {
+	spec := `openapi: 3.0.3
+paths:
+  /burgers:
+    get:
+      responses:
+        '200':
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                  extras:
+                    type: object
+                    x-strict-properties: false
+                    properties:
+                      cheese:
+                        type: boolean`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	v := NewResponseBodyValidator(&m.Model, config.WithStrictAdditionalProperties(config.StrictPropertiesResponses))
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/burgers", nil)
+	respond := func(body string) *http.Response {
+		res := httptest.NewRecorder()
+		res.Header().Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+		res.WriteHeader(http.StatusOK)
+		_, _ = res.WriteString(body)
+		return res.Result()
+	}
+
+	// the extras object opts out, so it may carry properties it does not define
+	valid, errs := v.ValidateResponseBody(request, respond(`{"name": "Big Mac", "extras": {"cheese": true, "bacon": true}}`))
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+
+	valid, errs = v.ValidateResponseBody(request, respond(`{"name": "Big Mac", "secret": "sauce"}`))
+	assert.False(t, valid)
+	assert.Len(t, errs, 1)
+	assert.Equal(t, helpers.Schema, errs[0].ValidationSubType)
+}
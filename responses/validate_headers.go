@@ -4,10 +4,12 @@
 package responses
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
 
+	"github.com/pb33f/libopenapi/datamodel/high/base"
 	"github.com/pb33f/libopenapi/orderedmap"
 
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
@@ -69,21 +71,134 @@ func ValidateResponseHeaders(
 		}
 	}
 
-	// validate the model schemas if they are set.
-	for h, header := range locatedHeaders {
-		if header.model.Schema != nil {
-			schema := header.model.Schema.Schema()
-			if schema != nil && header.model.Required {
-				for _, headerValue := range header.value {
-					validationErrors = append(validationErrors,
-						parameters.ValidateParameterSchema(schema, nil, headerValue, "header",
-							"response header", h, helpers.ResponseBodyValidation, lowv3.HeadersLabel, options)...)
-				}
-			}
+	// validate every header that is present against its schema (or content), required or not. Headers are
+	// checked in the order they are defined.
+	for name, header := range headers.FromOldest() {
+		located, ok := locatedHeaders[strings.ToLower(name)]
+		if !ok {
+			continue
 		}
+		if header.Deprecated && options.DeprecationHandler != nil {
+			options.DeprecationHandler(request, deprecatedHeaderWarning(located.name, header))
+		}
+		validationErrors = append(validationErrors,
+			validateResponseHeader(request, strings.ToLower(name), located.value, header, options)...)
 	}
 	if len(validationErrors) == 0 {
 		return true, nil
 	}
 	return false, validationErrors
 }
+
+// validateResponseHeader decodes the value of a response header as its definition describes, and validates it
+// against the schema of the header. A header that is sent more than once is combined into a single comma separated
+// value (RFC 9110, section 5.3). Headers are either described by a schema, serialized using style simple, or by
+// content, where JSON media types are decoded as JSON and anything else is a string.
+func validateResponseHeader(request *http.Request, name string, values []string, header *v3.Header,
+	options *config.ValidationOptions,
+) []*errors.ValidationError {
+	value := strings.Join(values, helpers.Comma)
+
+	var schema *base.Schema
+	var decoded any
+	switch {
+	case header.Schema != nil:
+		schema = header.Schema.Schema()
+		if schema == nil {
+			return nil
+		}
+		var ok bool
+		if decoded, ok = decodeSimpleHeader(value, schema, header.Explode); !ok {
+			return []*errors.ValidationError{errors.ResponseHeaderCannotBeDecoded(request, name, value, header)}
+		}
+	case orderedmap.Len(header.Content) > 0:
+		mediaTypeName, mediaType := header.Content.First().Key(), header.Content.First().Value()
+		if mediaType == nil || mediaType.Schema == nil {
+			return nil
+		}
+		schema = mediaType.Schema.Schema()
+		if schema == nil {
+			return nil
+		}
+		decoded = value
+		if strings.Contains(strings.ToLower(mediaTypeName), helpers.JSONType) {
+			if err := json.Unmarshal([]byte(value), &decoded); err != nil {
+				return []*errors.ValidationError{errors.ResponseHeaderCannotBeDecoded(request, name, value, header)}
+			}
+		}
+	default:
+		return nil
+	}
+
+	return parameters.ValidateSingleParameterSchema(schema, decoded, "header", "response header", name,
+		helpers.ResponseBodyValidation, lowv3.HeadersLabel, options)
+}
+
+// decodeSimpleHeader decodes a header value serialized with style simple, the only style headers can use. Arrays are
+// comma separated values, objects are comma separated keys and values, or key=value pairs when exploded. Values are
+// coerced to the types of their schemas.
+func decodeSimpleHeader(value string, schema *base.Schema, explode bool) (any, bool) {
+	switch helpers.SchemaPrimaryType(schema) {
+	case helpers.Array:
+		var items *base.Schema
+		if schema.Items != nil && schema.Items.IsA() {
+			items = schema.Items.A.Schema()
+		}
+		decoded := make([]any, 0)
+		if strings.TrimSpace(value) == "" {
+			return decoded, true
+		}
+		for _, item := range strings.Split(value, helpers.Comma) {
+			decoded = append(decoded, helpers.CastValueForSchema(strings.TrimSpace(item), items))
+		}
+		return decoded, true
+
+	case helpers.Object:
+		decoded := make(map[string]any)
+		if strings.TrimSpace(value) == "" {
+			return decoded, true
+		}
+		parts := strings.Split(value, helpers.Comma)
+		if explode {
+			for _, part := range parts {
+				key, val, ok := strings.Cut(strings.TrimSpace(part), helpers.Equals)
+				if !ok || key == "" {
+					return nil, false
+				}
+				decoded[key] = helpers.CastValueForSchema(val, helpers.PropertySchema(schema, key))
+			}
+			return decoded, true
+		}
+		if len(parts)%2 != 0 {
+			return nil, false
+		}
+		for i := 0; i < len(parts); i += 2 {
+			key := strings.TrimSpace(parts[i])
+			if key == "" {
+				return nil, false
+			}
+			decoded[key] = helpers.CastValueForSchema(strings.TrimSpace(parts[i+1]), helpers.PropertySchema(schema, key))
+		}
+		return decoded, true
+	}
+	return helpers.CastValueForSchema(strings.TrimSpace(value), schema), true
+}
+
+// deprecatedHeaderWarning builds the warning for a deprecated response header.
+func deprecatedHeaderWarning(name string, header *v3.Header) *config.DeprecationWarning {
+	warning := &config.DeprecationWarning{
+		In:       "response header",
+		Name:     name,
+		Message:  fmt.Sprintf("Response header '%s' is deprecated", name),
+		SpecLine: -1,
+		SpecCol:  -1,
+	}
+	if low := header.GoLow(); low != nil {
+		if low.Deprecated.KeyNode != nil {
+			warning.SpecLine, warning.SpecCol = low.Deprecated.KeyNode.Line, low.Deprecated.KeyNode.Column
+		} else if low.KeyNode != nil {
+			warning.SpecLine, warning.SpecCol = low.KeyNode.Line, low.KeyNode.Column
+		}
+	}
+	return warning
+}
@@ -10,6 +10,9 @@ import (
 
 	"github.com/pb33f/libopenapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/config"
 )
 
 func TestValidateResponseHeaders(t *testing.T) {
@@ -130,3 +133,119 @@ paths:
 	assert.True(t, valid)
 	assert.Len(t, errors, 0)
 }
+
+func TestValidateResponseHeaders_AllPresentHeaders(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /health:
+    get:
+      responses:
+        '200':
+          description: ok
+          headers:
+            X-RateLimit-Remaining:
+              schema:
+                type: integer
+                minimum: 0
+            X-Request-Id:
+              schema:
+                type: string
+            X-Tags:
+              schema:
+                type: array
+                items:
+                  type: integer
+            X-Limits:
+              schema:
+                type: object
+                properties:
+                  burst:
+                    type: integer
+            X-Limits-Exploded:
+              explode: true
+              schema:
+                type: object
+                required: [burst]
+                properties:
+                  burst:
+                    type: integer
+            X-Metadata:
+              content:
+                application/json:
+                  schema:
+                    type: object
+                    required: [region]
+                    properties:
+                      region:
+                        type: string
+            X-Legacy:
+              deprecated: true
+              schema:
+                type: string`
+
+	doc, _ := libopenapi.NewDocument([]byte(spec))
+	m, _ := doc.BuildV3Model()
+	headers := m.Model.Paths.PathItems.GetOrZero("/health").Get.Responses.Codes.GetOrZero("200").Headers
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/health", nil)
+
+	var warnings []*config.DeprecationWarning
+	handler := config.WithDeprecationHandler(func(_ *http.Request, warning *config.DeprecationWarning) {
+		warnings = append(warnings, warning)
+	})
+
+	respond := func(set map[string][]string) *http.Response {
+		res := httptest.NewRecorder()
+		for name, values := range set {
+			for _, value := range values {
+				res.Header().Add(name, value)
+			}
+		}
+		res.WriteHeader(http.StatusOK)
+		return res.Result()
+	}
+
+	valid, errs := ValidateResponseHeaders(request, respond(map[string][]string{
+		"X-RateLimit-Remaining": {"10"},
+		"X-Request-Id":          {"12345"}, // a string, even though it looks like a number
+		"X-Tags":                {"1, 2", "3"},
+		"X-Limits":              {"burst,5"},
+		"X-Limits-Exploded":     {"burst=5"},
+		"X-Metadata":            {`{"region": "eu"}`},
+		"X-Legacy":              {"yes"},
+	}), headers, handler)
+	assert.True(t, valid)
+	assert.Empty(t, errs)
+	require.Len(t, warnings, 1)
+	assert.Equal(t, "X-Legacy", warnings[0].Name)
+	assert.Equal(t, "response header", warnings[0].In)
+	assert.Greater(t, warnings[0].SpecLine, 0)
+
+	// optional headers are validated when they are present
+	valid, errs = ValidateResponseHeaders(request, respond(map[string][]string{
+		"X-RateLimit-Remaining": {"abc"},
+	}), headers)
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "header 'x-ratelimit-remaining' failed to validate", errs[0].Message)
+
+	valid, errs = ValidateResponseHeaders(request, respond(map[string][]string{
+		"X-RateLimit-Remaining": {"-1"},
+		"X-Tags":                {"1,two"},
+		"X-Limits-Exploded":     {"other=5"},
+		"X-Metadata":            {`{"zone": "eu"}`},
+	}), headers)
+	assert.False(t, valid)
+	assert.Len(t, errs, 4)
+
+	// values that cannot be decoded as their definition describes
+	valid, errs = ValidateResponseHeaders(request, respond(map[string][]string{
+		"X-Limits":          {"burst"},
+		"X-Limits-Exploded": {"burst"},
+		"X-Metadata":        {"{not json"},
+	}), headers)
+	assert.False(t, valid)
+	require.Len(t, errs, 3)
+	assert.Equal(t, "Response header 'x-limits' cannot be decoded", errs[0].Message)
+	assert.Equal(t, "Response header 'x-limits-exploded' cannot be decoded", errs[1].Message)
+	assert.Equal(t, "Response header 'x-metadata' cannot be decoded", errs[2].Message)
+}
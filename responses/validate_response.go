@@ -5,6 +5,7 @@ package responses
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -36,6 +37,11 @@ type ValidateResponseSchemaInput struct {
 	Schema   *base.Schema    // Required: The OpenAPI schema to validate against
 	Version  float32         // Required: OpenAPI version (3.0 or 3.1)
 	Options  []config.Option // Optional: Functional options (defaults applied if empty/nil)
+	Context  context.Context // Optional: Abandons validation when done (never cancelled if nil)
+
+	// ContentType is the media type of the body, JSON is assumed when empty. XML bodies are decoded using the xml
+	// objects of the schema.
+	ContentType string
 }
 
 // ValidateResponseSchema will validate the response body for a http.Response pointer. The request is used to
@@ -67,36 +73,27 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*errors
 		}}
 	}
 
+	var cached *cache.SchemaCacheEntry
 	if validationOptions.SchemaCache != nil {
 		hash := input.Schema.GoLow().Hash()
-		if cached, ok := validationOptions.SchemaCache.Load(hash); ok && cached != nil && cached.CompiledSchema != nil {
-			renderedSchema = cached.RenderedInline
-			referenceSchema = cached.ReferenceSchema
-			compiledSchema = cached.CompiledSchema
+		if entry, ok := validationOptions.SchemaCache.Load(hash); ok && entry != nil && entry.CompiledSchema != nil {
+			cached = entry
+			renderedSchema = entry.RenderedInline
+			referenceSchema = entry.ReferenceSchema
+			jsonSchema = entry.RenderedJSON
+			compiledSchema = entry.ResponseSchema
 		}
 	}
 
-	// Cache miss or no cache - render and compile
+	// Cache miss, no cache, or the schema has not been compiled for responses yet - render and compile
 	if compiledSchema == nil {
-		renderedSchema, _ = input.Schema.RenderInline()
-		referenceSchema = string(renderedSchema)
-		jsonSchema, _ = utils.ConvertYAMLtoJSON(renderedSchema)
-
-		var err error
-		schemaName := fmt.Sprintf("%x", input.Schema.GoLow().Hash())
-		compiledSchema, err = helpers.NewCompiledSchemaWithVersion(
-			schemaName,
-			jsonSchema,
-			validationOptions,
-			input.Version,
-		)
-		if err != nil {
+		compilationFailed := func(err error) []*errors.ValidationError {
 			violation := &errors.SchemaValidationFailure{
 				Reason:          fmt.Sprintf("failed to compile JSON schema: %s", err.Error()),
 				Location:        "schema compilation",
 				ReferenceSchema: referenceSchema,
 			}
-			validationErrors = append(validationErrors, &errors.ValidationError{
+			return append(validationErrors, &errors.ValidationError{
 				ValidationType:    helpers.ResponseBodyValidation,
 				ValidationSubType: helpers.Schema,
 				Message: fmt.Sprintf("%d response body for '%s' failed schema compilation",
@@ -109,18 +106,53 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*errors
 				HowToFix:               "check the response schema for invalid JSON Schema syntax, complex regex patterns, or unsupported schema constructs",
 				Context:                referenceSchema,
 			})
-			return false, validationErrors
 		}
 
+		var err error
+		entry := &cache.SchemaCacheEntry{}
+		schemaName := fmt.Sprintf("%x", input.Schema.GoLow().Hash())
+		if cached != nil {
+			*entry = *cached
+		} else {
+			renderedSchema, _ = input.Schema.RenderInline()
+			referenceSchema = string(renderedSchema)
+			jsonSchema, _ = utils.ConvertYAMLtoJSON(renderedSchema)
+			jsonSchema = helpers.AnnotateDiscriminators(input.Schema, jsonSchema)
+
+			entry.Schema = input.Schema
+			entry.RenderedInline = renderedSchema
+			entry.ReferenceSchema = referenceSchema
+			entry.RenderedJSON = jsonSchema
+			entry.CompiledSchema, err = helpers.NewCompiledSchemaWithVersion(
+				schemaName,
+				jsonSchema,
+				validationOptions,
+				input.Version,
+			)
+			if err != nil {
+				return false, compilationFailed(err)
+			}
+		}
+
+		// writeOnly properties are never required in a response, and strict additionalProperties may close objects.
+		compiledSchema = entry.CompiledSchema
+		responseSchema, relaxed := helpers.TransformSchemaForDirection(jsonSchema, helpers.ResponseDirection)
+		if relaxed || helpers.StrictPropertiesApply(validationOptions, helpers.ResponseDirection) {
+			compiledSchema, err = helpers.NewCompiledSchemaForDirection(
+				schemaName,
+				responseSchema,
+				validationOptions,
+				input.Version,
+				helpers.ResponseDirection,
+			)
+			if err != nil {
+				return false, compilationFailed(err)
+			}
+		}
+		entry.ResponseSchema = compiledSchema
+
 		if validationOptions.SchemaCache != nil {
-			hash := input.Schema.GoLow().Hash()
-			validationOptions.SchemaCache.Store(hash, &cache.SchemaCacheEntry{
-				Schema:          input.Schema,
-				RenderedInline:  renderedSchema,
-				ReferenceSchema: referenceSchema,
-				RenderedJSON:    jsonSchema,
-				CompiledSchema:  compiledSchema,
-			})
+			validationOptions.SchemaCache.Store(input.Schema.GoLow().Hash(), entry)
 		}
 	}
 
@@ -128,6 +160,10 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*errors
 	response := input.Response
 	schema := input.Schema
 
+	if err := helpers.ContextError(input.Context); err != nil {
+		return false, []*errors.ValidationError{errors.ValidationCancelled(err, request)}
+	}
+
 	if response == nil || response.Body == http.NoBody {
 		// cannot decode the response body, so it's not valid
 		violation := &errors.SchemaValidationFailure{
@@ -181,7 +217,12 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*errors
 	var decodedObj interface{}
 
 	if len(responseBody) > 0 {
-		err := json.Unmarshal(responseBody, &decodedObj)
+		var err error
+		if schema_validation.IsXMLContentType(input.ContentType) {
+			decodedObj, err = schema_validation.DecodeXML(string(responseBody), input.Schema)
+		} else {
+			err = json.Unmarshal(responseBody, &decodedObj)
+		}
 		if err != nil {
 			// cannot decode the response body, so it's not valid
 			violation := &errors.SchemaValidationFailure{
@@ -211,10 +252,21 @@ func ValidateResponseSchema(input *ValidateResponseSchemaInput) (bool, []*errors
 		return true, nil
 	}
 
+	// writeOnly properties must never leave the server.
+	for _, violation := range schema_validation.FindAccessModeViolations(decodedObj, schema, helpers.ResponseDirection,
+		validationOptions, input.Version) {
+		validationErrors = append(validationErrors, errors.WriteOnlyPropertyInResponse(request, response,
+			violation.Property, violation.InstancePath, violation.Schema))
+	}
+
 	// validate the object against the schema
-	scErrs := compiledSchema.Validate(decodedObj)
+	scErrs := helpers.ValidateSchemaWithContext(input.Context, compiledSchema, decodedObj)
 	if scErrs != nil {
-		jk := scErrs.(*jsonschema.ValidationError)
+		jk, ok := scErrs.(*jsonschema.ValidationError)
+		if !ok {
+			// evaluation was abandoned, the context is done.
+			return false, []*errors.ValidationError{errors.ValidationCancelled(scErrs, request)}
+		}
 
 		// flatten the validationErrors
 		schFlatErrs := jk.BasicOutput().Errors
@@ -0,0 +1,259 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package schema_validation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/utils"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+
+	"github.com/pb33f/libopenapi-validator/cache"
+	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+// AccessModeViolation is a property of a body that cannot be sent in its direction: a readOnly property of a
+// request body, or a writeOnly property of a response body.
+type AccessModeViolation struct {
+	Property     string       // The name of the property
+	InstancePath []string     // The path segments from the root of the body to the property
+	Schema       *base.Schema // The schema of the property that is readOnly or writeOnly
+}
+
+// FindAccessModeViolations walks a decoded body alongside its schema, and returns every property that cannot be
+// sent in the direction of the body. Properties are looked up in the schema, in every allOf schema, and in the oneOf
+// and anyOf schemas the value is valid against. A property is readOnly (or writeOnly) when any of its definitions
+// says so. The values of violating properties are not walked.
+func FindAccessModeViolations(decoded any, schema *base.Schema, direction helpers.Direction,
+	options *config.ValidationOptions, version float32,
+) []*AccessModeViolation {
+	if schema == nil || direction == helpers.AnyDirection {
+		return nil
+	}
+	if options == nil {
+		options = config.NewValidationOptions()
+	}
+	w := &accessModeWalker{
+		direction: direction,
+		options:   options,
+		version:   version,
+		compiled:  make(map[*base.Schema]*jsonschema.Schema),
+	}
+	w.walk(decoded, []*base.Schema{schema}, nil)
+	return w.violations
+}
+
+type accessModeWalker struct {
+	direction  helpers.Direction
+	options    *config.ValidationOptions
+	version    float32
+	compiled   map[*base.Schema]*jsonschema.Schema
+	violations []*AccessModeViolation
+}
+
+func (w *accessModeWalker) walk(value any, schemas []*base.Schema, path []string) {
+	schemas = w.expandComposition(schemas, value)
+	if len(schemas) == 0 {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		known := make(map[string]bool)
+		for _, sch := range schemas {
+			if sch.Properties == nil {
+				continue
+			}
+			for name := range sch.Properties.KeysFromOldest() {
+				if _, ok := v[name]; !ok || known[name] {
+					continue
+				}
+				known[name] = true
+				definitions := propertyDefinitions(schemas, name)
+				if violating := w.violating(definitions, v[name]); violating != nil {
+					w.violations = append(w.violations, &AccessModeViolation{
+						Property:     name,
+						InstancePath: appendPath(path, name),
+						Schema:       violating,
+					})
+					continue
+				}
+				w.walk(v[name], definitions, appendPath(path, name))
+			}
+		}
+
+		// the remaining properties are described by additionalProperties.
+		var additional []*base.Schema
+		for _, sch := range schemas {
+			if sch.AdditionalProperties != nil && sch.AdditionalProperties.IsA() {
+				if s := sch.AdditionalProperties.A.Schema(); s != nil {
+					additional = append(additional, s)
+				}
+			}
+		}
+		if len(additional) == 0 {
+			return
+		}
+		var names []string
+		for name := range v {
+			if !known[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			w.walk(v[name], additional, appendPath(path, name))
+		}
+
+	case []any:
+		var items []*base.Schema
+		for _, sch := range schemas {
+			if sch.Items != nil && sch.Items.IsA() {
+				if s := sch.Items.A.Schema(); s != nil {
+					items = append(items, s)
+				}
+			}
+		}
+		if len(items) == 0 {
+			return
+		}
+		for i := range v {
+			w.walk(v[i], items, appendPath(path, strconv.Itoa(i)))
+		}
+	}
+}
+
+// violating returns the first definition of a property that cannot be sent in the direction of the walk.
+func (w *accessModeWalker) violating(definitions []*base.Schema, value any) *base.Schema {
+	for _, def := range w.expandComposition(definitions, value) {
+		switch w.direction {
+		case helpers.RequestDirection:
+			if def.ReadOnly != nil && *def.ReadOnly {
+				return def
+			}
+		case helpers.ResponseDirection:
+			if def.WriteOnly != nil && *def.WriteOnly {
+				return def
+			}
+		}
+	}
+	return nil
+}
+
+// propertyDefinitions returns the definitions of a property in a set of schemas.
+func propertyDefinitions(schemas []*base.Schema, name string) []*base.Schema {
+	var definitions []*base.Schema
+	for _, sch := range schemas {
+		if sch.Properties == nil {
+			continue
+		}
+		if proxy := sch.Properties.GetOrZero(name); proxy != nil {
+			if s := proxy.Schema(); s != nil {
+				definitions = append(definitions, s)
+			}
+		}
+	}
+	return definitions
+}
+
+// expandComposition returns the schemas, followed by every schema they are composed of that applies to the value,
+// each schema once. allOf schemas always apply, oneOf and anyOf schemas only when the value is valid against them.
+func (w *accessModeWalker) expandComposition(schemas []*base.Schema, value any) []*base.Schema {
+	expanded := make([]*base.Schema, 0, len(schemas))
+	seen := make(map[*base.Schema]bool)
+	add := func(s *base.Schema) {
+		if s != nil && !seen[s] {
+			seen[s] = true
+			expanded = append(expanded, s)
+		}
+	}
+	for _, s := range schemas {
+		add(s)
+	}
+	for i := 0; i < len(expanded); i++ {
+		sch := expanded[i]
+		for _, proxy := range sch.AllOf {
+			add(proxy.Schema())
+		}
+		for _, composed := range [][]*base.SchemaProxy{sch.OneOf, sch.AnyOf} {
+			for _, proxy := range composed {
+				if s := proxy.Schema(); s != nil && w.matches(s, value) {
+					add(s)
+				}
+			}
+		}
+	}
+	return expanded
+}
+
+// matches reports whether the value is valid against a oneOf or anyOf schema. A schema that cannot be compiled is
+// assumed to match, so its properties are still checked.
+func (w *accessModeWalker) matches(schema *base.Schema, value any) bool {
+	compiled, ok := w.compiled[schema]
+	if !ok {
+		compiled = w.compile(schema)
+		w.compiled[schema] = compiled
+	}
+	return compiled == nil || compiled.Validate(value) == nil
+}
+
+// compile compiles a schema for the direction of the walk, so readOnly (or writeOnly) properties are not required.
+// Object schemas are left open, as they are when the schema is composed into another one. The schema cache is used
+// and filled as it is by the body validators.
+func (w *accessModeWalker) compile(schema *base.Schema) *jsonschema.Schema {
+	if schema.GoLow() == nil {
+		return nil
+	}
+	hash := schema.GoLow().Hash()
+	name := fmt.Sprintf("%x", hash)
+
+	var entry *cache.SchemaCacheEntry
+	if w.options.SchemaCache != nil {
+		if cached, ok := w.options.SchemaCache.Load(hash); ok && cached != nil && cached.CompiledSchema != nil {
+			entry = cached
+		}
+	}
+	if entry == nil {
+		renderedInline, _ := schema.RenderInline()
+		if len(renderedInline) == 0 {
+			return nil
+		}
+		renderedJSON, _ := utils.ConvertYAMLtoJSON(renderedInline)
+		renderedJSON = helpers.AnnotateDiscriminators(schema, renderedJSON)
+		compiledSchema, err := helpers.NewCompiledSchemaWithVersion(name, renderedJSON, w.options, w.version)
+		if err != nil {
+			return nil
+		}
+		entry = &cache.SchemaCacheEntry{
+			Schema:          schema,
+			RenderedInline:  renderedInline,
+			ReferenceSchema: string(renderedInline),
+			RenderedJSON:    renderedJSON,
+			CompiledSchema:  compiledSchema,
+		}
+		if w.options.SchemaCache != nil {
+			w.options.SchemaCache.Store(hash, entry)
+		}
+	}
+
+	transformed, relaxed := helpers.TransformSchemaForDirection(entry.RenderedJSON, w.direction)
+	if !relaxed {
+		return entry.CompiledSchema
+	}
+	compiled, err := helpers.NewCompiledSchemaWithVersion(name, transformed, w.options, w.version)
+	if err != nil {
+		return nil
+	}
+	return compiled
+}
+
+func appendPath(path []string, segment string) []string {
+	p := make([]string, len(path), len(path)+1)
+	copy(p, path)
+	return append(p, segment)
+}
@@ -0,0 +1,117 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package schema_validation
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pb33f/libopenapi-validator/helpers"
+)
+
+func TestFindAccessModeViolations(t *testing.T) {
+	spec := `openapi: 3.1.0
+components:
+  schemas:
+    Entity:
+      type: object
+      properties:
+        id:
+          type: integer
+          readOnly: true
+    User:
+      allOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: object
+          properties:
+            name:
+              type: string
+            password:
+              type: string
+              writeOnly: true
+            friends:
+              type: array
+              items:
+                $ref: '#/components/schemas/User'
+            settings:
+              type: object
+              additionalProperties:
+                type: object
+                properties:
+                  secret:
+                    type: string
+                    writeOnly: true
+            pet:
+              oneOf:
+                - type: object
+                  properties:
+                    tag:
+                      type: string
+                      readOnly: true
+                - type: string
+            toy:
+              anyOf:
+                - type: object
+                  required: [kind]
+                  properties:
+                    kind:
+                      const: ball
+                    serial:
+                      type: string
+                      readOnly: true
+                - type: object
+                  required: [kind]
+                  properties:
+                    kind:
+                      const: bone
+                    serial:
+                      type: string`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	m, errs := doc.BuildV3Model()
+	require.NoError(t, errs)
+	user := m.Model.Components.Schemas.GetOrZero("User").Schema()
+
+	body := map[string]any{
+		"id":       1,
+		"name":     "dave",
+		"password": "hunter2",
+		"friends": []any{
+			map[string]any{"id": 2, "name": "quobix"},
+			map[string]any{"name": "pb33f", "password": "secret", "toy": map[string]any{"kind": "ball", "serial": "y"}},
+		},
+		"settings": map[string]any{
+			"b": map[string]any{"secret": "x"},
+			"a": map[string]any{"secret": "y"},
+		},
+		"pet": map[string]any{"tag": "dog"},
+		"toy": map[string]any{"kind": "bone", "serial": "x"},
+	}
+
+	paths := func(violations []*AccessModeViolation) []string {
+		var p []string
+		for _, v := range violations {
+			p = append(p, helpers.ExtractJSONPathFromInstanceLocation(v.InstancePath))
+		}
+		return p
+	}
+
+	request := FindAccessModeViolations(body, user, helpers.RequestDirection, nil, 3.1)
+	assert.Equal(t, []string{"$.id", "$.friends[0].id", "$.friends[1].toy.serial", "$.pet.tag"}, paths(request))
+	assert.Equal(t, "id", request[0].Property)
+	assert.True(t, *request[0].Schema.ReadOnly)
+
+	response := FindAccessModeViolations(body, user, helpers.ResponseDirection, nil, 3.1)
+	assert.Equal(t, []string{"$.password", "$.friends[1].password", "$.settings.a.secret", "$.settings.b.secret"},
+		paths(response))
+
+	assert.Nil(t, FindAccessModeViolations(body, user, helpers.AnyDirection, nil, 3.1))
+	assert.Nil(t, FindAccessModeViolations(body, nil, helpers.RequestDirection, nil, 3.1))
+	assert.Empty(t, FindAccessModeViolations("dave", &base.Schema{}, helpers.RequestDirection, nil, 3.1))
+}
@@ -4,53 +4,96 @@
 // Package openapi_schemas contains the OpenAPI 3.0 and 3.1 schemas that are loaded from libopenapi, or our own
 // fork of the official OpenAPI repo specifications. Using an MD5 hash, we can compare the local version against
 // the remote version and determine if they differ, if they do - load the remote version.
+//
+// Documents are validated offline against the schemas libopenapi embeds, this package is only used when the latest
+// schemas are asked for (see config.WithOpenAPISchemaRefresh).
 package openapi_schemas
 
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"fmt"
 	"io"
 	"net/http"
+	"sync"
+	"time"
 
-	_ "embed"
+	"github.com/pb33f/libopenapi/datamodel"
 )
 
-var schema30, schema31 string
+// DefaultRefreshTimeout is how long fetching a remote schema waits, when no timeout is given.
+const DefaultRefreshTimeout = 5 * time.Second
+
+var (
+	remoteSchema3_0 = "https://raw.githubusercontent.com/pb33f/openapi-specification/main/schemas/v3.0/schema.json"
+	remoteSchema3_1 = "https://raw.githubusercontent.com/pb33f/openapi-specification/main/schemas/v3.1/schema.json"
+)
+
+// schema30 and schema31 hold the schemas loaded by LoadSchema3_0 and LoadSchema3_1, remote30 and remote31 those
+// refreshed by RefreshSchema3_0 and RefreshSchema3_1. They are kept apart, as loads compare the remote schema with
+// the schema they are given, and refreshes with the schema libopenapi embeds.
+var (
+	schema30, schema31 string
+	remote30, remote31 string
+	lock               sync.Mutex
+)
 
 // LoadSchema3_0 loads the latest OpenAPI 3.0 specification. The latest version is fetched from the OpenAPI repo.
 // and if there is no change in the schema, the local version is returned, otherwise the remote version is returned.
 func LoadSchema3_0(schema string) string {
-	if schema30 != "" {
-		return schema30
-	}
-	remoteSpec := "https://raw.githubusercontent.com/pb33f/openapi-specification/main/schemas/v3.0/schema.json"
-	schema30 = extractSchema(remoteSpec, schema)
-	return schema30
+	return loadSchema(&schema30, remoteSchema3_0, schema, DefaultRefreshTimeout)
 }
 
 // LoadSchema3_1 loads the latest OpenAPI 3.1 specification. The latest version is fetched from the OpenAPI repo.
 // and if there is no change in the schema, the local version is returned, otherwise the remote version is returned.
 func LoadSchema3_1(schema string) string {
-	if schema31 != "" {
-		return schema31
+	return loadSchema(&schema31, remoteSchema3_1, schema, DefaultRefreshTimeout)
+}
+
+// RefreshSchema3_0 works in the same way as LoadSchema3_0, comparing the remote schema with the OpenAPI 3.0 schema
+// libopenapi embeds, and waiting for the timeout at most. The embedded schema is returned when the remote schema
+// cannot be fetched.
+func RefreshSchema3_0(timeout time.Duration) string {
+	return loadSchema(&remote30, remoteSchema3_0, datamodel.OpenAPI3SchemaData, timeout)
+}
+
+// RefreshSchema3_1 works in the same way as LoadSchema3_1, comparing the remote schema with the OpenAPI 3.1 schema
+// libopenapi embeds, and waiting for the timeout at most. The embedded schema is returned when the remote schema
+// cannot be fetched.
+func RefreshSchema3_1(timeout time.Duration) string {
+	return loadSchema(&remote31, remoteSchema3_1, datamodel.OpenAPI31SchemaData, timeout)
+}
+
+func loadSchema(loaded *string, url, local string, timeout time.Duration) string {
+	lock.Lock()
+	defer lock.Unlock()
+	if *loaded != "" {
+		return *loaded
 	}
-	remoteSpec := "https://raw.githubusercontent.com/pb33f/openapi-specification/main/schemas/v3.1/schema.json"
-	schema31 = extractSchema(remoteSpec, schema)
-	return schema31
+	*loaded = extractSchema(url, local, timeout)
+	return *loaded
 }
 
-func getFile(url string) ([]byte, error) {
-	resp, err := http.Get(url)
+func getFile(url string, timeout time.Duration) ([]byte, error) {
+	if timeout <= 0 {
+		timeout = DefaultRefreshTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch '%s': %s", url, resp.Status)
+	}
 	return io.ReadAll(resp.Body)
 }
 
-func extractSchema(url string, local string) string {
+func extractSchema(url string, local string, timeout time.Duration) string {
 	// check the local version against the latest version held in our repo.
-	remoteVersion, err := getFile(url)
-	if err != nil {
+	remoteVersion, err := getFile(url, timeout)
+	if err != nil || len(remoteVersion) == 0 {
 		return local
 	}
 	remoteHash := md5.Sum(remoteVersion)
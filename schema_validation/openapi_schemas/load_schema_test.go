@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -48,9 +50,13 @@ func TestLoadSchema3_0_RemoteDifferent(t *testing.T) {
 	server := mockServer(remoteSchema, http.StatusOK)
 	defer server.Close()
 
-	// Override the remote spec URL in extractSchema function
+	url := remoteSchema3_0
+	remoteSchema3_0 = server.URL
+	defer func() { remoteSchema3_0, schema30 = url, "" }()
+
+	// The result should be the remote schema because it differs from the local schema
 	result := LoadSchema3_0(`{"title": "Local Schema 3.0"}`)
-	require.NotEqual(t, remoteSchema, result)
+	require.Equal(t, remoteSchema, result)
 }
 
 // Test LoadSchema3_0 when the remote schema is the same as the local schema
@@ -65,8 +71,13 @@ func TestLoadSchema3_0_RemoteSame(t *testing.T) {
 	server := mockServer(remoteSchema, http.StatusOK)
 	defer server.Close()
 
+	url := remoteSchema3_0
+	remoteSchema3_0 = server.URL
+	defer func() { remoteSchema3_0, schema30 = url, "" }()
+
+	// The result should be the local schema since the MD5 hashes are the same
 	result := LoadSchema3_0(localSchema)
-	require.NotEqual(t, localSchema, result)
+	require.Equal(t, localSchema, result)
 }
 
 // Test LoadSchema3_1 when the remote schema is different from the local schema
@@ -79,9 +90,13 @@ func TestLoadSchema3_1_RemoteDifferent(t *testing.T) {
 	server := mockServer(remoteSchema, http.StatusOK)
 	defer server.Close()
 
+	url := remoteSchema3_1
+	remoteSchema3_1 = server.URL
+	defer func() { remoteSchema3_1, schema31 = url, "" }()
+
 	// The result should be the remote schema because it differs from the local schema
 	result := LoadSchema3_1(`{"title": "Local Schema 3.1"}`)
-	require.NotEqual(t, remoteSchema, result)
+	require.Equal(t, remoteSchema, result)
 }
 
 // Test LoadSchema3_1 when the remote schema is the same as the local schema
@@ -96,9 +111,13 @@ func TestLoadSchema3_1_RemoteSame(t *testing.T) {
 	server := mockServer(remoteSchema, http.StatusOK)
 	defer server.Close()
 
+	url := remoteSchema3_1
+	remoteSchema3_1 = server.URL
+	defer func() { remoteSchema3_1, schema31 = url, "" }()
+
 	// The result should be the local schema since the MD5 hashes are the same
 	result := LoadSchema3_1(localSchema)
-	require.NotEqual(t, localSchema, result)
+	require.Equal(t, localSchema, result)
 }
 
 // Test extractSchema when the remote schema differs from the local schema
@@ -111,7 +130,7 @@ func TestExtractSchema_RemoteDifferent(t *testing.T) {
 	// Local schema is different from the remote schema
 	localSchema := `{"title": "Local Schema"}`
 
-	result := extractSchema(server.URL, localSchema)
+	result := extractSchema(server.URL, localSchema, time.Second)
 	require.Equal(t, remoteSchema, result)
 }
 
@@ -125,7 +144,7 @@ func TestExtractSchema_RemoteSame(t *testing.T) {
 	defer server.Close()
 
 	// Since the schemas match, the result should be the local schema
-	result := extractSchema(server.URL, localSchema)
+	result := extractSchema(server.URL, localSchema, time.Second)
 	require.Equal(t, localSchema, result)
 }
 
@@ -137,19 +156,62 @@ func TestExtractSchema_Error(t *testing.T) {
 
 	// Local schema should be returned in case of an error
 	localSchema := `{"title": "Local Schema"}`
-	result := extractSchema(server.URL, localSchema)
-	require.NotEqual(t, localSchema, result)
+	result := extractSchema(server.URL, localSchema, time.Second)
+	require.Equal(t, localSchema, result)
 }
 
 func TestGetFile_Error(t *testing.T) {
 	// Mock server to return an error
-	local, err := getFile("htttttp://981374918273")
+	local, err := getFile("htttttp://981374918273", 0)
 	assert.Error(t, err)
 	assert.Nil(t, local)
 }
 
 func TestGetSchema_Error(t *testing.T) {
 	// Mock server to return an error
-	local := extractSchema("htttttp://981374918273", "pingo")
+	local := extractSchema("htttttp://981374918273", "pingo", 0)
 	assert.Equal(t, "pingo", local)
 }
+
+func TestRefreshSchema3_1(t *testing.T) {
+	remoteSchema := `{"title": "Remote OpenAPI 3.1"}`
+	server := mockServer(remoteSchema, http.StatusOK)
+	defer server.Close()
+
+	url := remoteSchema3_1
+	remoteSchema3_1 = server.URL
+	defer func() { remoteSchema3_1 = url }()
+
+	remote31 = ""
+	assert.Equal(t, remoteSchema, RefreshSchema3_1(time.Second))
+	assert.Equal(t, remoteSchema, RefreshSchema3_1(time.Second), "the refreshed schema is used from then on")
+
+	// the schema libopenapi embeds is used when the remote schema is not fetched in time
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		_, _ = io.WriteString(w, remoteSchema)
+	}))
+	defer slow.Close()
+	remoteSchema3_1 = slow.URL
+
+	remote31 = ""
+	assert.Equal(t, datamodel.OpenAPI31SchemaData, RefreshSchema3_1(20*time.Millisecond))
+	remote31 = ""
+}
+
+func TestLoadAndRefreshSchema(t *testing.T) {
+	remoteSchema := `{"title": "Remote OpenAPI 3.0"}`
+	server := mockServer(remoteSchema, http.StatusOK)
+	defer server.Close()
+
+	url := remoteSchema3_0
+	remoteSchema3_0 = server.URL
+	defer func() { remoteSchema3_0 = url }()
+
+	schema30, remote30 = "", ""
+	defer func() { schema30, remote30 = "", "" }()
+
+	// a cached load does not stop a refresh from fetching the remote schema
+	schema30 = "cached schema 3.0"
+	assert.Equal(t, remoteSchema, RefreshSchema3_0(time.Second))
+}
@@ -7,8 +7,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"go.yaml.in/yaml/v4"
 	"golang.org/x/text/language"
@@ -17,6 +19,7 @@ import (
 	"github.com/pb33f/libopenapi-validator/config"
 	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/schema_validation/openapi_schemas"
 )
 
 func normalizeJSON(data any) any {
@@ -32,10 +35,23 @@ func ValidateOpenAPIDocument(doc libopenapi.Document, opts ...config.Option) (bo
 	options := config.NewValidationOptions(opts...)
 
 	info := doc.GetSpecInfo()
-	loadedSchema := info.APISchema
 	var validationErrors []*liberrors.ValidationError
 	decodedDocument := *info.SpecJSON
 
+	loadedSchema, err := loadOpenAPISchema(info, options)
+	if err != nil {
+		validationErrors = append(validationErrors, &liberrors.ValidationError{
+			ValidationType:    "schema",
+			ValidationSubType: "compilation",
+			Message:           "OpenAPI document schema cannot be loaded",
+			Reason:            fmt.Sprintf("The OpenAPI schema cannot be read: %s", err.Error()),
+			SpecLine:          1,
+			SpecCol:           0,
+			HowToFix:          "check the path of the OpenAPI schema file exists and can be read",
+		})
+		return false, validationErrors
+	}
+
 	// Compile the JSON Schema
 	jsch, err := helpers.NewCompiledSchema("schema", []byte(loadedSchema), options)
 	if err != nil {
@@ -135,3 +151,25 @@ func ValidateOpenAPIDocument(doc libopenapi.Document, opts ...config.Option) (bo
 	}
 	return true, nil
 }
+
+// loadOpenAPISchema returns the schema a document is validated against: the schema file of the options, the latest
+// OpenAPI 3.0 or 3.1 schema when the options ask for it, or otherwise the schema libopenapi provides for the version
+// of the document.
+func loadOpenAPISchema(info *datamodel.SpecInfo, options *config.ValidationOptions) (string, error) {
+	if options.OpenAPISchemaPath != "" {
+		schema, err := os.ReadFile(options.OpenAPISchemaPath)
+		if err != nil {
+			return "", err
+		}
+		return string(schema), nil
+	}
+	if options.RefreshOpenAPISchemas {
+		switch info.SpecFormat {
+		case datamodel.OAS3:
+			return openapi_schemas.RefreshSchema3_0(options.OpenAPISchemaRefreshTimeout), nil
+		case datamodel.OAS31:
+			return openapi_schemas.RefreshSchema3_1(options.OpenAPISchemaRefreshTimeout), nil
+		}
+	}
+	return info.APISchema, nil
+}
@@ -175,3 +175,23 @@ info:
 	assert.Len(t, errors, 1)
 	assert.Len(t, errors[0].SchemaValidationErrors, 6)
 }
+
+func TestValidateDocument_OpenAPISchemaPath(t *testing.T) {
+	petstore, _ := os.ReadFile("../test_specs/valid_31.yaml")
+	doc, _ := libopenapi.NewDocument(petstore)
+
+	// a schema file replaces the schema libopenapi provides
+	schemaPath := t.TempDir() + "/schema.json"
+	assert.NoError(t, os.WriteFile(schemaPath, []byte(`{"type": "object", "required": ["x-burger"]}`), 0o600))
+
+	valid, errors := ValidateOpenAPIDocument(doc, config.WithOpenAPISchemaPath(schemaPath))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Len(t, errors[0].SchemaValidationErrors, 1)
+	assert.Contains(t, errors[0].SchemaValidationErrors[0].Reason, "x-burger")
+
+	valid, errors = ValidateOpenAPIDocument(doc, config.WithOpenAPISchemaPath(schemaPath+".missing"))
+	assert.False(t, valid)
+	assert.Len(t, errors, 1)
+	assert.Equal(t, "OpenAPI document schema cannot be loaded", errors[0].Message)
+}
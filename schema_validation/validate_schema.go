@@ -157,6 +157,7 @@ func (s *schemaValidator) validateSchemaWithVersion(schema *base.Schema, payload
 	s.lock.Unlock()
 
 	jsonSchema, _ := utils.ConvertYAMLtoJSON(renderedSchema)
+	jsonSchema = helpers.AnnotateDiscriminators(schema, jsonSchema)
 
 	if decodedObject == nil && len(payload) > 0 {
 		err := json.Unmarshal(payload, &decodedObject)
@@ -4,15 +4,14 @@
 package schema_validation
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"strings"
 
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 
-	xj "github.com/basgys/goxml2json"
-
 	liberrors "github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
 )
@@ -26,11 +25,16 @@ func (x *xmlValidator) validateXMLWithVersion(schema *base.Schema, xmlString str
 	}
 
 	// parse xml and transform to json structure matching schema
-	transformedJSON, err := transformXMLToSchemaJSON(xmlString, schema)
+	decoder, transformedJSON, err := decodeXMLDocument(xmlString, schema)
 	if err != nil {
+		location := "/"
+		var xmlErr *XMLError
+		if errors.As(err, &xmlErr) {
+			location = xmlErr.Location
+		}
 		violation := &liberrors.SchemaValidationFailure{
 			Reason:          err.Error(),
-			Location:        "xml parsing",
+			Location:        location,
 			ReferenceSchema: "",
 			ReferenceObject: xmlString,
 		}
@@ -45,129 +49,429 @@ func (x *xmlValidator) validateXMLWithVersion(schema *base.Schema, xmlString str
 		return false, validationErrors
 	}
 
+	// nodes that do not follow the xml objects of the schema (names, namespaces, items) are reported first.
+	if len(decoder.issues) > 0 {
+		violations := make([]*liberrors.SchemaValidationFailure, len(decoder.issues))
+		for i, issue := range decoder.issues {
+			violations[i] = &liberrors.SchemaValidationFailure{
+				Reason:          issue.Reason,
+				Location:        issue.Location,
+				ReferenceObject: xmlString,
+			}
+		}
+		validationErrors = append(validationErrors, &liberrors.ValidationError{
+			ValidationType:         helpers.Schema,
+			ValidationSubType:      helpers.Schema,
+			Message:                "xml does not match the xml objects of the schema",
+			Reason:                 decoder.issues.Error(),
+			SchemaValidationErrors: violations,
+			HowToFix:               "ensure the names and namespaces of xml elements and attributes match the schema",
+		})
+	}
+
 	// validate transformed json against schema using existing validator
-	return x.schemaValidator.validateSchemaWithVersion(schema, nil, transformedJSON, log, version)
+	_, schemaErrors := x.schemaValidator.validateSchemaWithVersion(schema, nil, transformedJSON, log, version)
+
+	// point schema violations at the xml nodes their values were read from.
+	for _, schemaError := range schemaErrors {
+		for _, violation := range schemaError.SchemaValidationErrors {
+			if location, ok := decoder.locations[violation.Location]; ok {
+				violation.Location = location
+			}
+		}
+	}
+	validationErrors = append(validationErrors, schemaErrors...)
+	if len(validationErrors) > 0 {
+		return false, validationErrors
+	}
+	return true, nil
+}
+
+// DecodeXML parses an xml document into an object that can be validated against the schema, applying the openapi
+// xml object transformations (see transformXMLToSchemaJSON). It is used to validate xml request and response bodies.
+//
+// The error is an *XMLError when the document is malformed, or XMLErrors listing every node that does not follow
+// the xml objects of the schema.
+func DecodeXML(xmlString string, schema *base.Schema) (interface{}, error) {
+	return transformXMLToSchemaJSON(xmlString, schema)
 }
 
 // transformXMLToSchemaJSON converts xml to json structure matching openapi schema.
-// applies xml object transformations: name, attribute, wrapped.
+// applies xml object transformations: name, namespace, prefix, attribute, wrapped and nodeType.
 func transformXMLToSchemaJSON(xmlString string, schema *base.Schema) (interface{}, error) {
-	if xmlString == "" {
-		return nil, fmt.Errorf("empty xml content")
+	decoder, transformed, err := decodeXMLDocument(xmlString, schema)
+	if err != nil {
+		return nil, err
 	}
+	if len(decoder.issues) > 0 {
+		return transformed, decoder.issues
+	}
+	return transformed, nil
+}
 
-	// parse xml using goxml2json with type conversion for numbers only
-	// note: we convert floats and ints, but not booleans, since xml content
-	// may legitimately contain "true"/"false" as string values
-	jsonBuf, err := xj.Convert(strings.NewReader(xmlString), xj.WithTypeConverter(xj.Float, xj.Int))
+// decodeXMLDocument parses an xml document, and decodes its root element against the schema.
+func decodeXMLDocument(xmlString string, schema *base.Schema) (*xmlDecoder, interface{}, error) {
+	if strings.TrimSpace(xmlString) == "" {
+		return nil, nil, &XMLError{Location: "/", Reason: "empty xml content"}
+	}
+	root, err := parseXMLDocument(xmlString)
 	if err != nil {
-		return nil, fmt.Errorf("malformed xml: %w", err)
+		return nil, nil, err
 	}
 
-	// decode to interface{}
-	var rawJSON interface{}
-	if err := json.Unmarshal(jsonBuf.Bytes(), &rawJSON); err != nil {
-		return nil, fmt.Errorf("failed to decode json: %w", err)
+	decoder := &xmlDecoder{locations: make(map[string]string)}
+	if schema != nil && schema.XML != nil {
+		if schema.XML.Name != "" && root.local != schema.XML.Name {
+			decoder.issue(root.xpath(), "the root element is '%s', the schema expects '%s'", root.local, schema.XML.Name)
+		}
+		decoder.checkNamespace(root.xpath(), schema.XML, root.prefix, root.namespace)
 	}
+	return decoder, decoder.decodeElement(root, schema, ""), nil
+}
 
-	// apply openapi xml object transformations
-	transformed := applyXMLTransformations(rawJSON, schema)
-	return transformed, nil
+// xmlDecoder converts the elements of an xml document into values shaped by their schemas, following the openapi
+// xml object:
+//
+//   - name replaces the name of the element or attribute of a property (or the items of an array).
+//   - namespace and prefix are checked against the namespace each element or attribute is in.
+//   - attribute (or nodeType attribute) reads a property from an attribute of the element of its object.
+//   - wrapped (or nodeType element) arrays have an element that wraps their items, other arrays do not.
+//   - nodeType text and cdata read a property from the text content of the element of its object, none reads an
+//     object from the element of its parent.
+//
+// Properties of allOf schemas are merged into their object, references are followed by the schema proxies. Values
+// are converted to the type of their schema, and every value records the XPath of the node it was read from.
+type xmlDecoder struct {
+	locations map[string]string // json pointer of a decoded value, to the XPath of its node
+	issues    XMLErrors
 }
 
-// applyXMLTransformations applies openapi xml object rules to match json schema.
-// handles xml.name (root unwrapping), xml.attribute (dash prefix), xml.wrapped (array unwrapping).
-func applyXMLTransformations(data interface{}, schema *base.Schema) interface{} {
-	if schema == nil {
-		return data
-	}
+func (d *xmlDecoder) issue(location, format string, args ...any) {
+	d.issues = append(d.issues, &XMLError{Location: location, Reason: fmt.Sprintf(format, args...)})
+}
 
-	// unwrap root element if xml.name is set on schema
-	if schema.XML != nil && schema.XML.Name != "" {
-		if dataMap, ok := data.(map[string]interface{}); ok {
-			if wrapped, exists := dataMap[schema.XML.Name]; exists {
-				data = wrapped
-			}
+// checkNamespace checks the namespace of a node against its xml object. The prefix is only checked when the xml
+// object has no namespace, otherwise any prefix bound to the namespace will do.
+func (d *xmlDecoder) checkNamespace(location string, x *base.XML, prefix, namespace string) {
+	if x == nil {
+		return
+	}
+	if x.Namespace != "" && namespace != x.Namespace {
+		found := "no namespace"
+		if namespace != "" {
+			found = fmt.Sprintf("namespace '%s'", namespace)
 		}
+		d.issue(location, "the node is in %s, the schema expects namespace '%s'", found, x.Namespace)
+		return
+	}
+	if x.Namespace == "" && x.Prefix != "" && prefix != x.Prefix {
+		d.issue(location, "the node has prefix '%s', the schema expects prefix '%s'", prefix, x.Prefix)
 	}
+}
 
-	// transform properties based on their xml configurations
-	if dataMap, ok := data.(map[string]interface{}); ok {
-		if schema.Properties == nil || schema.Properties.Len() == 0 {
-			return data
-		}
+func (d *xmlDecoder) decodeElement(el *xmlElement, sch *base.Schema, pointer string) any {
+	d.locations[pointer] = el.xpath()
+	switch xmlSchemaKind(sch) {
+	case helpers.Object:
+		return d.decodeObject(el, sch, pointer, make(map[*xmlElement]bool))
+	case helpers.Array:
+		return d.decodeItems(el, sch, "", pointer)
+	case xmlPrimitive:
+		return helpers.CastValueForSchema(strings.TrimSpace(el.text.String()), sch)
+	}
+	return d.decodeAny(el, pointer)
+}
 
-		transformed := make(map[string]interface{}, schema.Properties.Len())
+// decodeObject decodes the properties of an object from an element, the children it reads are marked as consumed,
+// children that are not properties are decoded as they are, so additionalProperties still applies to them.
+func (d *xmlDecoder) decodeObject(el *xmlElement, sch *base.Schema, pointer string,
+	consumed map[*xmlElement]bool,
+) map[string]any {
+	object := make(map[string]any)
+	for _, prop := range xmlProperties(sch) {
+		propSchema := prop.proxy.Schema()
+		if propSchema == nil {
+			continue
+		}
+		propPointer := pointer + "/" + escapeJSONPointer(prop.name)
+		name := prop.name
+		if propSchema.XML != nil && propSchema.XML.Name != "" {
+			name = propSchema.XML.Name
+		}
 
-		for pair := schema.Properties.First(); pair != nil; pair = pair.Next() {
-			propName := pair.Key()
-			propSchemaProxy := pair.Value()
-			propSchema := propSchemaProxy.Schema()
-			if propSchema == nil {
+		switch xmlNodeType(propSchema) {
+		case xmlNodeAttribute:
+			attr := el.attribute(name)
+			if attr == nil {
 				continue
 			}
-
-			// determine xml element name (defaults to property name)
-			xmlName := propName
-			if propSchema.XML != nil && propSchema.XML.Name != "" {
-				xmlName = propSchema.XML.Name
+			location := el.xpath() + "/@" + attr.local
+			if attr.prefix != "" {
+				location = el.xpath() + "/@" + attr.prefix + ":" + attr.local
 			}
+			d.checkNamespace(location, propSchema.XML, attr.prefix, attr.namespace)
+			d.locations[propPointer] = location
+			object[prop.name] = castXMLValue(attr.value, propSchema)
+
+		case xmlNodeText, xmlNodeCData:
+			d.locations[propPointer] = el.xpath() + "/text()"
+			object[prop.name] = castXMLValue(strings.TrimSpace(el.text.String()), propSchema)
 
-			// handle xml.attribute: true - attributes are prefixed with dash
-			if propSchema.XML != nil && propSchema.XML.Attribute {
-				attrKey := "-" + xmlName
-				if val, exists := dataMap[attrKey]; exists {
-					transformed[propName] = val
+		case xmlNodeNone:
+			if xmlSchemaKind(propSchema) == helpers.Array {
+				items := el.childrenNamed(xmlItemName(propSchema, name))
+				if len(items) == 0 {
 					continue
 				}
-			}
-
-			// handle regular elements
-			if val, exists := dataMap[xmlName]; exists {
-				// handle wrapped arrays: unwrap container element
-				if len(propSchema.Type) > 0 && propSchema.Type[0] == "array" &&
-					propSchema.XML != nil && propSchema.XML.Wrapped {
-					val = unwrapArrayElement(val, propSchema)
+				for _, item := range items {
+					consumed[item] = true
 				}
+				d.locations[propPointer] = el.xpath()
+				object[prop.name] = d.decodeItemElements(items, propSchema, propPointer)
+				continue
+			}
+			d.locations[propPointer] = el.xpath()
+			if xmlSchemaKind(propSchema) == helpers.Object {
+				object[prop.name] = d.decodeObject(el, propSchema, propPointer, consumed)
+				continue
+			}
+			object[prop.name] = castXMLValue(strings.TrimSpace(el.text.String()), propSchema)
 
-				transformed[propName] = val
+		default:
+			children := el.childrenNamed(name)
+			// a referenced schema may carry the name of its own element, the property name is used as well.
+			if len(children) == 0 && name != prop.name && prop.proxy.IsReference() {
+				children = el.childrenNamed(prop.name)
+			}
+			if len(children) == 0 {
+				continue
 			}
+			child := children[0]
+			consumed[child] = true
+			d.checkNamespace(child.xpath(), propSchema.XML, child.prefix, child.namespace)
+			if xmlSchemaKind(propSchema) == helpers.Array {
+				// the element wraps the items of the array.
+				d.locations[propPointer] = child.xpath()
+				object[prop.name] = d.decodeItems(child, propSchema, "", propPointer)
+				continue
+			}
+			object[prop.name] = d.decodeElement(child, propSchema, propPointer)
+		}
+	}
+
+	var unknown []string
+	groups := make(map[string][]*xmlElement)
+	for _, child := range el.children {
+		if consumed[child] {
+			continue
+		}
+		if _, ok := object[child.local]; ok {
+			continue
 		}
+		if _, ok := groups[child.local]; !ok {
+			unknown = append(unknown, child.local)
+		}
+		groups[child.local] = append(groups[child.local], child)
+	}
+	for _, name := range unknown {
+		object[name] = d.decodeAnyGroup(groups[name], pointer+"/"+escapeJSONPointer(name))
+	}
+	return object
+}
 
-		return transformed
+// decodeItems decodes the items of an array from the children of the element that wraps them. Items are the
+// children named by the xml object of the items schema, any child is an item when it has no name. Children that
+// are not items do not follow the schema.
+func (d *xmlDecoder) decodeItems(wrapper *xmlElement, sch *base.Schema, fallback string, pointer string) []any {
+	itemName := xmlItemName(sch, fallback)
+	for _, child := range wrapper.children {
+		if itemName != "" && child.local != itemName {
+			d.issue(child.xpath(), "the element '%s' is not an item of the array, items are named '%s'",
+				child.local, itemName)
+		}
 	}
+	return d.decodeItemElements(wrapper.childrenNamed(itemName), sch, pointer)
+}
 
-	return data
+func (d *xmlDecoder) decodeItemElements(items []*xmlElement, sch *base.Schema, pointer string) []any {
+	itemSchema := xmlItemsSchema(sch)
+	values := make([]any, 0, len(items))
+	for i, item := range items {
+		if itemSchema != nil {
+			d.checkNamespace(item.xpath(), itemSchema.XML, item.prefix, item.namespace)
+		}
+		values = append(values, d.decodeElement(item, itemSchema, fmt.Sprintf("%s/%d", pointer, i)))
+	}
+	return values
 }
 
-// unwrapArrayElement removes wrapping element from xml arrays when xml.wrapped is true.
-// example: {"items": {"item": [...]}} becomes [...]
-func unwrapArrayElement(val interface{}, propSchema *base.Schema) interface{} {
-	wrapMap, ok := val.(map[string]interface{})
-	if !ok {
-		return val
+// decodeAny decodes an element without a schema: elements without children are scalars, others are objects keyed
+// by the names of their children, children that share a name are arrays.
+func (d *xmlDecoder) decodeAny(el *xmlElement, pointer string) any {
+	d.locations[pointer] = el.xpath()
+	if len(el.children) == 0 {
+		return castXMLValue(strings.TrimSpace(el.text.String()), nil)
+	}
+	var names []string
+	groups := make(map[string][]*xmlElement)
+	for _, child := range el.children {
+		if _, ok := groups[child.local]; !ok {
+			names = append(names, child.local)
+		}
+		groups[child.local] = append(groups[child.local], child)
+	}
+	object := make(map[string]any, len(names))
+	for _, name := range names {
+		object[name] = d.decodeAnyGroup(groups[name], pointer+"/"+escapeJSONPointer(name))
 	}
+	return object
+}
 
-	// determine item element name
-	itemName := "item"
-	if propSchema.Items != nil && propSchema.Items.A != nil {
-		itemSchema := propSchema.Items.A.Schema()
-		if itemSchema != nil && itemSchema.XML != nil && itemSchema.XML.Name != "" {
-			itemName = itemSchema.XML.Name
+func (d *xmlDecoder) decodeAnyGroup(group []*xmlElement, pointer string) any {
+	if len(group) == 1 {
+		return d.decodeAny(group[0], pointer)
+	}
+	values := make([]any, len(group))
+	for i, el := range group {
+		values[i] = d.decodeAny(el, fmt.Sprintf("%s/%d", pointer, i))
+	}
+	return values
+}
+
+const (
+	xmlPrimitive = "primitive"
+
+	xmlNodeElement   = "element"
+	xmlNodeAttribute = "attribute"
+	xmlNodeText      = "text"
+	xmlNodeCData     = "cdata"
+	xmlNodeNone      = "none"
+)
+
+// castXMLValue converts the text of a node to the type of its schema. Without a type, numbers are converted and
+// anything else is left as a string, booleans included, as xml text may legitimately be "true" or "false".
+func castXMLValue(value string, sch *base.Schema) any {
+	if xmlSchemaKind(sch) == xmlPrimitive {
+		return helpers.CastValueForSchema(value, sch)
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
+
+// xmlNodeType returns the kind of xml node a schema is serialized as. The nodeType of OpenAPI 3.2 wins, otherwise
+// attribute and wrapped decide, arrays are not wrapped by default.
+func xmlNodeType(sch *base.Schema) string {
+	isArray := xmlSchemaKind(sch) == helpers.Array
+	if sch.XML != nil {
+		switch {
+		case sch.XML.NodeType != "":
+			return sch.XML.NodeType
+		case sch.XML.Attribute:
+			return xmlNodeAttribute
+		case sch.XML.Wrapped && isArray:
+			return xmlNodeElement
 		}
 	}
+	if isArray {
+		return xmlNodeNone
+	}
+	return xmlNodeElement
+}
 
-	// unwrap: look for item element inside wrapper
-	if unwrapped, exists := wrapMap[itemName]; exists {
-		return unwrapped
+// xmlSchemaKind returns object or array for schemas of those types (or with properties or items), primitive for
+// other typed schemas, and an empty string for schemas without a type. The schemas of allOf are included.
+func xmlSchemaKind(sch *base.Schema) string {
+	kind := ""
+	for _, s := range xmlAllOf(sch) {
+		for _, typ := range s.Type {
+			if typ == helpers.Object || typ == helpers.Array {
+				return typ
+			}
+			if typ != "null" {
+				kind = xmlPrimitive
+			}
+		}
+		if s.Properties != nil && s.Properties.Len() > 0 {
+			return helpers.Object
+		}
+		if s.Items != nil && s.Items.IsA() {
+			return helpers.Array
+		}
+	}
+	return kind
+}
+
+type xmlProperty struct {
+	name  string
+	proxy *base.SchemaProxy
+}
+
+// xmlProperties returns the properties of an object schema, followed by those of its allOf schemas.
+func xmlProperties(sch *base.Schema) []xmlProperty {
+	var props []xmlProperty
+	seen := make(map[string]bool)
+	for _, s := range xmlAllOf(sch) {
+		if s.Properties == nil {
+			continue
+		}
+		for name, proxy := range s.Properties.FromOldest() {
+			if proxy != nil && !seen[name] {
+				seen[name] = true
+				props = append(props, xmlProperty{name: name, proxy: proxy})
+			}
+		}
+	}
+	return props
+}
+
+// xmlAllOf returns a schema and every schema it is composed of through allOf, each schema is only visited once.
+func xmlAllOf(sch *base.Schema) []*base.Schema {
+	if sch == nil {
+		return nil
 	}
+	schemas := []*base.Schema{sch}
+	seen := map[*base.Schema]bool{sch: true}
+	for i := 0; i < len(schemas); i++ {
+		for _, proxy := range schemas[i].AllOf {
+			if s := proxy.Schema(); s != nil && !seen[s] {
+				seen[s] = true
+				schemas = append(schemas, s)
+			}
+		}
+	}
+	return schemas
+}
+
+func xmlItemsSchema(sch *base.Schema) *base.Schema {
+	for _, s := range xmlAllOf(sch) {
+		if s.Items != nil && s.Items.IsA() && s.Items.A != nil {
+			return s.Items.A.Schema()
+		}
+	}
+	return nil
+}
+
+// xmlItemName returns the element name of the items of an array, from the xml object of its items schema.
+func xmlItemName(sch *base.Schema, fallback string) string {
+	if items := xmlItemsSchema(sch); items != nil && items.XML != nil && items.XML.Name != "" {
+		return items.XML.Name
+	}
+	return fallback
+}
 
-	return val
+func escapeJSONPointer(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
 }
 
-// IsXMLContentType checks if a media type string represents xml content.
+// IsXMLContentType checks if a media type string represents xml content. Any parameters (e.g. charset) are ignored.
 func IsXMLContentType(mediaType string) bool {
-	mt := strings.ToLower(strings.TrimSpace(mediaType))
+	mt, _, _ := strings.Cut(mediaType, ";")
+	mt = strings.ToLower(strings.TrimSpace(mt))
 	return strings.HasPrefix(mt, "application/xml") ||
 		strings.HasPrefix(mt, "text/xml") ||
 		strings.HasSuffix(mt, "+xml")
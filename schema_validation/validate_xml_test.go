@@ -750,29 +750,21 @@ func TestValidateXML_NilSchema(t *testing.T) {
 }
 
 func TestValidateXML_NilSchemaInTransformation(t *testing.T) {
-	// directly test applyXMLTransformations with nil schema (line 94)
-	result := applyXMLTransformations(map[string]interface{}{"test": "value"}, nil)
-	assert.NotNil(t, result)
-	assert.Equal(t, map[string]interface{}{"test": "value"}, result)
+	// without a schema, the children of the root element are decoded as they are
+	result, err := transformXMLToSchemaJSON("<Test><test>value</test><count>2</count></Test>", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"test": "value", "count": int64(2)}, result)
 }
 
 func TestValidateXML_TransformWithNilPropertySchemaProxy(t *testing.T) {
-	// directly test applyXMLTransformations when a property schema proxy returns nil (line 119)
-	// this can happen with circular refs or unresolved refs in edge cases
-
-	// create a schema with properties but we'll simulate a nil schema scenario
-	// by testing the transformation directly
-	data := map[string]interface{}{
-		"test": "value",
-	}
-
-	// schema with properties but no XML config - tests property iteration
+	// schema with no properties, the element is decoded as it is
 	schema := &base.Schema{
-		Properties: nil, // will trigger line 109 early return
+		Properties: nil,
 	}
 
-	result := applyXMLTransformations(data, schema)
-	assert.Equal(t, data, result)
+	result, err := transformXMLToSchemaJSON("<Test><test>value</test></Test>", schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"test": "value"}, result)
 }
 
 func TestValidateXML_NoProperties(t *testing.T) {
@@ -922,39 +914,40 @@ paths:
 }
 
 func TestValidateXML_DirectArrayValue(t *testing.T) {
-	// test unwrapArrayElement with non-map value (line 160)
+	// a root array is the element that wraps its items, which have no name, so every child is an item
 	schema := &base.Schema{
 		Type: []string{"array"},
 		Items: &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: &base.SchemaProxy{},
+			A: base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}}),
 		},
 		XML: &base.XML{
 			Wrapped: true,
 		},
 	}
 
-	// when val is already an array (not a map), it should return as-is
-	arrayVal := []interface{}{"one", "two", "three"}
-	result := unwrapArrayElement(arrayVal, schema)
-	assert.Equal(t, arrayVal, result)
+	result, err := transformXMLToSchemaJSON("<list><a>one</a><b>two</b><a>three</a></list>", schema)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"one", "two", "three"}, result)
 }
 
 func TestValidateXML_UnwrapArrayElementMissingItem(t *testing.T) {
-	// test unwrapArrayElement when wrapper map doesn't contain expected item (line 177)
+	// the wrapper contains elements that are not named as the items, each one is reported
 	schema := &base.Schema{
 		Type: []string{"array"},
 		Items: &base.DynamicValue[*base.SchemaProxy, bool]{
-			A: &base.SchemaProxy{},
+			A: base.CreateSchemaProxy(&base.Schema{Type: []string{"string"}, XML: &base.XML{Name: "item"}}),
 		},
 		XML: &base.XML{
 			Wrapped: true,
 		},
 	}
 
-	// wrapper map contains wrong key - should return map as-is (line 177)
-	wrapperMap := map[string]interface{}{"wrongKey": []interface{}{"one", "two"}}
-	result := unwrapArrayElement(wrapperMap, schema)
-	assert.Equal(t, wrapperMap, result)
+	result, err := transformXMLToSchemaJSON("<list><wrongKey>one</wrongKey><wrongKey>two</wrongKey></list>", schema)
+	var xmlErrs XMLErrors
+	assert.ErrorAs(t, err, &xmlErrs)
+	assert.Len(t, xmlErrs, 2)
+	assert.Equal(t, "/list/wrongKey[2]", xmlErrs[1].Location)
+	assert.Equal(t, []interface{}{}, result)
 }
 
 func TestTransformXMLToSchemaJSON_EmptyString(t *testing.T) {
@@ -966,13 +959,13 @@ func TestTransformXMLToSchemaJSON_EmptyString(t *testing.T) {
 }
 
 func TestApplyXMLTransformations_NoXMLName(t *testing.T) {
-	// test schema without xml.name - data stays wrapped
+	// without an xml name, any root element is accepted
 	schema := &base.Schema{
 		Properties: nil,
 	}
-	data := map[string]interface{}{"Cat": map[string]interface{}{"nice": "true"}}
-	result := applyXMLTransformations(data, schema)
-	assert.Equal(t, data, result)
+	result, err := transformXMLToSchemaJSON("<Cat><nice>true</nice></Cat>", schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"nice": "true"}, result)
 }
 
 func TestIsXMLContentType(t *testing.T) {
@@ -997,3 +990,284 @@ func TestIsXMLContentType(t *testing.T) {
 		})
 	}
 }
+
+func TestIsXMLContentType_Parameters(t *testing.T) {
+	assert.True(t, IsXMLContentType("application/xml; charset=utf-8"))
+	assert.True(t, IsXMLContentType("application/atom+xml;charset=utf-8"))
+	assert.False(t, IsXMLContentType("application/json; charset=utf-8"))
+}
+
+func TestDecodeXML(t *testing.T) {
+	schema := &base.Schema{
+		Type: []string{"object"},
+		XML:  &base.XML{Name: "cat"},
+	}
+	decoded, err := DecodeXML(`<cat><nice>true</nice></cat>`, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"nice": "true"}, decoded)
+
+	_, err = DecodeXML("", schema)
+	assert.Error(t, err)
+}
+
+func TestValidateXML_NamespacesAndPrefixes(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /message:
+    get:
+      responses:
+        '200':
+          content:
+            application/xml:
+              schema:
+                type: object
+                xml:
+                  name: Message
+                  namespace: http://example.com/message
+                  prefix: msg
+                properties:
+                  lang:
+                    type: string
+                    xml:
+                      attribute: true
+                      namespace: http://example.com/meta
+                  subject:
+                    type: string
+                    xml:
+                      namespace: http://example.com/message
+                  body:
+                    type: string
+                    xml:
+                      prefix: b`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	assert.NoError(t, err)
+	v3Doc, err := doc.BuildV3Model()
+	assert.NoError(t, err)
+	schema := v3Doc.Model.Paths.PathItems.GetOrZero("/message").Get.Responses.Codes.GetOrZero("200").
+		Content.GetOrZero("application/xml").Schema.Schema()
+
+	validator := NewXMLValidator()
+
+	// any prefix bound to the namespace will do, as will the default namespace
+	for _, valid := range []string{
+		`<msg:Message xmlns:msg="http://example.com/message" xmlns:m="http://example.com/meta" m:lang="en">` +
+			`<msg:subject>Hello</msg:subject><b:body xmlns:b="urn:body">World</b:body></msg:Message>`,
+		`<Message xmlns="http://example.com/message"><subject>Hello</subject></Message>`,
+	} {
+		ok, errs := validator.ValidateXMLString(schema, valid)
+		assert.True(t, ok, valid)
+		assert.Empty(t, errs, valid)
+	}
+
+	ok, errs := validator.ValidateXMLString(schema,
+		`<msg:Message xmlns:msg="http://example.com/other" xmlns:m="urn:wrong" m:lang="en">`+
+			`<subject>Hello</subject><body>World</body></msg:Message>`)
+	assert.False(t, ok)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "xml does not match the xml objects of the schema", errs[0].Message)
+		var locations []string
+		for _, failure := range errs[0].SchemaValidationErrors {
+			locations = append(locations, failure.Location)
+		}
+		assert.Equal(t, []string{"/msg:Message", "/msg:Message/@m:lang", "/msg:Message/subject", "/msg:Message/body"},
+			locations)
+		assert.Contains(t, errs[0].SchemaValidationErrors[0].Reason, "namespace 'http://example.com/other'")
+		assert.Contains(t, errs[0].SchemaValidationErrors[2].Reason, "no namespace")
+		assert.Contains(t, errs[0].SchemaValidationErrors[3].Reason, "prefix 'b'")
+	}
+
+	// prefixes must be declared
+	ok, errs = validator.ValidateXMLString(schema, `<msg:Message><msg:subject>Hello</msg:subject></msg:Message>`)
+	assert.False(t, ok)
+	if assert.Len(t, errs, 1) {
+		assert.Equal(t, "/msg:Message", errs[0].SchemaValidationErrors[0].Location)
+		assert.Contains(t, errs[0].Reason, "the namespace prefix 'msg' is not declared")
+	}
+}
+
+func TestValidateXML_NestedArraysAndComposition(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /orders:
+    get:
+      responses:
+        '200':
+          content:
+            application/xml:
+              schema:
+                $ref: '#/components/schemas/Orders'
+components:
+  schemas:
+    Orders:
+      type: object
+      xml:
+        name: orders
+      properties:
+        order:
+          type: array
+          items:
+            $ref: '#/components/schemas/Order'
+    Order:
+      allOf:
+        - $ref: '#/components/schemas/Entity'
+        - type: object
+          required: [lines]
+          properties:
+            lines:
+              type: array
+              xml:
+                wrapped: true
+              items:
+                type: object
+                xml:
+                  name: line
+                required: [sku]
+                properties:
+                  sku:
+                    type: string
+                  quantity:
+                    type: integer
+                    minimum: 1
+            paid:
+              type: boolean
+    Entity:
+      type: object
+      required: [id]
+      properties:
+        id:
+          type: integer
+          xml:
+            attribute: true`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	assert.NoError(t, err)
+	v3Doc, err := doc.BuildV3Model()
+	assert.NoError(t, err)
+	schema := v3Doc.Model.Paths.PathItems.GetOrZero("/orders").Get.Responses.Codes.GetOrZero("200").
+		Content.GetOrZero("application/xml").Schema.Schema()
+
+	validator := NewXMLValidator()
+
+	// a single order is still an array, and a boolean is a boolean
+	decoded, err := DecodeXML(`<orders><order id="1"><lines><line><sku>abc</sku><quantity>2</quantity></line>`+
+		`</lines><paid>true</paid></order></orders>`, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"order": []interface{}{map[string]interface{}{
+		"id":    int64(1),
+		"lines": []interface{}{map[string]interface{}{"sku": "abc", "quantity": int64(2)}},
+		"paid":  true,
+	}}}, decoded)
+
+	ok, errs := validator.ValidateXMLString(schema, `<orders>`+
+		`<order id="1"><lines><line><sku>abc</sku></line></lines></order>`+
+		`<order id="2"><lines><line><sku>def</sku></line><line><sku>ghi</sku><quantity>0</quantity></line></lines></order>`+
+		`</orders>`)
+	assert.False(t, ok)
+	if assert.Len(t, errs, 1) && assert.Len(t, errs[0].SchemaValidationErrors, 1) {
+		failure := errs[0].SchemaValidationErrors[0]
+		assert.Equal(t, "/orders/order[2]/lines/line[2]/quantity", failure.Location)
+		assert.Equal(t, "$.order[1].lines[1].quantity", failure.FieldPath)
+	}
+
+	// missing required values are located at the element that should contain them
+	ok, errs = validator.ValidateXMLString(schema, `<orders><order><lines/></order></orders>`)
+	assert.False(t, ok)
+	if assert.Len(t, errs, 1) && assert.Len(t, errs[0].SchemaValidationErrors, 1) {
+		assert.Equal(t, "/orders/order", errs[0].SchemaValidationErrors[0].Location)
+	}
+
+	// the root element must have the name of the schema
+	ok, errs = validator.ValidateXMLString(schema, `<order id="1"/>`)
+	assert.False(t, ok)
+	if assert.NotEmpty(t, errs) {
+		assert.Equal(t, "/order", errs[0].SchemaValidationErrors[0].Location)
+		assert.Contains(t, errs[0].SchemaValidationErrors[0].Reason, "the schema expects 'orders'")
+	}
+}
+
+func TestValidateXML_NodeType(t *testing.T) {
+	spec := `openapi: 3.2.0
+paths:
+  /prices:
+    get:
+      responses:
+        '200':
+          content:
+            application/xml:
+              schema:
+                type: object
+                xml:
+                  name: price
+                properties:
+                  currency:
+                    type: string
+                    enum: [EUR, USD]
+                    xml:
+                      nodeType: attribute
+                  amount:
+                    type: number
+                    xml:
+                      nodeType: text
+                  tags:
+                    type: array
+                    xml:
+                      nodeType: element
+                    items:
+                      type: string
+                      xml:
+                        name: tag
+                  audit:
+                    type: object
+                    xml:
+                      nodeType: none
+                    properties:
+                      createdBy:
+                        type: string
+                      createdAt:
+                        type: integer`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	assert.NoError(t, err)
+	v3Doc, err := doc.BuildV3Model()
+	assert.NoError(t, err)
+	schema := v3Doc.Model.Paths.PathItems.GetOrZero("/prices").Get.Responses.Codes.GetOrZero("200").
+		Content.GetOrZero("application/xml").Schema.Schema()
+
+	decoded, err := DecodeXML(`<price currency="EUR">9.99<tags><tag>sale</tag></tags>`+
+		`<createdBy>dave</createdBy><createdAt>1700000000</createdAt></price>`, schema)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"currency": "EUR",
+		"amount":   9.99,
+		"tags":     []interface{}{"sale"},
+		"audit":    map[string]interface{}{"createdBy": "dave", "createdAt": int64(1700000000)},
+	}, decoded)
+
+	validator := NewXMLValidator()
+	ok, errs := validator.ValidateXMLString(schema, `<price currency="GBP"><![CDATA[free]]></price>`)
+	assert.False(t, ok)
+	if assert.Len(t, errs, 1) {
+		var locations []string
+		for _, failure := range errs[0].SchemaValidationErrors {
+			locations = append(locations, failure.Location)
+		}
+		assert.ElementsMatch(t, []string{"/price/@currency", "/price/text()"}, locations)
+	}
+}
+
+func TestValidateXML_MalformedLocations(t *testing.T) {
+	for _, tc := range []struct{ xml, location, reason string }{
+		{`<burger><name>Big Mac`, "/burger/name", "is not closed"},
+		{`<burger><name>Big Mac</patties></burger>`, "/burger/name", "unexpected end element </patties>"},
+		{`<burger/><burger/>`, "/", "more than one root element"},
+		{`<burger><name a=1/></burger>`, "/burger", "malformed xml"},
+	} {
+		_, err := DecodeXML(tc.xml, nil)
+		var xmlErr *XMLError
+		if assert.ErrorAs(t, err, &xmlErr, tc.xml) {
+			assert.Equal(t, tc.location, xmlErr.Location, tc.xml)
+			assert.Contains(t, xmlErr.Reason, tc.reason, tc.xml)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package schema_validation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const xmlnsAttribute = "xmlns"
+
+// XMLError is a problem with an xml document: it is malformed, or it does not follow the xml objects of the schema
+// it is validated against. The location is an XPath expression that selects the offending node.
+type XMLError struct {
+	Location string // XPath of the node, e.g. /burger/toppings/topping[2] or /burger/@id
+	Reason   string
+}
+
+func (e *XMLError) Error() string {
+	return fmt.Sprintf("%s (at '%s')", e.Reason, e.Location)
+}
+
+// XMLErrors lists every node of an xml document that does not follow the xml objects of its schema.
+type XMLErrors []*XMLError
+
+func (e XMLErrors) Error() string {
+	reasons := make([]string, len(e))
+	for i := range e {
+		reasons[i] = e[i].Error()
+	}
+	return strings.Join(reasons, "; ")
+}
+
+// xmlElement is an element of a parsed xml document, with its namespace prefix resolved.
+type xmlElement struct {
+	prefix    string
+	local     string
+	namespace string
+	attrs     []xmlAttribute
+	children  []*xmlElement
+	text      strings.Builder
+	parent    *xmlElement
+}
+
+type xmlAttribute struct {
+	prefix    string
+	local     string
+	namespace string
+	value     string
+}
+
+func (e *xmlElement) qualifiedName() string {
+	if e.prefix == "" {
+		return e.local
+	}
+	return e.prefix + ":" + e.local
+}
+
+// xpath returns the location of the element in its document. Siblings that share a name are told apart by their
+// position, as they are in XPath.
+func (e *xmlElement) xpath() string {
+	if e == nil {
+		return "/"
+	}
+	step := e.qualifiedName()
+	if e.parent != nil {
+		position, count := 0, 0
+		for _, sibling := range e.parent.children {
+			if sibling.local == e.local && sibling.namespace == e.namespace {
+				count++
+				if sibling == e {
+					position = count
+				}
+			}
+		}
+		if count > 1 {
+			step = fmt.Sprintf("%s[%d]", step, position)
+		}
+		return e.parent.xpath() + "/" + step
+	}
+	return "/" + step
+}
+
+// attribute returns the attribute with a local name, or nil.
+func (e *xmlElement) attribute(local string) *xmlAttribute {
+	for i := range e.attrs {
+		if e.attrs[i].local == local {
+			return &e.attrs[i]
+		}
+	}
+	return nil
+}
+
+// childrenNamed returns the child elements with a local name, any name matches when the name is empty.
+func (e *xmlElement) childrenNamed(local string) []*xmlElement {
+	var named []*xmlElement
+	for _, child := range e.children {
+		if local == "" || child.local == local {
+			named = append(named, child)
+		}
+	}
+	return named
+}
+
+// parseXMLDocument parses an xml document into a tree of elements. Namespace prefixes are resolved against their
+// declarations, a prefix that is not declared makes the document malformed, as does an element that is not closed
+// by its own end tag.
+func parseXMLDocument(xmlString string) (*xmlElement, error) {
+	decoder := xml.NewDecoder(strings.NewReader(xmlString))
+	decoder.Strict = true
+
+	var root, current *xmlElement
+	scopes := []map[string]string{{"xml": "http://www.w3.org/XML/1998/namespace"}}
+
+	malformed := func(format string, args ...any) error {
+		return &XMLError{Location: current.xpath(), Reason: "malformed xml: " + fmt.Sprintf(format, args...)}
+	}
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, malformed("%s", err.Error())
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if current == nil && root != nil {
+				return nil, malformed("the document has more than one root element")
+			}
+			scope := make(map[string]string)
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == xmlnsAttribute:
+					scope[attr.Name.Local] = attr.Value
+				case attr.Name.Space == "" && attr.Name.Local == xmlnsAttribute:
+					scope[""] = attr.Value
+				}
+			}
+			scopes = append(scopes, scope)
+
+			element := &xmlElement{prefix: t.Name.Space, local: t.Name.Local, parent: current}
+			if current != nil {
+				current.children = append(current.children, element)
+			} else {
+				root = element
+			}
+			current = element
+
+			var ok bool
+			if element.namespace, ok = resolveXMLPrefix(scopes, element.prefix); !ok {
+				return nil, malformed("the namespace prefix '%s' is not declared", element.prefix)
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == xmlnsAttribute || (attr.Name.Space == "" && attr.Name.Local == xmlnsAttribute) {
+					continue
+				}
+				a := xmlAttribute{prefix: attr.Name.Space, local: attr.Name.Local, value: attr.Value}
+				// unprefixed attributes are in no namespace, the default namespace does not apply to them.
+				if a.prefix != "" {
+					if a.namespace, ok = resolveXMLPrefix(scopes, a.prefix); !ok {
+						return nil, malformed("the namespace prefix '%s' of attribute '%s' is not declared",
+							a.prefix, a.local)
+					}
+				}
+				element.attrs = append(element.attrs, a)
+			}
+
+		case xml.EndElement:
+			if current == nil || current.prefix != t.Name.Space || current.local != t.Name.Local {
+				name := t.Name.Local
+				if t.Name.Space != "" {
+					name = t.Name.Space + ":" + name
+				}
+				return nil, malformed("unexpected end element </%s>", name)
+			}
+			scopes = scopes[:len(scopes)-1]
+			current = current.parent
+
+		case xml.CharData:
+			if current != nil {
+				current.text.Write(t)
+			} else if strings.TrimSpace(string(t)) != "" {
+				return nil, malformed("text outside of the root element")
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, &XMLError{Location: "/", Reason: "malformed xml: the document has no root element"}
+	}
+	if current != nil {
+		return nil, malformed("element <%s> is not closed", current.qualifiedName())
+	}
+	return root, nil
+}
+
+// resolveXMLPrefix returns the namespace a prefix is bound to, in the innermost scope that declares it. Elements
+// without a prefix are in the default namespace, which may not be declared.
+func resolveXMLPrefix(scopes []map[string]string, prefix string) (string, bool) {
+	for i := len(scopes) - 1; i >= 0; i-- {
+		if namespace, ok := scopes[i][prefix]; ok {
+			return namespace, true
+		}
+	}
+	return "", prefix == ""
+}
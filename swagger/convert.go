@@ -0,0 +1,731 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+// Package swagger converts Swagger 2.0 documents into OpenAPI 3.0 documents, so requests and responses can be
+// validated against them by the same validators (and with the same errors) as OpenAPI 3+ documents.
+//
+// The conversion follows the upgrade path of the OpenAPI specification:
+//   - host, basePath and schemes become servers.
+//   - definitions become components/schemas, and their references are rewritten.
+//   - body parameters become request bodies, with a media type for every type the operation consumes.
+//   - formData parameters become the properties of an application/x-www-form-urlencoded or multipart/form-data
+//     request body, file parameters become binary strings.
+//   - query, path and header parameters get a schema from their type, and a style from their collectionFormat.
+//   - responses get a media type for every type the operation produces.
+//   - securityDefinitions become components/securitySchemes, with OAuth2 flows and HTTP basic authentication.
+//
+// The tsv collectionFormat has no OpenAPI 3 equivalent, those values are not split. References to other documents
+// are kept as they are.
+package swagger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
+	"go.yaml.in/yaml/v4"
+)
+
+// OpenAPIVersion is the version of the OpenAPI documents that Swagger documents are converted into.
+const OpenAPIVersion = "3.0.3"
+
+const (
+	formURLEncoded = "application/x-www-form-urlencoded"
+	multipartForm  = "multipart/form-data"
+	jsonContent    = "application/json"
+)
+
+// schemaKeywords are the keywords of parameters, items and headers that describe their values, they are moved
+// into the schema of the converted parameter or header.
+var schemaKeywords = []string{
+	"type", "format", "default", "maximum", "exclusiveMaximum", "minimum", "exclusiveMinimum", "maxLength",
+	"minLength", "pattern", "maxItems", "minItems", "uniqueItems", "enum", "multipleOf",
+}
+
+// IsSwagger returns true if the document is a Swagger 2.0 document.
+func IsSwagger(document libopenapi.Document) bool {
+	if document == nil || document.GetSpecInfo() == nil {
+		return false
+	}
+	return document.GetSpecInfo().SpecFormat == datamodel.OAS2
+}
+
+// ConvertDocument converts a Swagger 2.0 document into an OpenAPI 3.0 document, using the configuration of the
+// document. The document itself is not modified.
+//
+// The lines and columns of the converted document are those of the Swagger document, so errors point into the
+// document that was written. Nodes that only exist in the converted document (like the content of a request body)
+// have the position of the node they were created in.
+func ConvertDocument(document libopenapi.Document) (libopenapi.Document, error) {
+	if !IsSwagger(document) {
+		return nil, fmt.Errorf("the document is not a Swagger 2.0 document")
+	}
+	converted, err := Convert(document.GetSpecInfo().RootNode)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := yaml.Marshal(converted)
+	if err != nil {
+		return nil, fmt.Errorf("unable to render the converted document: %w", err)
+	}
+	doc, err := libopenapi.NewDocumentWithConfiguration(spec, document.GetConfiguration())
+	if err != nil {
+		return nil, err
+	}
+	if root := doc.GetSpecInfo().RootNode; root != nil && len(root.Content) > 0 {
+		copyPositions(root.Content[0], converted, 1, 1)
+	}
+	return doc, nil
+}
+
+// copyPositions sets the line and column of the nodes parsed from the rendered converted document to those of the
+// nodes they were rendered from. Nodes without a position take the position of their parent.
+func copyPositions(parsed, from *yaml.Node, line, column int) {
+	if from.Line > 0 {
+		line, column = from.Line, from.Column
+	}
+	parsed.Line, parsed.Column = line, column
+	if parsed.Kind != from.Kind || len(parsed.Content) != len(from.Content) {
+		return
+	}
+	for i := range parsed.Content {
+		keyLine, keyColumn := line, column
+		if from.Kind == yaml.MappingNode && i%2 == 0 && from.Content[i].Line == 0 && from.Content[i+1].Line > 0 {
+			keyLine, keyColumn = from.Content[i+1].Line, from.Content[i+1].Column // a key added for an existing value
+		}
+		copyPositions(parsed.Content[i], from.Content[i], keyLine, keyColumn)
+	}
+}
+
+// Convert converts the root node of a Swagger 2.0 document into the root node of an OpenAPI 3.0 document. The node
+// is copied, it is not modified.
+func Convert(root *yaml.Node) (*yaml.Node, error) {
+	if root != nil && root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root == nil || root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the Swagger document is not an object")
+	}
+	c := &converter{root: cloneNode(root)}
+	return c.convert(), nil
+}
+
+type converter struct {
+	root     *yaml.Node
+	consumes []string
+	produces []string
+}
+
+func (c *converter) convert() *yaml.Node {
+	c.consumes = stringList(get(c.root, "consumes"))
+	c.produces = stringList(get(c.root, "produces"))
+
+	out := mapping()
+	set(out, "openapi", scalar(OpenAPIVersion))
+	for _, key := range []string{"info", "externalDocs", "tags"} {
+		if n := get(c.root, key); n != nil {
+			set(out, key, n)
+		}
+	}
+	if servers := c.servers(); len(servers.Content) > 0 {
+		set(out, "servers", servers)
+	}
+	if n := get(c.root, "security"); n != nil {
+		set(out, "security", n)
+	}
+
+	paths := mapping()
+	if n := get(c.root, "paths"); n != nil {
+		at(paths, n)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key, item := n.Content[i], n.Content[i+1]
+			if isExtension(key.Value) {
+				set(paths, key.Value, item)
+				continue
+			}
+			set(paths, key.Value, c.pathItem(item))
+		}
+	}
+	set(out, "paths", paths)
+
+	components := mapping()
+	if n := get(c.root, "definitions"); n != nil {
+		for i := 1; i < len(n.Content); i += 2 {
+			rewriteSchema(n.Content[i])
+		}
+		set(components, "schemas", n)
+	}
+	if n := get(c.root, "securityDefinitions"); n != nil {
+		schemes := mapping()
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			set(schemes, n.Content[i].Value, securityScheme(n.Content[i+1]))
+		}
+		set(components, "securitySchemes", schemes)
+	}
+	if len(components.Content) > 0 {
+		set(out, "components", components)
+	}
+	copyExtensions(c.root, out)
+	return out
+}
+
+// servers returns a server for every scheme of the document, with its host and base path. Without schemes, both
+// http and https are served. Without a host, the base path is the only server.
+func (c *converter) servers() *yaml.Node {
+	servers := sequence()
+	host := scalarValue(get(c.root, "host"))
+	basePath := strings.TrimSuffix(scalarValue(get(c.root, "basePath")), "/")
+	if host == "" {
+		if basePath != "" {
+			servers.Content = append(servers.Content, server(basePath))
+		}
+		return servers
+	}
+	schemes := stringList(get(c.root, "schemes"))
+	if len(schemes) == 0 {
+		schemes = []string{"https", "http"}
+	}
+	for _, scheme := range schemes {
+		servers.Content = append(servers.Content, server(scheme+"://"+host+basePath))
+	}
+	return servers
+}
+
+// pathItem converts a path item, its parameters are merged into the parameters of each operation.
+func (c *converter) pathItem(item *yaml.Node) *yaml.Node {
+	out := at(mapping(), item)
+	shared := c.resolveParameters(get(item, "parameters"))
+	for i := 0; i+1 < len(item.Content); i += 2 {
+		key, value := item.Content[i].Value, item.Content[i+1]
+		switch key {
+		case "get", "put", "post", "delete", "options", "head", "patch":
+			set(out, key, c.operation(value, shared))
+		default:
+			if isExtension(key) {
+				set(out, key, value)
+			}
+		}
+	}
+	return out
+}
+
+// operation converts an operation, the body and formData parameters of the operation (or of its path item) become
+// its request body.
+func (c *converter) operation(op *yaml.Node, shared []*yaml.Node) *yaml.Node {
+	consumes := c.consumes
+	if n := get(op, "consumes"); n != nil {
+		consumes = stringList(n)
+	}
+	produces := c.produces
+	if n := get(op, "produces"); n != nil {
+		produces = stringList(n)
+	}
+
+	params := mergeParameters(shared, c.resolveParameters(get(op, "parameters")))
+	out := at(mapping(), op)
+	for i := 0; i+1 < len(op.Content); i += 2 {
+		key, value := op.Content[i].Value, op.Content[i+1]
+		switch key {
+		case "tags", "summary", "description", "externalDocs", "operationId", "deprecated", "security":
+			set(out, key, value)
+		case "parameters":
+			if converted := c.parameters(params); len(converted.Content) > 0 {
+				set(out, key, converted)
+			}
+		case "responses":
+			set(out, key, c.responses(value, produces))
+		default:
+			if isExtension(key) {
+				set(out, key, value)
+			}
+		}
+	}
+	if get(op, "parameters") == nil && len(params) > 0 {
+		if converted := c.parameters(params); len(converted.Content) > 0 {
+			set(out, "parameters", converted)
+		}
+	}
+	if body := requestBody(params, consumes); body != nil {
+		set(out, "requestBody", body)
+	}
+	return out
+}
+
+// resolveParameters returns the parameters of a list, references to the parameters of the document are resolved.
+func (c *converter) resolveParameters(list *yaml.Node) []*yaml.Node {
+	if list == nil {
+		return nil
+	}
+	var params []*yaml.Node
+	for _, p := range list.Content {
+		if ref := scalarValue(get(p, "$ref")); strings.HasPrefix(ref, "#/parameters/") {
+			if resolved := get(get(c.root, "parameters"), unescapePointer(strings.TrimPrefix(ref, "#/parameters/"))); resolved != nil {
+				p = resolved
+			}
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// parameters converts the query, path and header parameters of a list.
+func (c *converter) parameters(params []*yaml.Node) *yaml.Node {
+	out := sequence()
+	for _, p := range params {
+		switch scalarValue(get(p, "in")) {
+		case "body", "formData":
+			continue
+		}
+		out.Content = append(out.Content, parameter(p))
+	}
+	return out
+}
+
+func (c *converter) responses(responses *yaml.Node, produces []string) *yaml.Node {
+	if len(produces) == 0 {
+		produces = []string{jsonContent}
+	}
+	out := at(mapping(), responses)
+	for i := 0; i+1 < len(responses.Content); i += 2 {
+		code, response := responses.Content[i].Value, responses.Content[i+1]
+		if isExtension(code) {
+			set(out, code, response)
+			continue
+		}
+		if ref := scalarValue(get(response, "$ref")); strings.HasPrefix(ref, "#/responses/") {
+			if resolved := get(get(c.root, "responses"), unescapePointer(strings.TrimPrefix(ref, "#/responses/"))); resolved != nil {
+				response = resolved
+			}
+		}
+		set(out, code, convertResponse(response, produces))
+	}
+	return out
+}
+
+func convertResponse(response *yaml.Node, produces []string) *yaml.Node {
+	out := at(mapping(), response)
+	description := get(response, "description")
+	if description == nil {
+		description = scalar("")
+	}
+	set(out, "description", description)
+	if headers := get(response, "headers"); headers != nil {
+		converted := mapping()
+		for i := 0; i+1 < len(headers.Content); i += 2 {
+			set(converted, headers.Content[i].Value, header(headers.Content[i+1]))
+		}
+		set(out, "headers", converted)
+	}
+	if schema := get(response, "schema"); schema != nil {
+		rewriteSchema(schema)
+		content := mapping()
+		for _, mediaType := range produces {
+			set(content, mediaType, mapping("schema", schema))
+		}
+		set(out, "content", content)
+	}
+	copyExtensions(response, out)
+	return out
+}
+
+// parameter converts a query, path or header parameter, its type becomes its schema and its collectionFormat its
+// style.
+func parameter(p *yaml.Node) *yaml.Node {
+	if get(p, "in") == nil {
+		return p // a reference that cannot be resolved.
+	}
+	in := scalarValue(get(p, "in"))
+	out := at(mapping(), p)
+	for _, key := range []string{"name", "in", "description", "required"} {
+		if n := get(p, key); n != nil {
+			set(out, key, n)
+		}
+	}
+	if n := get(p, "allowEmptyValue"); n != nil && in == "query" {
+		set(out, "allowEmptyValue", n)
+	}
+	schema := itemsSchema(p)
+	set(out, "schema", schema)
+	if scalarValue(get(schema, "type")) == "array" {
+		if style, explode, ok := collectionStyle(in, scalarValue(get(p, "collectionFormat"))); ok {
+			set(out, "style", scalar(style))
+			set(out, "explode", boolean(explode))
+		}
+	}
+	copyExtensions(p, out)
+	return out
+}
+
+func header(h *yaml.Node) *yaml.Node {
+	out := at(mapping(), h)
+	if n := get(h, "description"); n != nil {
+		set(out, "description", n)
+	}
+	set(out, "schema", itemsSchema(h))
+	copyExtensions(h, out)
+	return out
+}
+
+// itemsSchema returns the schema of a parameter, header or items object, from the keywords that describe its
+// values.
+func itemsSchema(n *yaml.Node) *yaml.Node {
+	schema := at(mapping(), n)
+	for _, key := range schemaKeywords {
+		if v := get(n, key); v != nil {
+			set(schema, key, v)
+		}
+	}
+	if scalarValue(get(schema, "type")) == "file" {
+		set(schema, "type", scalar("string"))
+		set(schema, "format", scalar("binary"))
+	}
+	if items := get(n, "items"); items != nil {
+		set(schema, "items", itemsSchema(items))
+	}
+	return schema
+}
+
+// collectionStyle returns the style and explode values of a collectionFormat, for a parameter in a location (or a
+// property of a form). The default format is csv, tsv has no equivalent.
+func collectionStyle(in, format string) (string, bool, bool) {
+	switch format {
+	case "", "csv":
+		if in == "query" || in == "formData" {
+			return "form", false, true
+		}
+		return "simple", false, true
+	case "ssv":
+		return "spaceDelimited", false, in == "query" || in == "formData"
+	case "pipes":
+		return "pipeDelimited", false, in == "query" || in == "formData"
+	case "multi":
+		return "form", true, in == "query" || in == "formData"
+	}
+	return "", false, false
+}
+
+// requestBody converts the body parameter, or the formData parameters, of an operation into its request body.
+func requestBody(params []*yaml.Node, consumes []string) *yaml.Node {
+	var body *yaml.Node
+	var form []*yaml.Node
+	for _, p := range params {
+		switch scalarValue(get(p, "in")) {
+		case "body":
+			body = p
+		case "formData":
+			form = append(form, p)
+		}
+	}
+	if body != nil {
+		if len(consumes) == 0 {
+			consumes = []string{jsonContent}
+		}
+		out := at(mapping(), body)
+		if n := get(body, "description"); n != nil {
+			set(out, "description", n)
+		}
+		schema := get(body, "schema")
+		if schema == nil {
+			schema = mapping()
+		}
+		rewriteSchema(schema)
+		content := mapping()
+		for _, mediaType := range consumes {
+			set(content, mediaType, mapping("schema", schema))
+		}
+		set(out, "content", content)
+		if n := get(body, "required"); n != nil {
+			set(out, "required", n)
+		}
+		copyExtensions(body, out)
+		return out
+	}
+	if len(form) == 0 {
+		return nil
+	}
+	return formBody(form, consumes)
+}
+
+// formBody converts formData parameters into the properties of a form request body. The media types are the form
+// types the operation consumes, multipart/form-data is used when a parameter is a file.
+func formBody(form []*yaml.Node, consumes []string) *yaml.Node {
+	schema := at(mapping("type", scalar("object")), form[0])
+	properties := mapping()
+	required := sequence()
+	encoding := mapping()
+	hasFile := false
+	for _, p := range form {
+		name := scalarValue(get(p, "name"))
+		property := itemsSchema(p)
+		if n := get(p, "description"); n != nil {
+			set(property, "description", n)
+		}
+		set(properties, name, property)
+		if scalarValue(get(p, "type")) == "file" {
+			hasFile = true
+		}
+		if scalarValue(get(p, "required")) == "true" {
+			required.Content = append(required.Content, scalar(name))
+		}
+		if scalarValue(get(property, "type")) == "array" {
+			if style, explode, ok := collectionStyle("formData", scalarValue(get(p, "collectionFormat"))); ok {
+				set(encoding, name, mapping("style", scalar(style), "explode", boolean(explode)))
+			}
+		}
+	}
+	set(schema, "properties", properties)
+	if len(required.Content) > 0 {
+		set(schema, "required", required)
+	}
+
+	var mediaTypes []string
+	for _, mediaType := range consumes {
+		if mediaType == formURLEncoded || mediaType == multipartForm {
+			mediaTypes = append(mediaTypes, mediaType)
+		}
+	}
+	if len(mediaTypes) == 0 {
+		mediaTypes = []string{formURLEncoded}
+		if hasFile {
+			mediaTypes = []string{multipartForm}
+		}
+	}
+
+	content := mapping()
+	for _, mediaType := range mediaTypes {
+		mt := mapping("schema", schema)
+		if mediaType == formURLEncoded && len(encoding.Content) > 0 {
+			set(mt, "encoding", encoding)
+		}
+		set(content, mediaType, mt)
+	}
+	out := at(mapping("content", content), form[0])
+	if len(required.Content) > 0 {
+		set(out, "required", boolean(true))
+	}
+	return out
+}
+
+// securityScheme converts a security definition: basic authentication becomes an HTTP scheme, and the flow of an
+// OAuth2 definition becomes one of its flows.
+func securityScheme(def *yaml.Node) *yaml.Node {
+	out := at(mapping(), def)
+	switch scalarValue(get(def, "type")) {
+	case "basic":
+		set(out, "type", scalar("http"))
+		set(out, "scheme", scalar("basic"))
+	case "oauth2":
+		set(out, "type", scalar("oauth2"))
+		flow := mapping()
+		name := ""
+		switch scalarValue(get(def, "flow")) {
+		case "implicit":
+			name = "implicit"
+		case "password":
+			name = "password"
+		case "application":
+			name = "clientCredentials"
+		case "accessCode":
+			name = "authorizationCode"
+		}
+		if n := get(def, "authorizationUrl"); n != nil && name != "password" && name != "clientCredentials" {
+			set(flow, "authorizationUrl", n)
+		}
+		if n := get(def, "tokenUrl"); n != nil && name != "implicit" {
+			set(flow, "tokenUrl", n)
+		}
+		scopes := get(def, "scopes")
+		if scopes == nil {
+			scopes = mapping()
+		}
+		set(flow, "scopes", scopes)
+		if name != "" {
+			set(out, "flows", mapping(name, flow))
+		}
+	default:
+		for _, key := range []string{"type", "name", "in"} {
+			if n := get(def, key); n != nil {
+				set(out, key, n)
+			}
+		}
+	}
+	if n := get(def, "description"); n != nil {
+		set(out, "description", n)
+	}
+	copyExtensions(def, out)
+	return out
+}
+
+// rewriteSchema rewrites what OpenAPI 3.0 schemas express differently: references to definitions, discriminators
+// that are property names, x-nullable and file types. Only the keywords of the schema and of its subschemas are
+// rewritten, examples, defaults, enums and extensions are values and are kept as they are. Rewriting a schema twice
+// does not change it.
+func rewriteSchema(schema *yaml.Node) {
+	if schema == nil || schema.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(schema.Content); i += 2 {
+		key, value := schema.Content[i], schema.Content[i+1]
+		switch key.Value {
+		case "$ref":
+			if name, ok := strings.CutPrefix(value.Value, "#/definitions/"); ok && value.Kind == yaml.ScalarNode {
+				value.Value = "#/components/schemas/" + name
+			}
+		case "discriminator":
+			if value.Kind == yaml.ScalarNode {
+				schema.Content[i+1] = mapping("propertyName", value)
+			}
+		case "x-nullable":
+			key.Value = "nullable"
+		case "type":
+			if value.Kind == yaml.ScalarNode && value.Value == "file" {
+				value.Value = "string"
+				if get(schema, "format") == nil {
+					set(schema, "format", scalar("binary"))
+				}
+			}
+		case "items", "additionalProperties", "not":
+			if value.Kind == yaml.SequenceNode {
+				for _, item := range value.Content {
+					rewriteSchema(item)
+				}
+				continue
+			}
+			rewriteSchema(value)
+		case "allOf", "anyOf", "oneOf":
+			for _, item := range value.Content {
+				rewriteSchema(item)
+			}
+		case "properties", "patternProperties":
+			for j := 1; j < len(value.Content); j += 2 {
+				rewriteSchema(value.Content[j])
+			}
+		}
+	}
+}
+
+// mergeParameters returns the parameters of a path item, overridden by the parameters of an operation with the same
+// name and location.
+func mergeParameters(shared, own []*yaml.Node) []*yaml.Node {
+	if len(shared) == 0 {
+		return own
+	}
+	params := make([]*yaml.Node, 0, len(shared)+len(own))
+	for _, p := range shared {
+		overridden := false
+		for _, o := range own {
+			if scalarValue(get(o, "name")) == scalarValue(get(p, "name")) &&
+				scalarValue(get(o, "in")) == scalarValue(get(p, "in")) {
+				overridden = true
+				break
+			}
+		}
+		if !overridden {
+			params = append(params, p)
+		}
+	}
+	return append(params, own...)
+}
+
+func server(url string) *yaml.Node {
+	return mapping("url", scalar(url))
+}
+
+func copyExtensions(from, to *yaml.Node) {
+	for i := 0; i+1 < len(from.Content); i += 2 {
+		if isExtension(from.Content[i].Value) {
+			set(to, from.Content[i].Value, from.Content[i+1])
+		}
+	}
+}
+
+func isExtension(key string) bool {
+	return strings.HasPrefix(key, "x-")
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+func get(n *yaml.Node, key string) *yaml.Node {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func set(n *yaml.Node, key string, value *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			n.Content[i+1] = value
+			return
+		}
+	}
+	n.Content = append(n.Content, scalar(key), value)
+}
+
+func scalarValue(n *yaml.Node) string {
+	if n == nil || n.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return n.Value
+}
+
+func stringList(n *yaml.Node) []string {
+	if n == nil {
+		return nil
+	}
+	var values []string
+	for _, v := range n.Content {
+		if v.Kind == yaml.ScalarNode {
+			values = append(values, v.Value)
+		}
+	}
+	return values
+}
+
+// mapping returns a mapping node of (key, value) pairs.
+func mapping(pairs ...any) *yaml.Node {
+	n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		n.Content = append(n.Content, scalar(pairs[i].(string)), pairs[i+1].(*yaml.Node))
+	}
+	return n
+}
+
+// at gives a node created by the conversion the position of the node it was created from.
+func at(n, from *yaml.Node) *yaml.Node {
+	n.Line, n.Column = from.Line, from.Column
+	return n
+}
+
+func sequence() *yaml.Node {
+	return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+}
+
+func scalar(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
+
+func boolean(value bool) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: fmt.Sprint(value)}
+}
+
+func cloneNode(n *yaml.Node) *yaml.Node {
+	if n == nil {
+		return nil
+	}
+	clone := *n
+	clone.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		clone.Content[i] = cloneNode(child)
+	}
+	clone.Alias = cloneNode(n.Alias)
+	return &clone
+}
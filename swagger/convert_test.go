@@ -0,0 +1,308 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package swagger
+
+import (
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.yaml.in/yaml/v4"
+
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+)
+
+var swaggerSpec = `swagger: "2.0"
+info:
+  title: Burger Shop
+  version: 1.0.0
+host: api.pb33f.io
+basePath: /v2
+schemes: [https]
+consumes: [application/json]
+produces: [application/json]
+securityDefinitions:
+  Basic:
+    type: basic
+  Token:
+    type: oauth2
+    flow: accessCode
+    authorizationUrl: https://pb33f.io/authorize
+    tokenUrl: https://pb33f.io/token
+    scopes:
+      burgers:write: make burgers
+parameters:
+  Limit:
+    name: limit
+    in: query
+    type: integer
+    maximum: 10
+paths:
+  /burgers:
+    parameters:
+      - $ref: '#/parameters/Limit'
+    get:
+      parameters:
+        - name: toppings
+          in: query
+          type: array
+          items:
+            type: string
+          collectionFormat: pipes
+        - name: X-Tenant
+          in: header
+          type: array
+          items:
+            type: string
+      responses:
+        '200':
+          description: burgers
+          headers:
+            X-Count:
+              type: integer
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/Burger'
+    post:
+      security:
+        - Basic: []
+      parameters:
+        - name: burger
+          in: body
+          required: true
+          schema:
+            $ref: '#/definitions/Burger'
+      responses:
+        '201':
+          description: created
+  /burgers/{id}/photo:
+    put:
+      consumes: [multipart/form-data]
+      parameters:
+        - name: id
+          in: path
+          required: true
+          type: string
+        - name: photo
+          in: formData
+          required: true
+          type: file
+        - name: tags
+          in: formData
+          type: array
+          items:
+            type: string
+          collectionFormat: multi
+      responses:
+        default:
+          description: done
+definitions:
+  Burger:
+    type: object
+    discriminator: kind
+    required: [kind]
+    properties:
+      kind:
+        type: string
+      name:
+        type: string
+        x-nullable: true`
+
+func convertSpec(t *testing.T, spec string) *v3.Document {
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	require.True(t, IsSwagger(doc))
+	converted, err := ConvertDocument(doc)
+	require.NoError(t, err)
+	m, errs := converted.BuildV3Model()
+	require.NoError(t, errs)
+	return &m.Model
+}
+
+func TestConvertDocument(t *testing.T) {
+	m := convertSpec(t, swaggerSpec)
+
+	assert.Equal(t, OpenAPIVersion, m.Version)
+	require.Len(t, m.Servers, 1)
+	assert.Equal(t, "https://api.pb33f.io/v2", m.Servers[0].URL)
+
+	// definitions are schemas, their discriminators and extensions are rewritten
+	burger := m.Components.Schemas.GetOrZero("Burger").Schema()
+	require.NotNil(t, burger.Discriminator)
+	assert.Equal(t, "kind", burger.Discriminator.PropertyName)
+	assert.True(t, *burger.Properties.GetOrZero("name").Schema().Nullable)
+
+	// security definitions
+	basic := m.Components.SecuritySchemes.GetOrZero("Basic")
+	assert.Equal(t, "http", basic.Type)
+	assert.Equal(t, "basic", basic.Scheme)
+	token := m.Components.SecuritySchemes.GetOrZero("Token")
+	require.NotNil(t, token.Flows.AuthorizationCode)
+	assert.Equal(t, "https://pb33f.io/token", token.Flows.AuthorizationCode.TokenUrl)
+	assert.Equal(t, 1, token.Flows.AuthorizationCode.Scopes.Len())
+
+	// parameters of the path item are merged into the operation, collection formats are styles
+	get := m.Paths.PathItems.GetOrZero("/burgers").Get
+	require.Len(t, get.Parameters, 3)
+	assert.Equal(t, "limit", get.Parameters[0].Name)
+	assert.Equal(t, "integer", get.Parameters[0].Schema.Schema().Type[0])
+	assert.Equal(t, "pipeDelimited", get.Parameters[1].Style)
+	assert.False(t, *get.Parameters[1].Explode)
+	assert.Equal(t, "simple", get.Parameters[2].Style)
+
+	// responses are produced as json, with their headers
+	ok := get.Responses.Codes.GetOrZero("200")
+	assert.Equal(t, "#/components/schemas/Burger",
+		ok.Content.GetOrZero("application/json").Schema.Schema().Items.A.GetReference())
+	assert.Equal(t, "integer", ok.Headers.GetOrZero("X-Count").Schema.Schema().Type[0])
+
+	// the body parameter is the request body
+	post := m.Paths.PathItems.GetOrZero("/burgers").Post
+	require.NotNil(t, post.RequestBody)
+	assert.True(t, *post.RequestBody.Required)
+	assert.Equal(t, "#/components/schemas/Burger",
+		post.RequestBody.Content.GetOrZero("application/json").Schema.GetReference())
+	assert.Equal(t, 1, post.Security[0].Requirements.Len())
+	assert.Nil(t, post.Responses.Codes.GetOrZero("201").Content)
+
+	// formData parameters are the properties of a form
+	put := m.Paths.PathItems.GetOrZero("/burgers/{id}/photo").Put
+	require.Len(t, put.Parameters, 1)
+	form := put.RequestBody.Content.GetOrZero("multipart/form-data")
+	require.NotNil(t, form)
+	schema := form.Schema.Schema()
+	assert.Equal(t, []string{"photo"}, schema.Required)
+	assert.Equal(t, "binary", schema.Properties.GetOrZero("photo").Schema().Format)
+	assert.Equal(t, "array", schema.Properties.GetOrZero("tags").Schema().Type[0])
+	assert.Equal(t, "done", put.Responses.Default.Description)
+}
+
+func TestConvertDocument_Servers(t *testing.T) {
+	m := convertSpec(t, `swagger: "2.0"
+host: pb33f.io
+paths: {}`)
+	require.Len(t, m.Servers, 2)
+	assert.Equal(t, "https://pb33f.io", m.Servers[0].URL)
+	assert.Equal(t, "http://pb33f.io", m.Servers[1].URL)
+
+	m = convertSpec(t, `swagger: "2.0"
+basePath: /api/
+paths: {}`)
+	require.Len(t, m.Servers, 1)
+	assert.Equal(t, "/api", m.Servers[0].URL)
+
+	m = convertSpec(t, `swagger: "2.0"
+paths: {}`)
+	assert.Empty(t, m.Servers)
+}
+
+func TestConvertDocument_FormEncoding(t *testing.T) {
+	m := convertSpec(t, `swagger: "2.0"
+paths:
+  /orders:
+    post:
+      parameters:
+        - name: items
+          in: formData
+          type: array
+          items:
+            type: string
+      responses:
+        '200':
+          description: ok`)
+
+	form := m.Paths.PathItems.GetOrZero("/orders").Post.RequestBody.Content.GetOrZero(formURLEncoded)
+	require.NotNil(t, form)
+	enc := form.Encoding.GetOrZero("items")
+	require.NotNil(t, enc)
+	assert.Equal(t, "form", enc.Style)
+	assert.False(t, *enc.Explode)
+}
+
+func TestConvertDocument_NotSwagger(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(`openapi: 3.1.0`))
+	require.NoError(t, err)
+	assert.False(t, IsSwagger(doc))
+	assert.False(t, IsSwagger(nil))
+	_, err = ConvertDocument(doc)
+	assert.Error(t, err)
+
+	_, err = Convert(&yaml.Node{Kind: yaml.ScalarNode, Value: "burger"})
+	assert.Error(t, err)
+}
+
+func TestConvert_DoesNotModifyRoot(t *testing.T) {
+	doc, err := libopenapi.NewDocument([]byte(swaggerSpec))
+	require.NoError(t, err)
+	before, _ := yaml.Marshal(doc.GetSpecInfo().RootNode)
+	_, err = Convert(doc.GetSpecInfo().RootNode)
+	require.NoError(t, err)
+	after, _ := yaml.Marshal(doc.GetSpecInfo().RootNode)
+	assert.Equal(t, string(before), string(after))
+}
+
+func TestConvert_SchemaValues(t *testing.T) {
+	var root yaml.Node
+	require.NoError(t, yaml.Unmarshal([]byte(`swagger: "2.0"
+paths:
+  /burgers:
+    post:
+      parameters:
+        - name: burger
+          in: body
+          schema:
+            type: object
+            properties:
+              discriminator:
+                type: string
+                x-nullable: true
+            example:
+              $ref: '#/definitions/Burger'
+              type: file
+              discriminator: kind
+            x-menu:
+              type: file
+      responses:
+        '200':
+          description: ok`), &root))
+	converted, err := Convert(&root)
+	require.NoError(t, err)
+
+	var out struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema map[string]any
+				}
+			} `yaml:"requestBody"`
+		}
+	}
+	require.NoError(t, converted.Decode(&out))
+	schema := out.Paths["/burgers"]["post"].RequestBody.Content["application/json"].Schema
+
+	// a property called discriminator is a schema, not a discriminator
+	assert.Equal(t, map[string]any{"discriminator": map[string]any{"type": "string", "nullable": true}},
+		schema["properties"])
+
+	// examples and extensions are values, not schemas
+	assert.Equal(t, map[string]any{"$ref": "#/definitions/Burger", "type": "file", "discriminator": "kind"},
+		schema["example"])
+	assert.Equal(t, map[string]any{"type": "file"}, schema["x-menu"])
+}
+
+func TestConvertDocument_Positions(t *testing.T) {
+	m := convertSpec(t, swaggerSpec)
+
+	// positions are those of the Swagger document
+	burger := m.Components.Schemas.GetOrZero("Burger").Schema().GoLow()
+	assert.Equal(t, 88, burger.Type.KeyNode.Line)
+	assert.Equal(t, 5, burger.Type.KeyNode.Column)
+
+	// nodes created by the conversion have the position of the node they were created from
+	post := m.Paths.PathItems.GetOrZero("/burgers").Post.GoLow()
+	assert.Equal(t, 57, post.RequestBody.KeyNode.Line)
+	assert.Equal(t, 11, post.RequestBody.KeyNode.Column)
+}
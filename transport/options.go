@@ -0,0 +1,74 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package transport
+
+import (
+	"net/http"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+)
+
+// ErrorHandler receives the validation errors for an exchange. The response is nil when the errors are for the
+// outbound request.
+type ErrorHandler func(request *http.Request, response *http.Response, validationErrors []*errors.ValidationError)
+
+// Options A container for RoundTripper configuration.
+type Options struct {
+	Transport    http.RoundTripper // The transport used to send requests, http.DefaultTransport if nil
+	ErrorHandler ErrorHandler
+	FailOnError  bool // Fail the call with a *ValidationFailedError instead of only reporting
+
+	// MaxResponseBodySize is the largest response body, in bytes, that is buffered and validated. Larger responses
+	// are passed on without being validated. Zero (the default) means no limit.
+	MaxResponseBodySize int64
+}
+
+// Option Enables an 'Options pattern' approach
+type Option func(*Options)
+
+// NewOptions creates a new Options instance with default values.
+func NewOptions(opts ...Option) *Options {
+	o := &Options{
+		Transport: http.DefaultTransport,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithTransport sets the http.RoundTripper that is wrapped, the default is http.DefaultTransport.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *Options) {
+		if transport != nil {
+			o.Transport = transport
+		}
+	}
+}
+
+// WithErrorHandler sets the callback that receives validation errors.
+func WithErrorHandler(handler ErrorHandler) Option {
+	return func(o *Options) {
+		o.ErrorHandler = handler
+	}
+}
+
+// WithFailOnError makes the RoundTripper fail the call with a *ValidationFailedError when validation fails.
+// Invalid requests are never sent, and invalid responses are closed and discarded. The ErrorHandler is still
+// called first.
+func WithFailOnError() Option {
+	return func(o *Options) {
+		o.FailOnError = true
+	}
+}
+
+// WithMaxResponseBodySize sets the largest response body, in bytes, that is buffered and validated. Responses with
+// larger bodies are passed on without being validated.
+func WithMaxResponseBodySize(limit int64) Option {
+	return func(o *Options) {
+		o.MaxResponseBodySize = limit
+	}
+}
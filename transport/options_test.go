@@ -0,0 +1,28 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewOptions_Defaults(t *testing.T) {
+	o := NewOptions()
+
+	assert.Equal(t, http.DefaultTransport, o.Transport)
+	assert.Nil(t, o.ErrorHandler)
+	assert.False(t, o.FailOnError)
+	assert.Zero(t, o.MaxResponseBodySize)
+}
+
+func TestNewOptions_Overrides(t *testing.T) {
+	o := NewOptions(nil, WithTransport(nil), WithFailOnError(), WithMaxResponseBodySize(1024))
+
+	assert.Equal(t, http.DefaultTransport, o.Transport)
+	assert.True(t, o.FailOnError)
+	assert.Equal(t, int64(1024), o.MaxResponseBodySize)
+}
@@ -0,0 +1,7 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+// Package transport contains an http.RoundTripper that validates outbound client traffic, and the responses that
+// come back, against the OpenAPI 3+ document of the API being called. It is designed to catch contract drift
+// between a client and a third-party API.
+package transport
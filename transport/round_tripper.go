@@ -0,0 +1,181 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+
+	validator "github.com/pb33f/libopenapi-validator"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+)
+
+// ValidationFailedError is returned by the RoundTripper when validation fails and WithFailOnError is set.
+type ValidationFailedError struct {
+	Request  *http.Request
+	Response *http.Response // nil when the request failed validation, and was never sent
+	Errors   []*errors.ValidationError
+}
+
+// Error returns a summary of the failure.
+func (e *ValidationFailedError) Error() string {
+	direction := "request"
+	if e.Response != nil {
+		direction = "response"
+	}
+	return fmt.Sprintf("%s %s %s failed OpenAPI validation with %d error(s)",
+		e.Request.Method, e.Request.URL.String(), direction, len(e.Errors))
+}
+
+// RoundTripper is an http.RoundTripper that validates outbound requests before they are sent, and responses after
+// they are received.
+//
+// The request is validated with ValidateHttpRequest before it is sent, and the response is validated with
+// ValidateHttpResponse after it is received, so the errors of a request are only reported once.
+//
+// Request and response bodies are buffered in memory. The request handed to the transport, and those validated,
+// are clones with their own reader over the request body, the caller's request is not modified (its body can be
+// read again through GetBody). The response body is restored, so callers see it untouched. A response body larger
+// than the limit set by WithMaxResponseBodySize is not validated, and only the part read so far is buffered.
+type RoundTripper struct {
+	validator validator.Validator
+	options   *Options
+}
+
+// NewRoundTripper creates a new RoundTripper that validates traffic with the supplied validator.Validator.
+func NewRoundTripper(v validator.Validator, opts ...Option) *RoundTripper {
+	return &RoundTripper{validator: v, options: NewOptions(opts...)}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *RoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	body, err := drain(request.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, validationErrors := rt.validateRequest(withBody(request, body)); len(validationErrors) > 0 {
+		if errors.ContainsCancellation(validationErrors) {
+			return nil, request.Context().Err()
+		}
+		if err = rt.fail(request, nil, validationErrors); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := rt.options.Transport.RoundTrip(withBody(request, body))
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, complete, err := drainLimited(response.Body, rt.options.MaxResponseBodySize)
+	if err != nil {
+		return nil, err
+	}
+	if !complete {
+		// too large to validate, the rest of the body is left to the caller.
+		response.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(responseBody), response.Body), response.Body}
+		return response, nil
+	}
+	response.Body = restore(responseBody)
+
+	validated := *response
+	validated.Body = restore(responseBody)
+	if _, validationErrors := rt.validateResponse(withBody(request, body), &validated); len(validationErrors) > 0 {
+		if errors.ContainsCancellation(validationErrors) {
+			_ = response.Body.Close()
+			return nil, request.Context().Err()
+		}
+		if err = rt.fail(request, response, validationErrors); err != nil {
+			return nil, err
+		}
+	}
+	return response, nil
+}
+
+// validateRequest validates a request with its context, when the validator accepts one.
+func (rt *RoundTripper) validateRequest(request *http.Request) (bool, []*errors.ValidationError) {
+	if contextValidator, ok := rt.validator.(validator.ContextValidator); ok {
+		return contextValidator.ValidateHttpRequestCtx(request.Context(), request)
+	}
+	return rt.validator.ValidateHttpRequest(request)
+}
+
+// validateResponse validates a response with the context of its request, when the validator accepts one.
+func (rt *RoundTripper) validateResponse(request *http.Request, response *http.Response) (bool, []*errors.ValidationError) {
+	if contextValidator, ok := rt.validator.(validator.ContextValidator); ok {
+		return contextValidator.ValidateHttpResponseCtx(request.Context(), request, response)
+	}
+	return rt.validator.ValidateHttpResponse(request, response)
+}
+
+// fail reports the errors, and returns a *ValidationFailedError if the call should fail.
+func (rt *RoundTripper) fail(request *http.Request, response *http.Response, validationErrors []*errors.ValidationError) error {
+	if rt.options.ErrorHandler != nil {
+		rt.options.ErrorHandler(request, response, validationErrors)
+	}
+	if !rt.options.FailOnError {
+		return nil
+	}
+	if response != nil {
+		_ = response.Body.Close()
+	}
+	return &ValidationFailedError{Request: request, Response: response, Errors: validationErrors}
+}
+
+// drain reads and closes a body, a nil body yields nil.
+func drain(body io.ReadCloser) ([]byte, error) {
+	if body == nil || body == http.NoBody {
+		return nil, nil
+	}
+	defer body.Close()
+	return io.ReadAll(body)
+}
+
+// drainLimited works in the same way as drain, but stops reading once more than limit bytes have been read (a limit
+// of zero or less reads everything). A body that is not complete is left open.
+func drainLimited(body io.ReadCloser, limit int64) ([]byte, bool, error) {
+	if limit <= 0 {
+		b, err := drain(body)
+		return b, true, err
+	}
+	if body == nil || body == http.NoBody {
+		return nil, true, nil
+	}
+	b, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		_ = body.Close()
+		return nil, false, err
+	}
+	if int64(len(b)) > limit {
+		return b, false, nil
+	}
+	_ = body.Close()
+	return b, true, nil
+}
+
+func restore(body []byte) io.ReadCloser {
+	if body == nil {
+		return http.NoBody
+	}
+	return io.NopCloser(bytes.NewReader(body))
+}
+
+// withBody clones the request, with a fresh reader over the buffered body.
+func withBody(request *http.Request, body []byte) *http.Request {
+	clone := request.Clone(request.Context())
+	clone.Body = restore(body)
+	if body != nil {
+		clone.GetBody = func() (io.ReadCloser, error) {
+			return restore(body), nil
+		}
+	}
+	return clone
+}
@@ -0,0 +1,249 @@
+// Copyright 2023-2025 Princess Beef Heavy Industries, LLC / Dave Shanley
+// https://pb33f.io
+
+package transport
+
+import (
+	"context"
+	stdErrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	validator "github.com/pb33f/libopenapi-validator"
+
+	"github.com/pb33f/libopenapi-validator/errors"
+)
+
+const burgerSpec = `openapi: 3.1.0
+paths:
+  /burgers:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+      responses:
+        '201':
+          description: created
+          content:
+            application/json:
+              schema:
+                type: object
+                required: [id]
+                properties:
+                  id:
+                    type: integer`
+
+type captured struct {
+	request  *http.Request
+	response *http.Response
+	errors   []*errors.ValidationError
+}
+
+func newBurgerServer(t *testing.T, response string, received *string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		if received != nil {
+			*received = string(b)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newBurgerClient(t *testing.T, opts ...Option) (*http.Client, *[]captured) {
+	doc, err := libopenapi.NewDocument([]byte(burgerSpec))
+	require.NoError(t, err)
+	v, errs := validator.NewValidator(doc)
+	require.Empty(t, errs)
+
+	var calls []captured
+	opts = append([]Option{WithErrorHandler(func(request *http.Request, response *http.Response, validationErrors []*errors.ValidationError) {
+		calls = append(calls, captured{request, response, validationErrors})
+	})}, opts...)
+	return &http.Client{Transport: NewRoundTripper(v, opts...)}, &calls
+}
+
+func TestRoundTripper_Valid(t *testing.T) {
+	var received string
+	server := newBurgerServer(t, `{"id":1}`, &received)
+	client, calls := newBurgerClient(t)
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	require.NoError(t, err)
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, `{"id":1}`, string(body))
+	assert.Equal(t, `{"name":"big mac"}`, received)
+	assert.Empty(t, *calls)
+
+	// the caller's request is not modified, its body can be read again
+	reqBody, err := request.GetBody()
+	require.NoError(t, err)
+	b, _ := io.ReadAll(reqBody)
+	assert.Equal(t, `{"name":"big mac"}`, string(b))
+}
+
+func TestRoundTripper_ReportsInvalidRequestAndResponse(t *testing.T) {
+	var received string
+	server := newBurgerServer(t, `{"id":"one"}`, &received)
+	client, calls := newBurgerClient(t)
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/burgers", strings.NewReader(`{"patties":2}`))
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	require.NoError(t, err)
+
+	// the request was still sent, and the response is untouched
+	assert.Equal(t, `{"patties":2}`, received)
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, `{"id":"one"}`, string(body))
+
+	// the request is reported before it is sent, and only the response once it is received
+	require.Len(t, *calls, 2)
+	assert.Nil(t, (*calls)[0].response)
+	require.Len(t, (*calls)[0].errors, 1)
+	assert.Equal(t, "requestBody", (*calls)[0].errors[0].ValidationType)
+	assert.NotNil(t, (*calls)[1].response)
+	require.Len(t, (*calls)[1].errors, 1)
+	assert.Equal(t, "response", (*calls)[1].errors[0].ValidationType)
+}
+
+func TestRoundTripper_MaxResponseBodySize(t *testing.T) {
+	large := `{"id":"` + strings.Repeat("x", 64) + `"}`
+	server := newBurgerServer(t, large, nil)
+	client, calls := newBurgerClient(t, WithMaxResponseBodySize(32), WithFailOnError())
+
+	// the response is too large to validate, so it is passed on untouched
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	assert.Equal(t, large, string(body))
+	assert.NoError(t, response.Body.Close())
+	assert.Empty(t, *calls)
+
+	// smaller responses are still validated
+	server = newBurgerServer(t, `{"id":"one"}`, nil)
+	request, _ = http.NewRequest(http.MethodPost, server.URL+"/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	_, err = client.Do(request)
+	var failed *ValidationFailedError
+	require.True(t, stdErrors.As(err, &failed))
+	require.Len(t, *calls, 1)
+}
+
+func TestRoundTripper_FailOnError_Request(t *testing.T) {
+	sent := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+	}))
+	defer server.Close()
+	client, calls := newBurgerClient(t, WithFailOnError())
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/burgers", strings.NewReader(`{}`))
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+
+	assert.Nil(t, response)
+	assert.False(t, sent)
+	require.Len(t, *calls, 1)
+
+	var failed *ValidationFailedError
+	require.True(t, stdErrors.As(err, &failed))
+	assert.Nil(t, failed.Response)
+	assert.NotEmpty(t, failed.Errors)
+	assert.Contains(t, failed.Error(), "request failed OpenAPI validation with 1 error(s)")
+}
+
+func TestRoundTripper_FailOnError_Response(t *testing.T) {
+	server := newBurgerServer(t, `{"id":"one"}`, nil)
+	client, _ := newBurgerClient(t, WithFailOnError())
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL+"/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+
+	assert.Nil(t, response)
+	var failed *ValidationFailedError
+	require.True(t, stdErrors.As(err, &failed))
+	assert.Equal(t, http.StatusCreated, failed.Response.StatusCode)
+	assert.Contains(t, failed.Error(), "response failed OpenAPI validation")
+}
+
+func TestRoundTripper_CustomTransport(t *testing.T) {
+	used := false
+	inner := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		used = true
+		return &http.Response{
+			StatusCode: http.StatusCreated,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       io.NopCloser(strings.NewReader(`{"id":1}`)),
+			Request:    r,
+		}, nil
+	})
+	client, calls := newBurgerClient(t, WithTransport(inner))
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	response, err := client.Do(request)
+	require.NoError(t, err)
+
+	assert.True(t, used)
+	assert.Equal(t, http.StatusCreated, response.StatusCode)
+	assert.Empty(t, *calls)
+}
+
+func TestRoundTripper_TransportError(t *testing.T) {
+	inner := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		return nil, io.ErrUnexpectedEOF
+	})
+	client, _ := newBurgerClient(t, WithTransport(inner))
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	_, err := client.Do(request)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+func TestRoundTripper_Cancelled(t *testing.T) {
+	client, calls := newBurgerClient(t, WithTransport(roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		t.Fatal("request should not be sent")
+		return nil, nil
+	})))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request, _ := http.NewRequestWithContext(ctx, http.MethodPost, "https://things.com/burgers", strings.NewReader(`{"name":"big mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	_, err := client.Do(request)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, *calls)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -4,8 +4,10 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"sync"
 
 	"github.com/pb33f/libopenapi"
@@ -23,6 +25,7 @@ import (
 	"github.com/pb33f/libopenapi-validator/requests"
 	"github.com/pb33f/libopenapi-validator/responses"
 	"github.com/pb33f/libopenapi-validator/schema_validation"
+	"github.com/pb33f/libopenapi-validator/swagger"
 )
 
 // Validator provides a coarse grained interface for validating an OpenAPI 3+ documents.
@@ -58,6 +61,15 @@ type Validator interface {
 	// ValidateDocument will validate an OpenAPI 3+ document against the 3.0 or 3.1 OpenAPI 3+ specification
 	ValidateDocument() (bool, []*errors.ValidationError)
 
+	// FindPath will locate the PathItem in the document that matches the request, using the same options as the
+	// validator. The returned path item and path value can be handed to the ...WithPathItem methods, so the lookup
+	// is only performed once.
+	FindPath(request *http.Request) (*v3.PathItem, []*errors.ValidationError, string)
+
+	// MatchRoute works in the same way as FindPath, but returns a paths.RouteMatch with the operation, the matched
+	// server and the decoded and typed path parameters, so the request URL does not have to be parsed again.
+	MatchRoute(request *http.Request) (*paths.RouteMatch, []*errors.ValidationError)
+
 	// GetParameterValidator will return a parameters.ParameterValidator instance used to validate parameters
 	GetParameterValidator() parameters.ParameterValidator
 
@@ -71,9 +83,55 @@ type Validator interface {
 	SetDocument(document libopenapi.Document)
 }
 
-// NewValidator will create a new Validator from an OpenAPI 3+ document
+// ContextValidator is a Validator that can abandon validation when a context is done. The Validator created by
+// NewValidator is always a ContextValidator.
+type ContextValidator interface {
+	Validator
+
+	// ValidateHttpRequestCtx will validate an *http.Request object against an OpenAPI 3+ document, in the same way as
+	// ValidateHttpRequest. If the context is done before validation completes, validation is abandoned and a single
+	// cancellation error is returned (see errors.ValidationError.IsCancellationError).
+	ValidateHttpRequestCtx(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError)
+
+	// ValidateHttpRequestSyncCtx will validate an *http.Request object against an OpenAPI 3+ document synchronously,
+	// in the same way as ValidateHttpRequestSync. If the context is done before validation completes, validation is
+	// abandoned and a single cancellation error is returned.
+	ValidateHttpRequestSyncCtx(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError)
+
+	// ValidateHttpRequestWithPathItemCtx will validate an *http.Request object against an OpenAPI 3+ document, in the
+	// same way as ValidateHttpRequestWithPathItem. If the context is done before validation completes, the spawned
+	// goroutines are stopped, and a single cancellation error is returned once they have all returned.
+	ValidateHttpRequestWithPathItemCtx(ctx context.Context, request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+	// ValidateHttpRequestSyncWithPathItemCtx will validate an *http.Request object against an OpenAPI 3+ document
+	// synchronously, in the same way as ValidateHttpRequestSyncWithPathItem. If the context is done before validation
+	// completes, validation is abandoned and a single cancellation error is returned.
+	ValidateHttpRequestSyncWithPathItemCtx(ctx context.Context, request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
+
+	// ValidateHttpResponseCtx will validate an *http.Response object against an OpenAPI 3+ document, in the same way
+	// as ValidateHttpResponse. If the context is done before validation completes, validation is abandoned and a
+	// single cancellation error is returned.
+	ValidateHttpResponseCtx(ctx context.Context, request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
+
+	// ValidateHttpRequestResponseCtx will validate both the *http.Request and *http.Response objects against an
+	// OpenAPI 3+ document, in the same way as ValidateHttpRequestResponse. If the context is done before validation
+	// completes, validation is abandoned and a single cancellation error is returned.
+	ValidateHttpRequestResponseCtx(ctx context.Context, request *http.Request, response *http.Response) (bool, []*errors.ValidationError)
+}
+
+// NewValidator will create a new Validator from an OpenAPI 3+ document, or a Swagger 2.0 document. Swagger documents
+// are converted into OpenAPI 3.0 documents (see swagger.ConvertDocument), requests and responses are validated
+// against the converted document, and the document itself is validated against the Swagger 2.0 schema.
 func NewValidator(document libopenapi.Document, opts ...config.Option) (Validator, []error) {
-	m, errs := document.BuildV3Model()
+	model := document
+	if swagger.IsSwagger(document) {
+		converted, err := swagger.ConvertDocument(document)
+		if err != nil {
+			return nil, []error{err}
+		}
+		model = converted
+	}
+	m, errs := model.BuildV3Model()
 	if errs != nil {
 		return nil, []error{errs}
 	}
@@ -86,6 +144,15 @@ func NewValidator(document libopenapi.Document, opts ...config.Option) (Validato
 func NewValidatorFromV3Model(m *v3.Document, opts ...config.Option) Validator {
 	options := config.NewValidationOptions(opts...)
 
+	// build the additional operations of OpenAPI 3.2 path items once, so every validator shares them.
+	helpers.BuildAdditionalOperations(m)
+
+	// build the routing index once, and share it with every validator, unless one was supplied.
+	if options.PathRouter == nil {
+		options.PathRouter = paths.NewRouter(m, opts...)
+		opts = append(slices.Clip(opts), config.WithPathRouter(options.PathRouter))
+	}
+
 	v := &validator{options: options, v3Model: m}
 
 	// create a new parameter validator
@@ -108,6 +175,14 @@ func (v *validator) SetDocument(document libopenapi.Document) {
 	v.document = document
 }
 
+func (v *validator) FindPath(request *http.Request) (*v3.PathItem, []*errors.ValidationError, string) {
+	return paths.FindPathWithOptions(request, v.v3Model, v.options)
+}
+
+func (v *validator) MatchRoute(request *http.Request) (*paths.RouteMatch, []*errors.ValidationError) {
+	return paths.MatchRoute(request, v.v3Model, v.options)
+}
+
 func (v *validator) GetParameterValidator() parameters.ParameterValidator {
 	return v.paramValidator
 }
@@ -132,22 +207,30 @@ func (v *validator) ValidateDocument() (bool, []*errors.ValidationError) {
 			HowToFix:          "Set the document via `SetDocument` before validating",
 		}}
 	}
-	var validationOpts []config.Option
-	if v.options != nil {
-		validationOpts = append(validationOpts, config.WithRegexEngine(v.options.RegexEngine))
-	}
-	return schema_validation.ValidateOpenAPIDocument(v.document, validationOpts...)
+	return schema_validation.ValidateOpenAPIDocument(v.document, config.WithExistingOpts(v.options))
 }
 
 func (v *validator) ValidateHttpResponse(
 	request *http.Request,
 	response *http.Response,
 ) (bool, []*errors.ValidationError) {
+	return v.ValidateHttpResponseCtx(context.Background(), request, response)
+}
+
+func (v *validator) ValidateHttpResponseCtx(
+	ctx context.Context,
+	request *http.Request,
+	response *http.Response,
+) (bool, []*errors.ValidationError) {
+	if err := helpers.ContextError(ctx); err != nil {
+		return false, []*errors.ValidationError{errors.ValidationCancelled(err, request)}
+	}
+
 	var pathItem *v3.PathItem
 	var pathValue string
 	var errs []*errors.ValidationError
 
-	pathItem, errs, pathValue = paths.FindPath(request, v.v3Model, v.options.RegexCache)
+	pathItem, errs, pathValue = paths.FindPathWithOptions(request, v.v3Model, v.options)
 	if pathItem == nil || errs != nil {
 		return false, errs
 	}
@@ -155,7 +238,7 @@ func (v *validator) ValidateHttpResponse(
 	responseBodyValidator := v.responseValidator
 
 	// validate response
-	_, responseErrors := responseBodyValidator.ValidateResponseBodyWithPathItem(request, response, pathItem, pathValue)
+	_, responseErrors := validateResponseBody(ctx, responseBodyValidator, request, response, pathItem, pathValue)
 
 	if len(responseErrors) > 0 {
 		return false, responseErrors
@@ -167,11 +250,23 @@ func (v *validator) ValidateHttpRequestResponse(
 	request *http.Request,
 	response *http.Response,
 ) (bool, []*errors.ValidationError) {
+	return v.ValidateHttpRequestResponseCtx(context.Background(), request, response)
+}
+
+func (v *validator) ValidateHttpRequestResponseCtx(
+	ctx context.Context,
+	request *http.Request,
+	response *http.Response,
+) (bool, []*errors.ValidationError) {
+	if err := helpers.ContextError(ctx); err != nil {
+		return false, []*errors.ValidationError{errors.ValidationCancelled(err, request)}
+	}
+
 	var pathItem *v3.PathItem
 	var pathValue string
 	var errs []*errors.ValidationError
 
-	pathItem, errs, pathValue = paths.FindPath(request, v.v3Model, v.options.RegexCache)
+	pathItem, errs, pathValue = paths.FindPathWithOptions(request, v.v3Model, v.options)
 	if pathItem == nil || errs != nil {
 		return false, errs
 	}
@@ -179,8 +274,14 @@ func (v *validator) ValidateHttpRequestResponse(
 	responseBodyValidator := v.responseValidator
 
 	// validate request and response
-	_, requestErrors := v.ValidateHttpRequestWithPathItem(request, pathItem, pathValue)
-	_, responseErrors := responseBodyValidator.ValidateResponseBodyWithPathItem(request, response, pathItem, pathValue)
+	_, requestErrors := v.ValidateHttpRequestWithPathItemCtx(ctx, request, pathItem, pathValue)
+	if errors.ContainsCancellation(requestErrors) {
+		return false, requestErrors
+	}
+	_, responseErrors := validateResponseBody(ctx, responseBodyValidator, request, response, pathItem, pathValue)
+	if errors.ContainsCancellation(responseErrors) {
+		return false, responseErrors
+	}
 
 	if len(requestErrors) > 0 || len(responseErrors) > 0 {
 		return false, append(requestErrors, responseErrors...)
@@ -189,30 +290,65 @@ func (v *validator) ValidateHttpRequestResponse(
 }
 
 func (v *validator) ValidateHttpRequest(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.v3Model, v.options.RegexCache)
+	return v.ValidateHttpRequestCtx(context.Background(), request)
+}
+
+func (v *validator) ValidateHttpRequestCtx(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError) {
+	if err := helpers.ContextError(ctx); err != nil {
+		return false, []*errors.ValidationError{errors.ValidationCancelled(err, request)}
+	}
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.v3Model, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
-	return v.ValidateHttpRequestWithPathItem(request, pathItem, foundPath)
+	return v.ValidateHttpRequestWithPathItemCtx(ctx, request, pathItem, foundPath)
 }
 
 func (v *validator) ValidateHttpRequestWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateHttpRequestWithPathItemCtx(context.Background(), request, pathItem, pathValue)
+}
+
+func (v *validator) ValidateHttpRequestWithPathItemCtx(ctx context.Context, request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	if err := helpers.ContextError(ctx); err != nil {
+		return false, cancelled(err, request, pathValue)
+	}
+	if ctx == nil {
+		// a nil context is never done.
+		ctx = context.Background()
+	}
+
 	// create a new parameter validator
 	paramValidator := v.paramValidator
 
 	// create a new request body validator
 	reqBodyValidator := v.requestValidator
 
-	// create some channels to handle async validation
-	doneChan := make(chan struct{})
+	// create some channels to handle async validation. every send selects on the context as well, so when the
+	// context is done, nothing is left blocked and all goroutines are free to exit. they are waited for before
+	// returning, as body validation replaces the body of the request.
+	var running sync.WaitGroup
+	doneChan := make(chan struct{}, 1)
 	errChan := make(chan []*errors.ValidationError)
 	controlChan := make(chan struct{})
 
+	sendErrors := func(errorChan chan []*errors.ValidationError, vErrs []*errors.ValidationError) {
+		select {
+		case errorChan <- vErrs:
+		case <-ctx.Done():
+		}
+	}
+	sendControl := func(control chan struct{}) {
+		select {
+		case control <- struct{}{}:
+		case <-ctx.Done():
+		}
+	}
+
 	// async param validation function.
 	parameterValidationFunc := func(control chan struct{}, errorChan chan []*errors.ValidationError) {
 		paramErrs := make(chan []*errors.ValidationError)
 		paramControlChan := make(chan struct{})
-		paramFunctionControlChan := make(chan struct{})
+		paramFunctionControlChan := make(chan struct{}, 1)
 		var paramValidationErrors []*errors.ValidationError
 
 		validations := []validationFunction{
@@ -236,6 +372,8 @@ func (v *validator) ValidateHttpRequestWithPathItem(request *http.Request, pathI
 						paramFunctionControlChan <- struct{}{}
 						return
 					}
+				case <-ctx.Done():
+					return
 				}
 			}
 		}
@@ -245,33 +383,39 @@ func (v *validator) ValidateHttpRequestWithPathItem(request *http.Request, pathI
 			errorChan chan []*errors.ValidationError,
 			validatorFunc validationFunction,
 		) {
+			defer running.Done()
 			valid, pErrs := validatorFunc(request, pathItem, pathValue)
 			if !valid {
-				errorChan <- pErrs
+				sendErrors(errorChan, pErrs)
 			}
-			control <- struct{}{}
+			sendControl(control)
 		}
 		go paramListener(paramControlChan, paramErrs)
+		running.Add(len(validations))
 		for i := range validations {
 			go validateParamFunction(paramControlChan, paramErrs, validations[i])
 		}
 
 		// wait for all the validations to complete
-		<-paramFunctionControlChan
+		select {
+		case <-paramFunctionControlChan:
+		case <-ctx.Done():
+			return
+		}
 		if len(paramValidationErrors) > 0 {
-			errorChan <- paramValidationErrors
+			sendErrors(errorChan, paramValidationErrors)
 		}
 
 		// let runValidation know we are done with this part.
-		controlChan <- struct{}{}
+		sendControl(controlChan)
 	}
 
 	requestBodyValidationFunc := func(control chan struct{}, errorChan chan []*errors.ValidationError) {
-		valid, pErrs := reqBodyValidator.ValidateRequestBodyWithPathItem(request, pathItem, pathValue)
+		valid, pErrs := validateRequestBody(ctx, reqBodyValidator, request, pathItem, pathValue)
 		if !valid {
-			errorChan <- pErrs
+			sendErrors(errorChan, pErrs)
 		}
-		control <- struct{}{}
+		sendControl(control)
 	}
 
 	// build async functions
@@ -283,27 +427,50 @@ func (v *validator) ValidateHttpRequestWithPathItem(request *http.Request, pathI
 	var validationErrors []*errors.ValidationError
 
 	// sit and wait for everything to report back.
-	go runValidation(controlChan, doneChan, errChan, &validationErrors, len(asyncFunctions))
+	go runValidation(ctx, controlChan, doneChan, errChan, &validationErrors, len(asyncFunctions))
 
 	// run async functions
+	running.Add(len(asyncFunctions))
 	for i := range asyncFunctions {
-		go asyncFunctions[i](controlChan, errChan)
+		go func(asyncFunction validationFunctionAsync) {
+			defer running.Done()
+			asyncFunction(controlChan, errChan)
+		}(asyncFunctions[i])
 	}
 
-	// wait for all the validations to complete
-	<-doneChan
+	// wait for all the validations to complete, or for the context to be done.
+	select {
+	case <-doneChan:
+	case <-ctx.Done():
+		running.Wait()
+		return false, cancelled(helpers.ContextError(ctx), request, pathValue)
+	}
+	if errors.ContainsCancellation(validationErrors) {
+		return false, cancelled(helpers.ContextError(ctx), request, pathValue)
+	}
 	return len(validationErrors) == 0, validationErrors
 }
 
 func (v *validator) ValidateHttpRequestSync(request *http.Request) (bool, []*errors.ValidationError) {
-	pathItem, errs, foundPath := paths.FindPath(request, v.v3Model, v.options.RegexCache)
+	return v.ValidateHttpRequestSyncCtx(context.Background(), request)
+}
+
+func (v *validator) ValidateHttpRequestSyncCtx(ctx context.Context, request *http.Request) (bool, []*errors.ValidationError) {
+	if err := helpers.ContextError(ctx); err != nil {
+		return false, []*errors.ValidationError{errors.ValidationCancelled(err, request)}
+	}
+	pathItem, errs, foundPath := paths.FindPathWithOptions(request, v.v3Model, v.options)
 	if len(errs) > 0 {
 		return false, errs
 	}
-	return v.ValidateHttpRequestSyncWithPathItem(request, pathItem, foundPath)
+	return v.ValidateHttpRequestSyncWithPathItemCtx(ctx, request, pathItem, foundPath)
 }
 
 func (v *validator) ValidateHttpRequestSyncWithPathItem(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
+	return v.ValidateHttpRequestSyncWithPathItemCtx(context.Background(), request, pathItem, pathValue)
+}
+
+func (v *validator) ValidateHttpRequestSyncWithPathItemCtx(ctx context.Context, request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError) {
 	// create a new parameter validator
 	paramValidator := v.paramValidator
 
@@ -320,13 +487,20 @@ func (v *validator) ValidateHttpRequestSyncWithPathItem(request *http.Request, p
 		paramValidator.ValidateQueryParamsWithPathItem,
 		paramValidator.ValidateSecurityWithPathItem,
 	} {
+		// check the context between each validation, there is no point continuing if nobody is listening.
+		if err := helpers.ContextError(ctx); err != nil {
+			return false, cancelled(err, request, pathValue)
+		}
 		valid, pErrs := validateFunc(request, pathItem, pathValue)
 		if !valid {
 			paramValidationErrors = append(paramValidationErrors, pErrs...)
 		}
 	}
 
-	valid, pErrs := reqBodyValidator.ValidateRequestBodyWithPathItem(request, pathItem, pathValue)
+	valid, pErrs := validateRequestBody(ctx, reqBodyValidator, request, pathItem, pathValue)
+	if errors.ContainsCancellation(pErrs) {
+		return false, cancelled(helpers.ContextError(ctx), request, pathValue)
+	}
 	if !valid {
 		paramValidationErrors = append(paramValidationErrors, pErrs...)
 	}
@@ -344,7 +518,7 @@ type validator struct {
 	responseValidator responses.ResponseBodyValidator
 }
 
-func runValidation(control, doneChan chan struct{},
+func runValidation(ctx context.Context, control, doneChan chan struct{},
 	errorChan chan []*errors.ValidationError,
 	validationErrors *[]*errors.ValidationError,
 	total int,
@@ -363,10 +537,39 @@ func runValidation(control, doneChan chan struct{},
 				doneChan <- struct{}{}
 				return
 			}
+		case <-ctx.Done():
+			return
 		}
 	}
 }
 
+// validateRequestBody validates a request body with the context, when the request body validator accepts one.
+func validateRequestBody(ctx context.Context, bodyValidator requests.RequestBodyValidator, request *http.Request,
+	pathItem *v3.PathItem, pathValue string,
+) (bool, []*errors.ValidationError) {
+	if contextValidator, ok := bodyValidator.(requests.ContextRequestBodyValidator); ok {
+		return contextValidator.ValidateRequestBodyWithPathItemCtx(ctx, request, pathItem, pathValue)
+	}
+	return bodyValidator.ValidateRequestBodyWithPathItem(request, pathItem, pathValue)
+}
+
+// validateResponseBody validates a response body with the context, when the response body validator accepts one.
+func validateResponseBody(ctx context.Context, bodyValidator responses.ResponseBodyValidator, request *http.Request,
+	response *http.Response, pathItem *v3.PathItem, pathValue string,
+) (bool, []*errors.ValidationError) {
+	if contextValidator, ok := bodyValidator.(responses.ContextResponseBodyValidator); ok {
+		return contextValidator.ValidateResponseBodyWithPathItemCtx(ctx, request, response, pathItem, pathValue)
+	}
+	return bodyValidator.ValidateResponseBodyWithPathItem(request, response, pathItem, pathValue)
+}
+
+// cancelled creates a single cancellation error for a request that was abandoned because the context was done.
+func cancelled(err error, request *http.Request, pathValue string) []*errors.ValidationError {
+	validationErrors := []*errors.ValidationError{errors.ValidationCancelled(err, request)}
+	errors.PopulateValidationErrors(validationErrors, request, pathValue)
+	return validationErrors
+}
+
 type (
 	validationFunction      func(request *http.Request, pathItem *v3.PathItem, pathValue string) (bool, []*errors.ValidationError)
 	validationFunctionAsync func(control chan struct{}, errorChan chan []*errors.ValidationError)
@@ -390,7 +593,7 @@ func warmSchemaCaches(
 		pathItem := pathPair.Value()
 
 		// Get all operations for this path (handles all HTTP methods including OpenAPI 3.2+ extensions)
-		operations := pathItem.GetOperations()
+		operations := helpers.ExtractOperations(pathItem)
 		if operations == nil {
 			continue
 		}
@@ -406,7 +609,7 @@ func warmSchemaCaches(
 				for contentPair := operation.RequestBody.Content.First(); contentPair != nil; contentPair = contentPair.Next() {
 					mediaType := contentPair.Value()
 					if mediaType.Schema != nil {
-						warmMediaTypeSchema(mediaType, schemaCache, options)
+						warmMediaTypeSchema(mediaType, schemaCache, options, helpers.RequestDirection)
 					}
 				}
 			}
@@ -421,7 +624,7 @@ func warmSchemaCaches(
 							for contentPair := response.Content.First(); contentPair != nil; contentPair = contentPair.Next() {
 								mediaType := contentPair.Value()
 								if mediaType.Schema != nil {
-									warmMediaTypeSchema(mediaType, schemaCache, options)
+									warmMediaTypeSchema(mediaType, schemaCache, options, helpers.ResponseDirection)
 								}
 							}
 						}
@@ -433,7 +636,7 @@ func warmSchemaCaches(
 					for contentPair := operation.Responses.Default.Content.First(); contentPair != nil; contentPair = contentPair.Next() {
 						mediaType := contentPair.Value()
 						if mediaType.Schema != nil {
-							warmMediaTypeSchema(mediaType, schemaCache, options)
+							warmMediaTypeSchema(mediaType, schemaCache, options, helpers.ResponseDirection)
 						}
 					}
 				}
@@ -460,30 +663,59 @@ func warmSchemaCaches(
 	}
 }
 
-// warmMediaTypeSchema warms the cache for a media type schema
-func warmMediaTypeSchema(mediaType *v3.MediaType, schemaCache cache.SchemaCache, options *config.ValidationOptions) {
+// warmMediaTypeSchema warms the cache for a media type schema, used by request or response bodies
+func warmMediaTypeSchema(mediaType *v3.MediaType, schemaCache cache.SchemaCache, options *config.ValidationOptions,
+	direction helpers.Direction,
+) {
 	if mediaType != nil && mediaType.Schema != nil {
 		hash := mediaType.GoLow().Schema.Value.Hash()
 
-		if _, exists := schemaCache.Load(hash); !exists {
+		entry, exists := schemaCache.Load(hash)
+		if !exists || entry == nil {
 			schema := mediaType.Schema.Schema()
-			if schema != nil {
-				renderedInline, _ := schema.RenderInline()
-				referenceSchema := string(renderedInline)
-				renderedJSON, _ := utils.ConvertYAMLtoJSON(renderedInline)
-				if len(renderedInline) > 0 {
-					compiledSchema, _ := helpers.NewCompiledSchema(fmt.Sprintf("%x", hash), renderedJSON, options)
-
-					schemaCache.Store(hash, &cache.SchemaCacheEntry{
-						Schema:          schema,
-						RenderedInline:  renderedInline,
-						ReferenceSchema: referenceSchema,
-						RenderedJSON:    renderedJSON,
-						CompiledSchema:  compiledSchema,
-					})
-				}
+			if schema == nil {
+				return
+			}
+			renderedInline, _ := schema.RenderInline()
+			if len(renderedInline) == 0 {
+				return
 			}
+			renderedJSON, _ := utils.ConvertYAMLtoJSON(renderedInline)
+			renderedJSON = helpers.AnnotateDiscriminators(schema, renderedJSON)
+			compiledSchema, _ := helpers.NewCompiledSchema(fmt.Sprintf("%x", hash), renderedJSON, options)
+			entry = &cache.SchemaCacheEntry{
+				Schema:          schema,
+				RenderedInline:  renderedInline,
+				ReferenceSchema: string(renderedInline),
+				RenderedJSON:    renderedJSON,
+				CompiledSchema:  compiledSchema,
+			}
+		} else if (direction == helpers.RequestDirection && entry.RequestSchema != nil) ||
+			(direction == helpers.ResponseDirection && entry.ResponseSchema != nil) {
+			return
+		} else {
+			copied := *entry
+			entry = &copied
+		}
+		if entry.CompiledSchema == nil {
+			schemaCache.Store(hash, entry)
+			return
+		}
+
+		// bodies are compiled for their direction, readOnly and writeOnly properties are not always required, and
+		// object schemas may be closed by strict additionalProperties.
+		directional := entry.CompiledSchema
+		transformed, relaxed := helpers.TransformSchemaForDirection(entry.RenderedJSON, direction)
+		if relaxed || helpers.StrictPropertiesApply(options, direction) {
+			directional, _ = helpers.NewCompiledSchemaForDirection(fmt.Sprintf("%x", hash), transformed, options, 3.1, direction)
+		}
+		switch direction {
+		case helpers.RequestDirection:
+			entry.RequestSchema = directional
+		case helpers.ResponseDirection:
+			entry.ResponseSchema = directional
 		}
+		schemaCache.Store(hash, entry)
 	}
 }
 
@@ -84,6 +84,7 @@ func ExampleNewValidator_validateHttpRequest() {
 		}
 	}
 	// Output: Type: security, Failure: API Key api_key not found in header
+	// Type: security, Failure: Bearer token for 'petstore_auth' scheme not found
 	// Type: parameter, Failure: Path parameter 'petId' is not a valid integer
 }
 
@@ -147,6 +148,7 @@ func ExampleNewValidator_validateHttpRequestResponse() {
 
 	// 6. Create a new *http.Request (normally, this would be where the host application will pass in the request)
 	request, _ := http.NewRequest(http.MethodGet, "/pet/findByStatus?status=sold", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 
 	// 7. Simulate a request/response, in this case the contract returns a 200 with an array of pets.
 	// Normally, this would be where the host application would pass in the response.
@@ -216,6 +218,7 @@ func ExampleNewValidator_validateHttpResponse() {
 
 	// 6. Create a new *http.Request (normally, this would be where the host application will pass in the request)
 	request, _ := http.NewRequest(http.MethodGet, "/pet/findByStatus?status=sold", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 
 	// 7. Simulate a request/response, in this case the contract returns a 200 with an array of pets.
 	// Normally, this would be where the host application would pass in the response.
@@ -363,8 +366,7 @@ paths:
 	// Normally, this would be where the host application would pass in the response.
 	recorder := httptest.NewRecorder()
 	handler := func(w http.ResponseWriter, r *http.Request) {
-		// set return content type.
-		w.Header().Set("Chicken-Nuggets", "I am a chicken nugget, and not an integer")
+		// the optional header is not set, optional headers are only validated when they are present.
 		w.WriteHeader(http.StatusOK)
 		_, _ = w.Write(nil)
 	}
@@ -5,6 +5,7 @@ package validator
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,9 +13,11 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 	"unicode"
 
 	"github.com/dlclark/regexp2"
@@ -27,7 +30,9 @@ import (
 
 	"github.com/pb33f/libopenapi-validator/cache"
 	"github.com/pb33f/libopenapi-validator/config"
+	"github.com/pb33f/libopenapi-validator/errors"
 	"github.com/pb33f/libopenapi-validator/helpers"
+	"github.com/pb33f/libopenapi-validator/paths"
 )
 
 func TestNewValidator(t *testing.T) {
@@ -145,6 +150,15 @@ func TestNewValidator_ValidateDocument(t *testing.T) {
 	assert.Len(t, errs, 0)
 }
 
+func TestNewValidator_ValidateDocument_SchemaPath(t *testing.T) {
+	doc, _ := libopenapi.NewDocument(petstoreBytes)
+	v, _ := NewValidator(doc, config.WithOpenAPISchemaPath(filepath.Join(t.TempDir(), "missing.json")))
+	valid, errs := v.ValidateDocument()
+	assert.False(t, valid)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "OpenAPI document schema cannot be loaded", errs[0].Message)
+}
+
 type dlclarkRegexp regexp2.Regexp
 
 func (re *dlclarkRegexp) MatchString(s string) bool {
@@ -384,7 +398,10 @@ paths:
 }
 
 func TestNewValidator_BadDoc(t *testing.T) {
-	spec := `swagger: 2.0`
+	spec := `openapi: 3.1.0
+paths:
+  /burgers:
+    $ref: '#/nope'`
 
 	doc, _ := libopenapi.NewDocument([]byte(spec))
 
@@ -914,6 +931,7 @@ func TestNewValidator_PetStore_MissingContentType(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPut, "https://hyperspace-superherbs.com/pet",
 		bytes.NewBuffer(bodyBytes))
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -965,6 +983,7 @@ func TestNewValidator_PetStore_PetPost200_Valid_PathPreset(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPut, "https://hyperspace-superherbs.com/pet",
 		bytes.NewBuffer(bodyBytes))
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1010,6 +1029,7 @@ func TestNewValidator_PetStore_PetPost200_Valid(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPut, "https://hyperspace-superherbs.com/pet",
 		bytes.NewBuffer(bodyBytes))
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1054,6 +1074,7 @@ func TestNewValidator_PetStore_PetPost200_Invalid(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPost, "https://hyperspace-superherbs.com/pet",
 		bytes.NewBuffer(bodyBytes))
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1108,6 +1129,7 @@ func TestNewValidator_PetStore_PetFindByStatusGet200_Valid(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodGet,
 		"https://hyperspace-superherbs.com/pet/findByStatus?status=sold", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1153,6 +1175,7 @@ func TestNewValidator_PetStore_PetFindByStatusGet200_BadEnum(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodGet,
 		"https://hyperspace-superherbs.com/pet/findByStatus?status=invalidEnum", nil) // enum is invalid
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response, in this case the contract returns a 200 with a pet
@@ -1201,6 +1224,7 @@ func TestNewValidator_PetStore_PetFindByTagsGet200_Valid(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodGet,
 		"https://hyperspace-superherbs.com/pet/findByTags?tags=fuzzy&tags=wuzzy", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1246,6 +1270,7 @@ func TestNewValidator_PetStore_PetFindByTagsGet200_InvalidExplode(t *testing.T)
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodGet,
 		"https://hyperspace-superherbs.com/pet/findByTags?tags=fuzzy,wuzzy", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set("Content-Type", "application/json")
 
 	// simulate a request/response
@@ -1353,9 +1378,10 @@ func TestNewValidator_PetStore_PetGet200_PathNotFound(t *testing.T) {
 	valid, errors := v.ValidateHttpRequestResponse(request, res.Result())
 
 	assert.False(t, valid)
-	assert.Len(t, errors, 2)
+	assert.Len(t, errors, 3)
 	assert.Equal(t, "API Key api_key not found in header", errors[0].Message)
-	assert.Equal(t, "Path parameter 'petId' is not a valid integer", errors[1].Message)
+	assert.Equal(t, "Bearer token for 'petstore_auth' scheme not found", errors[1].Message)
+	assert.Equal(t, "Path parameter 'petId' is not a valid integer", errors[2].Message)
 }
 
 func TestNewValidator_PetStore_PetGet200(t *testing.T) {
@@ -1462,6 +1488,7 @@ func TestNewValidator_PetStore_PetWithIdPost200_Missing200(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPost,
 		"https://hyperspace-superherbs.com/pet/112233?name=peter&query=thing", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1492,6 +1519,7 @@ func TestNewValidator_PetStore_UploadImage200_InvalidRequestBodyType(t *testing.
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPost,
 		"https://hyperspace-superherbs.com/pet/112233/uploadImage?additionalMetadata=blem", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1522,6 +1550,7 @@ func TestNewValidator_PetStore_UploadImage200_Valid(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPost,
 		"https://hyperspace-superherbs.com/pet/112233/uploadImage?additionalMetadata=blem", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set(helpers.ContentTypeHeader, "application/octet-stream")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -1563,6 +1592,7 @@ func TestNewValidator_PetStore_UploadImage200_InvalidAPIResponse(t *testing.T) {
 	// create a new put request
 	request, _ := http.NewRequest(http.MethodPost,
 		"https://hyperspace-superherbs.com/pet/112233/uploadImage?additionalMetadata=blem", nil)
+	request.Header.Set(helpers.AuthorizationHeader, "Bearer pb33f")
 	request.Header.Set(helpers.ContentTypeHeader, "application/octet-stream")
 
 	// simulate a request/response, in this case the contract returns a 200 with the pet we just created.
@@ -2019,6 +2049,75 @@ func TestCacheWarming_PopulatesCache(t *testing.T) {
 	assert.Greater(t, count, 0, "Schema cache should have entries from request and response bodies")
 }
 
+func TestCacheWarming_AccessModes(t *testing.T) {
+	// the same schema describes the request and the response, each direction relaxes its own required properties.
+	spec := `openapi: 3.1.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        '201':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, name, password]
+      properties:
+        id:
+          type: integer
+          readOnly: true
+        name:
+          type: string
+        password:
+          type: string
+          writeOnly: true`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc)
+	require.Nil(t, errs)
+
+	validator := v.(*validator)
+	validator.options.SchemaCache.Range(func(key [32]byte, value *cache.SchemaCacheEntry) bool {
+		assert.NotNil(t, value.RequestSchema)
+		assert.NotNil(t, value.ResponseSchema)
+		assert.NotSame(t, value.RequestSchema, value.ResponseSchema)
+		return true
+	})
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/users",
+		bytes.NewBufferString(`{"name": "dave", "password": "hunter2"}`))
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+
+	valid, validationErrs := v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	response := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{helpers.ContentTypeHeader: []string{helpers.JSONContentType}},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"id": 1, "name": "dave"}`)),
+	}
+	valid, validationErrs = v.ValidateHttpResponse(request, response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	response.Body = io.NopCloser(bytes.NewBufferString(`{"id": 1, "name": "dave", "password": "hunter2"}`))
+	valid, validationErrs = v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Equal(t, helpers.WriteOnly, validationErrs[0].ValidationSubType)
+}
+
 func TestCacheWarming_EdgeCases(t *testing.T) {
 	// Test nil document
 	warmSchemaCaches(nil, nil)
@@ -2254,3 +2353,595 @@ paths:
 	})
 	assert.Greater(t, count, 0, "Schema cache should have entries from path-level parameters")
 }
+
+// slowBurgerSpec declares a request and response body with a custom 'slow' format, used to simulate an expensive
+// schema evaluation that can be held open for as long as a test needs.
+const slowBurgerSpec = `openapi: 3.1.0
+paths:
+  /burgers/createBurger:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              properties:
+                name:
+                  type: string
+                  format: slow
+      responses:
+        '200':
+          description: OK
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                    format: slow`
+
+func newSlowBurgerValidator(t *testing.T, release chan struct{}) ContextValidator {
+	doc, err := libopenapi.NewDocument([]byte(slowBurgerSpec))
+	require.NoError(t, err)
+
+	v, errs := NewValidator(doc,
+		config.WithFormatAssertions(),
+		config.WithCustomFormat("slow", func(v any) error {
+			<-release
+			return nil
+		}))
+	require.Empty(t, errs)
+	require.Implements(t, (*ContextValidator)(nil), v)
+	return v.(ContextValidator)
+}
+
+func newBurgerRequest() *http.Request {
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/burgers/createBurger",
+		bytes.NewBufferString(`{"name":"Big Mac"}`))
+	request.Header.Set("Content-Type", "application/json")
+	return request
+}
+
+func newBurgerResponse() *http.Response {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"name":"Big Mac"}`)),
+	}
+}
+
+func TestNewValidator_ValidateHttpRequestCtx_Valid(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	v := newSlowBurgerValidator(t, release)
+
+	valid, errs := v.ValidateHttpRequestCtx(context.Background(), newBurgerRequest())
+	assert.True(t, valid)
+	assert.Len(t, errs, 0)
+
+	valid, errs = v.ValidateHttpRequestSyncCtx(context.Background(), newBurgerRequest())
+	assert.True(t, valid)
+	assert.Len(t, errs, 0)
+
+	valid, errs = v.ValidateHttpResponseCtx(context.Background(), newBurgerRequest(), newBurgerResponse())
+	assert.True(t, valid)
+	assert.Len(t, errs, 0)
+
+	valid, errs = v.ValidateHttpRequestResponseCtx(context.Background(), newBurgerRequest(), newBurgerResponse())
+	assert.True(t, valid)
+	assert.Len(t, errs, 0)
+}
+
+func TestNewValidator_ValidateHttpRequestCtx_NilContext(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	v := newSlowBurgerValidator(t, release)
+
+	request := newBurgerRequest()
+	pathItem, errs, pathValue := v.FindPath(request)
+	require.Empty(t, errs)
+
+	//nolint:staticcheck // a nil context is explicitly supported.
+	checks := map[string]func() (bool, []*errors.ValidationError){
+		"request": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestCtx(nil, newBurgerRequest())
+		},
+		"requestWithPathItem": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestWithPathItemCtx(nil, newBurgerRequest(), pathItem, pathValue)
+		},
+		"requestSync": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestSyncCtx(nil, newBurgerRequest())
+		},
+		"response": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpResponseCtx(nil, newBurgerRequest(), newBurgerResponse())
+		},
+		"requestResponse": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestResponseCtx(nil, newBurgerRequest(), newBurgerResponse())
+		},
+	}
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			valid, errs := check()
+			assert.True(t, valid)
+			assert.Empty(t, errs)
+		})
+	}
+}
+
+func TestNewValidator_ValidateHttpRequestCtx_AlreadyCancelled(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	v := newSlowBurgerValidator(t, release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	checks := map[string]func() (bool, []*errors.ValidationError){
+		"request": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestCtx(ctx, newBurgerRequest())
+		},
+		"requestSync": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestSyncCtx(ctx, newBurgerRequest())
+		},
+		"response": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpResponseCtx(ctx, newBurgerRequest(), newBurgerResponse())
+		},
+		"requestResponse": func() (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestResponseCtx(ctx, newBurgerRequest(), newBurgerResponse())
+		},
+	}
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			valid, errs := check()
+			assert.False(t, valid)
+			require.Len(t, errs, 1)
+			assert.True(t, errs[0].IsCancellationError())
+			assert.Equal(t, helpers.ContextCancelled, errs[0].ValidationSubType)
+			assert.ErrorIs(t, errs[0].Context.(error), context.Canceled)
+		})
+	}
+}
+
+func TestNewValidator_ValidateHttpRequestCtx_DeadlineDuringSchemaEvaluation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	v := newSlowBurgerValidator(t, release)
+
+	checks := map[string]func(ctx context.Context) (bool, []*errors.ValidationError){
+		"request": func(ctx context.Context) (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestCtx(ctx, newBurgerRequest())
+		},
+		"requestSync": func(ctx context.Context) (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestSyncCtx(ctx, newBurgerRequest())
+		},
+		"response": func(ctx context.Context) (bool, []*errors.ValidationError) {
+			return v.ValidateHttpResponseCtx(ctx, newBurgerRequest(), newBurgerResponse())
+		},
+		"requestResponse": func(ctx context.Context) (bool, []*errors.ValidationError) {
+			return v.ValidateHttpRequestResponseCtx(ctx, newBurgerRequest(), newBurgerResponse())
+		},
+	}
+	for name, check := range checks {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			// the 'slow' format blocks until release is closed, so only the deadline can end validation.
+			valid, errs := check(ctx)
+			assert.False(t, valid)
+			require.Len(t, errs, 1)
+			assert.True(t, errs[0].IsCancellationError())
+			assert.Equal(t, helpers.ContextDeadlineExceeded, errs[0].ValidationSubType)
+			assert.Equal(t, "/burgers/createBurger", errs[0].RequestPath)
+			assert.Equal(t, http.MethodPost, errs[0].RequestMethod)
+		})
+	}
+}
+
+func TestNewValidator_ValidateHttpRequestWithPathItemCtx_CancelledAsync(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	v := newSlowBurgerValidator(t, release)
+
+	request := newBurgerRequest()
+	pathItem, errs, pathValue := paths.FindPath(request, v.(*validator).v3Model, nil)
+	require.Empty(t, errs)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	valid, vErrs := v.ValidateHttpRequestWithPathItemCtx(ctx, request, pathItem, pathValue)
+	assert.False(t, valid)
+	require.Len(t, vErrs, 1)
+	assert.True(t, vErrs[0].IsCancellationError())
+	assert.Equal(t, helpers.ContextCancelled, vErrs[0].ValidationSubType)
+	assert.Equal(t, "/burgers/createBurger", vErrs[0].SpecPath)
+
+	// the validation goroutines have returned, the request is left alone from here on
+	body, _ := io.ReadAll(request.Body)
+	assert.JSONEq(t, `{"name":"Big Mac"}`, string(body))
+	request.Body = io.NopCloser(bytes.NewReader(body))
+}
+
+func TestNewValidator_FindPath(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	v := newSlowBurgerValidator(t, release)
+	assert.IsType(t, &paths.Router{}, v.(*validator).options.PathRouter)
+
+	pathItem, errs, pathValue := v.FindPath(newBurgerRequest())
+	require.Empty(t, errs)
+	assert.NotNil(t, pathItem.Post)
+	assert.Equal(t, "/burgers/createBurger", pathValue)
+
+	valid, vErrs := v.ValidateHttpRequestWithPathItem(newBurgerRequest(), pathItem, pathValue)
+	assert.True(t, valid)
+	assert.Empty(t, vErrs)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/pizza", nil)
+	pathItem, errs, _ = v.FindPath(request)
+	assert.Nil(t, pathItem)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].IsPathMissingError())
+}
+
+func TestNewValidator_MatchRoute(t *testing.T) {
+	spec := `openapi: 3.1.0
+servers:
+  - url: https://things.com/api
+paths:
+  /burgers/{burgerId}/sauces/{sauce}:
+    get:
+      operationId: getSauce
+      parameters:
+        - name: burgerId
+          in: path
+          required: true
+          schema:
+            type: integer
+        - name: sauce
+          in: path
+          required: true
+          schema:
+            type: string
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v, _ := NewValidator(doc)
+
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/api/burgers/42/sauces/hot%20sauce", nil)
+	match, errs := v.MatchRoute(request)
+	require.Empty(t, errs)
+	require.NotNil(t, match)
+	assert.Equal(t, "getSauce", match.Operation.OperationId)
+	assert.Equal(t, "/burgers/{burgerId}/sauces/{sauce}", match.Template)
+	assert.Equal(t, "https://things.com/api", match.Server.URL)
+	assert.Equal(t, map[string]any{"burgerId": int64(42), "sauce": "hot sauce"}, match.PathParams)
+	assert.Equal(t, map[string]string{"burgerId": "42", "sauce": "hot%20sauce"}, match.RawPathParams)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/api/pizza", nil)
+	match, errs = v.MatchRoute(request)
+	assert.Nil(t, match)
+	require.Len(t, errs, 1)
+	assert.True(t, errs[0].IsPathMissingError())
+}
+
+func TestStrictAdditionalProperties_RequestsOnly(t *testing.T) {
+	// the same schema describes the request and the response, only requests are strict.
+	spec := `openapi: 3.1.0
+paths:
+  /users:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        '201':
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        name:
+          type: string`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc, config.WithStrictAdditionalProperties(config.StrictPropertiesRequests))
+	require.Nil(t, errs)
+
+	request, _ := http.NewRequest(http.MethodPost, "https://things.com/users",
+		bytes.NewBufferString(`{"name": "dave", "nickname": "pb33f"}`))
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+
+	valid, validationErrs := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	require.NotEmpty(t, validationErrs[0].SchemaValidationErrors)
+	assert.Contains(t, validationErrs[0].SchemaValidationErrors[0].Reason, "nickname")
+
+	response := &http.Response{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{helpers.ContentTypeHeader: []string{helpers.JSONContentType}},
+		Body:       io.NopCloser(bytes.NewBufferString(`{"name": "dave", "nickname": "pb33f"}`)),
+	}
+	valid, validationErrs = v.ValidateHttpResponse(request, response)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+}
+
+func TestNewValidator_OpenAPI32(t *testing.T) {
+	spec := `openapi: 3.2.0
+info:
+  title: Burger Shop
+  version: 1.0.0
+paths:
+  /burgers:
+    query:
+      parameters:
+        - name: search
+          in: querystring
+          required: true
+          content:
+            application/x-www-form-urlencoded:
+              schema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+                  limit:
+                    type: integer
+                    maximum: 10
+      responses:
+        '200':
+          description: a stream of burgers
+          content:
+            application/jsonl:
+              itemSchema:
+                type: object
+                required: [name]
+                properties:
+                  name:
+                    type: string
+    additionalOperations:
+      LINK:
+        requestBody:
+          content:
+            text/event-stream:
+              itemSchema:
+                type: object
+                required: [data]
+                properties:
+                  event:
+                    enum: [order, cancel]
+                  data:
+                    type: string
+        responses:
+          '204':
+            description: linked`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc)
+	require.Nil(t, errs)
+
+	valid, validationErrs := v.ValidateDocument()
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	// QUERY operations and querystring parameters
+	request, _ := http.NewRequest(helpers.MethodQuery, "https://things.com/burgers?name=big+mac&limit=5", nil)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	request, _ = http.NewRequest(helpers.MethodQuery, "https://things.com/burgers?limit=50", nil)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Equal(t, "Query string 'search' failed to validate", validationErrs[0].Message)
+	assert.Len(t, validationErrs[0].SchemaValidationErrors, 2)
+
+	request, _ = http.NewRequest(helpers.MethodQuery, "https://things.com/burgers", nil)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Equal(t, "Query parameter 'search' is missing", validationErrs[0].Message)
+
+	// each item of a stream is validated against the itemSchema
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{helpers.ContentTypeHeader: []string{"application/jsonl"}},
+		Body:       io.NopCloser(bytes.NewBufferString("{\"name\": \"big mac\"}\n{\"name\": 42}\n")),
+	}
+	request, _ = http.NewRequest(helpers.MethodQuery, "https://things.com/burgers?name=big+mac", nil)
+	valid, validationErrs = v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	require.Len(t, validationErrs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "$[1].name", validationErrs[0].SchemaValidationErrors[0].FieldPath)
+
+	// additional operations
+	request, _ = http.NewRequest("LINK", "https://things.com/burgers",
+		bytes.NewBufferString("event: order\ndata: big mac\n\nevent: refund\ndata: fries\n\n"))
+	request.Header.Set(helpers.ContentTypeHeader, helpers.EventStreamContentType)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	require.Len(t, validationErrs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "$[1].event", validationErrs[0].SchemaValidationErrors[0].FieldPath)
+
+	request, _ = http.NewRequest("UNLINK", "https://things.com/burgers", nil)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Equal(t, "UNLINK Path '/burgers' not found", validationErrs[0].Message)
+}
+
+func TestNewValidator_Swagger(t *testing.T) {
+	spec := `swagger: "2.0"
+info:
+  title: Burger Shop
+  version: 1.0.0
+basePath: /v2
+consumes: [application/json]
+produces: [application/json]
+paths:
+  /burgers:
+    get:
+      parameters:
+        - name: ids
+          in: query
+          type: array
+          items:
+            type: integer
+      responses:
+        '200':
+          description: burgers
+          schema:
+            type: array
+            items:
+              $ref: '#/definitions/Burger'
+    post:
+      parameters:
+        - name: burger
+          in: body
+          required: true
+          schema:
+            $ref: '#/definitions/Burger'
+      responses:
+        '201':
+          description: created
+  /orders:
+    post:
+      consumes: [application/x-www-form-urlencoded]
+      parameters:
+        - name: burger
+          in: formData
+          required: true
+          type: string
+        - name: quantity
+          in: formData
+          type: integer
+          minimum: 1
+      responses:
+        '201':
+          description: ordered
+definitions:
+  Burger:
+    type: object
+    required: [name]
+    properties:
+      name:
+        type: string`
+
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+	v, errs := NewValidator(doc)
+	require.Empty(t, errs)
+
+	// the document is validated against the Swagger 2.0 schema
+	valid, validationErrs := v.ValidateDocument()
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	// csv is the default collection format
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/v2/burgers?ids=1,2,3", nil)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrs)
+
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v2/burgers?ids=1,two", nil)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Equal(t, helpers.ParameterValidationQuery, validationErrs[0].ValidationSubType)
+
+	// the body parameter is the request body
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/v2/burgers", bytes.NewBufferString(`{"name": 1}`))
+	request.Header.Set(helpers.ContentTypeHeader, helpers.JSONContentType)
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Equal(t, helpers.RequestBodyValidation, validationErrs[0].ValidationType)
+
+	// formData parameters are the properties of a form
+	request, _ = http.NewRequest(http.MethodPost, "https://things.com/v2/orders",
+		bytes.NewBufferString("burger=big+mac&quantity=0"))
+	request.Header.Set(helpers.ContentTypeHeader, "application/x-www-form-urlencoded")
+	valid, validationErrs = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	require.Len(t, validationErrs[0].SchemaValidationErrors, 1)
+	assert.Equal(t, "$.quantity", validationErrs[0].SchemaValidationErrors[0].FieldPath)
+
+	// responses are produced as json
+	request, _ = http.NewRequest(http.MethodGet, "https://things.com/v2/burgers", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{helpers.ContentTypeHeader: []string{helpers.JSONContentType}},
+		Body:       io.NopCloser(bytes.NewBufferString(`[{"name": "big mac"}, {}]`)),
+	}
+	valid, validationErrs = v.ValidateHttpResponse(request, response)
+	assert.False(t, valid)
+	require.Len(t, validationErrs, 1)
+	assert.Equal(t, helpers.ResponseBodyValidation, validationErrs[0].ValidationType)
+}
+
+func TestNewValidator_AmbiguousPaths(t *testing.T) {
+	spec := `openapi: 3.1.0
+paths:
+  /a/{x}/c:
+    get:
+      responses:
+        "200":
+          description: ok
+  /a/b/{y}:
+    get:
+      responses:
+        "200":
+          description: ok
+`
+	doc, err := libopenapi.NewDocument([]byte(spec))
+	require.NoError(t, err)
+
+	// equally specific templates fail requests by default
+	v, errs := NewValidator(doc)
+	require.Empty(t, errs)
+	request, _ := http.NewRequest(http.MethodGet, "https://things.com/a/b/c", nil)
+	valid, validationErrors := v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.True(t, validationErrors[0].IsPathAmbiguousError())
+
+	// unless asked not to, then the first one in the document is used
+	v, errs = NewValidator(doc, config.WithoutAmbiguousPathErrors())
+	require.Empty(t, errs)
+	valid, validationErrors = v.ValidateHttpRequest(request)
+	assert.True(t, valid)
+	assert.Empty(t, validationErrors)
+
+	match, matchErrors := v.MatchRoute(request)
+	require.NotNil(t, match)
+	assert.Empty(t, matchErrors)
+	assert.Equal(t, "/a/{x}/c", match.Template)
+	assert.Equal(t, []string{"/a/b/{y}"}, match.Ambiguous)
+
+	v, errs = NewValidator(doc, config.WithAmbiguousPathErrors())
+	require.Empty(t, errs)
+	valid, validationErrors = v.ValidateHttpRequest(request)
+	assert.False(t, valid)
+	require.Len(t, validationErrors, 1)
+	assert.True(t, validationErrors[0].IsPathAmbiguousError())
+}